@@ -0,0 +1,155 @@
+package worlds
+
+import (
+	"github.com/irmine/worlds/blocks"
+	"github.com/irmine/worlds/chunks"
+	"math"
+)
+
+// BlockBehavior implements the gameplay of a block, such as doors opening, crops growing or sand falling.
+// The hooks of a behavior get called by the dimension for every block with the block ID the behavior is registered to.
+// X, Y and Z are always world coordinates.
+type BlockBehavior interface {
+	// OnPlace gets called after the block got placed.
+	OnPlace(dimension *Dimension, x, y, z int, block blocks.Block)
+	// OnBreak gets called after the block got replaced by another block, with the ID and data the block had.
+	OnBreak(dimension *Dimension, x, y, z int, id, data byte)
+	// OnNeighborUpdate gets called after a neighbouring block changed, with the world position of the neighbour.
+	OnNeighborUpdate(dimension *Dimension, x, y, z, neighbourX, neighbourY, neighbourZ int)
+	// OnRandomTick gets called when the block gets picked to be random ticked.
+	OnRandomTick(dimension *Dimension, x, y, z int, id, data byte)
+}
+
+// ScheduledTickBehavior is a block behavior that also gets called for scheduled block updates of its blocks.
+// Updates scheduled for a block that was replaced in the meantime are not passed to the behavior,
+// unless it was replaced by another variant of a VariantBehavior.
+type ScheduledTickBehavior interface {
+	BlockBehavior
+	// OnScheduledTick gets called when a block update scheduled for the block is due.
+	OnScheduledTick(dimension *Dimension, x, y, z int, tick chunks.ScheduledTick)
+}
+
+// VariantBehavior is a block behavior shared by the variants of a block with several block IDs,
+// such as the flowing and still block IDs of a liquid.
+type VariantBehavior interface {
+	BlockBehavior
+	// IsVariant checks if the block ID is one of the variants of the block of the behavior.
+	IsVariant(id byte) bool
+}
+
+// isSameBlock checks if the block with the current ID is still the block with the scheduled ID the behavior is registered to,
+// either having the same ID or being another variant of the behavior.
+func isSameBlock(behavior BlockBehavior, scheduledId, currentId byte) bool {
+	if scheduledId == currentId {
+		return true
+	}
+	var variants, ok = behavior.(VariantBehavior)
+	return ok && variants.IsVariant(currentId)
+}
+
+// BaseBehavior implements all hooks of BlockBehavior doing nothing.
+// It can be embedded by behaviors only implementing some of the hooks.
+type BaseBehavior struct{}
+
+// OnPlace does nothing.
+func (BaseBehavior) OnPlace(dimension *Dimension, x, y, z int, block blocks.Block) {}
+
+// OnBreak does nothing.
+func (BaseBehavior) OnBreak(dimension *Dimension, x, y, z int, id, data byte) {}
+
+// OnNeighborUpdate does nothing.
+func (BaseBehavior) OnNeighborUpdate(dimension *Dimension, x, y, z, neighbourX, neighbourY, neighbourZ int) {
+}
+
+// OnRandomTick does nothing.
+func (BaseBehavior) OnRandomTick(dimension *Dimension, x, y, z int, id, data byte) {}
+
+// BehaviorManager manages the block behaviors of block IDs.
+type BehaviorManager map[byte]BlockBehavior
+
+// NewBehaviorManager returns a new behavior manager.
+func NewBehaviorManager() BehaviorManager {
+	return BehaviorManager{}
+}
+
+// Register registers the behavior for the given block ID, overwriting any behavior previously registered on the ID.
+func (manager BehaviorManager) Register(blockId byte, behavior BlockBehavior) {
+	manager[blockId] = behavior
+}
+
+// Deregister deregisters the behavior of the given block ID.
+func (manager BehaviorManager) Deregister(blockId byte) {
+	delete(manager, blockId)
+}
+
+// Get returns the behavior of the given block ID, and a bool indicating if one was registered.
+func (manager BehaviorManager) Get(blockId byte) (BlockBehavior, bool) {
+	var behavior, ok = manager[blockId]
+	return behavior, ok
+}
+
+// SetBehaviorManager sets the behavior manager of the dimension, whose behaviors get called on block changes.
+// Behaviors should be registered before the manager is set, as the manager is not safe for concurrent use.
+func (dimension *Dimension) SetBehaviorManager(manager BehaviorManager) {
+	dimension.mutex.Lock()
+	dimension.behaviors = manager
+	dimension.mutex.Unlock()
+}
+
+// GetBehaviorManager returns the behavior manager of the dimension.
+func (dimension *Dimension) GetBehaviorManager() BehaviorManager {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.behaviors
+}
+
+// callBlockChange calls the behaviors of a block changed from the given previous ID and data, and of its neighbours.
+func (dimension *Dimension) callBlockChange(x, y, z int, block blocks.Block, previousId, previousData byte, flags BlockUpdateFlags) {
+	var behaviors = dimension.GetBehaviorManager()
+	if !flags.Has(NoPhysics) {
+		if behavior, ok := behaviors.Get(previousId); ok && previousId != 0 && (previousId != block.GetId() || previousData != block.GetData()) {
+			behavior.OnBreak(dimension, x, y, z, previousId, previousData)
+		}
+		if behavior, ok := behaviors.Get(block.GetId()); ok && block.GetId() != 0 {
+			behavior.OnPlace(dimension, x, y, z, block)
+		}
+	}
+	if flags.Has(NoNeighborUpdates) {
+		return
+	}
+	for _, face := range [6][3]int{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}} {
+		var nx, ny, nz = x + face[0], y + face[1], z + face[2]
+		// Neighbours in chunks that are not loaded are not updated, so that updating a block never loads chunks.
+		var chunk, ok = dimension.GetChunk(int32(nx>>4), int32(nz>>4))
+		if !ok {
+			continue
+		}
+		if behavior, ok := behaviors.Get(chunk.GetBlockId(nx&15, ny, nz&15)); ok {
+			behavior.OnNeighborUpdate(dimension, nx, ny, nz, x, y, z)
+		}
+	}
+}
+
+// randomTick random ticks blocks in all loaded chunks, picking as many blocks per sub chunk as the random tick speed game rule.
+func (dimension *Dimension) randomTick() {
+	var behaviors = dimension.GetBehaviorManager()
+	if len(behaviors) == 0 {
+		return
+	}
+	var speed = 0
+	if rule := dimension.level.GetGameRule(GameRuleRandomTickSpeed); rule != nil {
+		if value, ok := rule.GetValue().(uint32); ok && value <= math.MaxInt32 {
+			speed = int(value)
+		}
+	}
+	if speed == 0 {
+		return
+	}
+	for _, chunk := range dimension.chunkProvider.GetLoadedChunks() {
+		for _, block := range chunk.RandomTickBlocks(dimension.random, speed) {
+			if behavior, ok := behaviors.Get(block.Id); ok {
+				behavior.OnRandomTick(dimension, block.X, block.Y, block.Z, block.Id, block.Data)
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package worlds
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/blocks"
+	"github.com/irmine/worlds/chunks"
+)
+
+// SetBlockBatch applies all changes of the batch to the dimension, executing all side effects.
+// See SetBlockBatchWithFlags.
+func (dimension *Dimension) SetBlockBatch(batch *blocks.Batch) {
+	dimension.SetBlockBatchWithFlags(batch, UpdateAll)
+}
+
+// SetBlockBatchWithFlags applies all changes of the batch to the dimension, skipping the side effects opted out of by the flags.
+// Changes are grouped by chunk, so every chunk is loaded and written to only once.
+// The changed blocks of every chunk are queued for update at once, and sent to viewers together with the other block updates of the next tick.
+// Changes outside of the world border of the dimension are left out.
+func (dimension *Dimension) SetBlockBatchWithFlags(batch *blocks.Batch, flags BlockUpdateFlags) {
+	var grouped = make(map[[2]int32][]blocks.BatchChange)
+	var order [][2]int32
+	for _, change := range batch.GetChanges() {
+		if !dimension.isBlockWithinBorder(change.X, change.Y, change.Z) {
+			continue
+		}
+		var position = [2]int32{int32(change.X >> 4), int32(change.Z >> 4)}
+		if _, ok := grouped[position]; !ok {
+			order = append(order, position)
+		}
+		grouped[position] = append(grouped[position], change)
+	}
+	for _, position := range order {
+		var changes = grouped[position]
+		dimension.LoadChunk(position[0], position[1], func(chunk *chunks.Chunk) {
+			dimension.applyChanges(chunk, changes, flags)
+		})
+	}
+}
+
+// applyChanges applies the changes to the chunk, and executes their side effects.
+func (dimension *Dimension) applyChanges(chunk *chunks.Chunk, changes []blocks.BatchChange, flags BlockUpdateFlags) {
+	var writes = make([]chunks.BlockWrite, len(changes))
+	var previous = make([][2]byte, len(changes))
+	for i, change := range changes {
+		var x, z = change.X & 15, change.Z & 15
+		if chunk.GetSubChunkRange().ContainsY(change.Y) {
+			previous[i] = [2]byte{chunk.GetBlockIdUnchecked(x, change.Y, z), chunk.GetBlockDataUnchecked(x, change.Y, z)}
+		}
+		writes[i] = chunks.BlockWrite{X: x, Y: change.Y, Z: z, Id: change.Block.GetId(), Data: change.Block.GetData(), NBT: change.Block.GetNBT()}
+		// The same block may be set at multiple positions, so every position gets its own copy of the compound.
+		if writes[i].NBT != nil {
+			writes[i].NBT = chunks.CopyCompound(writes[i].NBT)
+		}
+	}
+	chunk.SetBlocks(writes)
+
+	var updates = make([]r3.Vector, 0, len(changes))
+	for i, change := range changes {
+		if !chunk.GetSubChunkRange().ContainsY(change.Y) {
+			continue
+		}
+		dimension.setBlockEntity(change.X, change.Y, change.Z, writes[i].NBT)
+		if !flags.Has(NoLightUpdate) {
+			chunk.SetHeightMapAt(change.X&15, change.Z&15, chunk.GetHighestBlockY(change.X&15, change.Z&15)+1)
+			dimension.lightEngine.UpdateBlock(change.X, change.Y, change.Z)
+		}
+		if !flags.Has(NoBroadcast) {
+			updates = append(updates, r3.Vector{X: float64(change.X), Y: float64(change.Y), Z: float64(change.Z)})
+		}
+		dimension.callBlockChange(change.X, change.Y, change.Z, change.Block, previous[i][0], previous[i][1], flags)
+	}
+	if len(updates) > 0 {
+		dimension.SetBlocksForUpdate(updates)
+	}
+}
+
+// GetRawBlockAt returns the block ID, block data and block NBT at the given world position.
+// The NBT of block entities is saved first, so it reflects their current state.
+// Returns UnloadedChunk if the chunk of the position is not loaded.
+func (dimension *Dimension) GetRawBlockAt(x, y, z int) (byte, byte, *gonbt.Compound, error) {
+	var chunk, ok = dimension.GetChunk(int32(x>>4), int32(z>>4))
+	if !ok {
+		return 0, 0, nil, UnloadedChunk
+	}
+	var nbt, _ = chunk.GetBlockNBTAt(x&15, y, z&15)
+	if blockEntity, ok := dimension.GetBlockEntity(r3.Vector{X: float64(x), Y: float64(y), Z: float64(z)}); ok {
+		nbt = blockEntity.Save()
+	}
+	return chunk.GetBlockId(x&15, y, z&15), chunk.GetBlockData(x&15, y, z&15), nbt, nil
+}
+
+// ApplyBatchWithUndo applies all changes of the batch to the dimension, and returns the batch undoing the changes.
+// The batch is not applied if the blocks at any of its positions could not be captured, such as when chunks are not loaded,
+// or if any of its positions is outside of the world border, in which case OutsideBorder is returned.
+func (dimension *Dimension) ApplyBatchWithUndo(batch *blocks.Batch) (*blocks.Batch, error) {
+	for _, change := range batch.GetChanges() {
+		if !dimension.isBlockWithinBorder(change.X, change.Y, change.Z) {
+			return nil, OutsideBorder
+		}
+	}
+	var undo, err = batch.Capture(dimension)
+	if err != nil {
+		return nil, err
+	}
+	dimension.SetBlockBatch(batch)
+	return undo, nil
+}
+
+// SetChunkBlocks writes all blocks to the chunk at once, without queuing the blocks for block updates or calling block behaviors.
+// The height map and light of the chunk are recalculated once, together with the light of its neighbours, and the chunk is sent to its viewers as a whole.
+// SetChunkBlocks is meant for large edits, where sending every block would be slower than sending the chunk.
+// Writes outside of the world border of the dimension are left out. The count of blocks written is returned.
+func (dimension *Dimension) SetChunkBlocks(chunk *chunks.Chunk, writes []chunks.BlockWrite) int {
+	var within = make([]chunks.BlockWrite, 0, len(writes))
+	for _, write := range writes {
+		if dimension.isBlockWithinBorder(int(chunk.X)<<4|write.X, write.Y, int(chunk.Z)<<4|write.Z) {
+			within = append(within, write)
+		}
+	}
+	writes = within
+	if len(writes) == 0 {
+		return 0
+	}
+	for i, write := range writes {
+		// Writes may share the same compound, which would otherwise end up holding the position of the last block entity.
+		if write.NBT != nil {
+			writes[i].NBT = chunks.CopyCompound(write.NBT)
+		}
+	}
+	chunk.SetBlocks(writes)
+	for _, write := range writes {
+		dimension.setBlockEntity(int(chunk.X)<<4|write.X, write.Y, int(chunk.Z)<<4|write.Z, write.NBT)
+	}
+	chunk.RecalculateHeightMap()
+	dimension.lightEngine.Relight(chunk)
+	dimension.RefreshChunk(chunk, writes)
+	return len(writes)
+}
+
+// RefreshChunk sends the chunk to all its viewers after the given blocks were written to it.
+// Viewers that cannot be sent chunks are sent every block written instead.
+func (dimension *Dimension) RefreshChunk(chunk *chunks.Chunk, writes []chunks.BlockWrite) {
+	for _, viewer := range chunk.GetViewers() {
+		if chunkViewer, ok := viewer.(chunks.ChunkViewer); ok {
+			chunkViewer.SendChunk(chunk)
+			continue
+		}
+		for _, write := range writes {
+			if runtimeId, ok := dimension.GetViewerRuntimeId(viewer, int(write.Id), int(write.Data)); ok && write.Y >= 0 {
+				viewer.SendUpdateBlock(blocks.NewPosition(chunk.X<<4|int32(write.X), uint32(write.Y), chunk.Z<<4|int32(write.Z)), runtimeId, 0)
+			}
+		}
+	}
+}
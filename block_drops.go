@@ -0,0 +1,73 @@
+package worlds
+
+import (
+	"errors"
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/blocks"
+	"math"
+)
+
+// UnbreakableBlock gets returned when breaking a block that cannot be broken, such as bedrock.
+var UnbreakableBlock = errors.New("block cannot be broken")
+
+// ItemEntityFunction returns a new item entity holding the item stack, to be added to the dimension at the given position.
+// Returns nil if no item entity should be added.
+type ItemEntityFunction func(stack blocks.ItemStack, position r3.Vector) DroppedItem
+
+// SetItemEntityFunction sets the function creating item entities for blocks broken in the dimension.
+// No items are dropped when breaking blocks if nil.
+func (dimension *Dimension) SetItemEntityFunction(function ItemEntityFunction) {
+	dimension.mutex.Lock()
+	dimension.itemEntityFunction = function
+	dimension.mutex.Unlock()
+}
+
+// GetItemEntityFunction returns the function creating item entities for items dropped in the dimension.
+func (dimension *Dimension) GetItemEntityFunction() ItemEntityFunction {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.itemEntityFunction
+}
+
+// DropItemStacks drops the item stacks naturally as item entities at the position, using the item entity function of the dimension.
+// No items are dropped if the dimension has no item entity function.
+func (dimension *Dimension) DropItemStacks(stacks []blocks.ItemStack, position r3.Vector) {
+	var function = dimension.GetItemEntityFunction()
+	if function == nil {
+		return
+	}
+	for _, stack := range stacks {
+		if item := function(stack, position); item != nil {
+			dimension.DropItemNaturally(item, position)
+		}
+	}
+}
+
+// BreakBlock breaks the block at the given vector with the tool, replacing it with air.
+// The drops of the block are dropped naturally as item entities in the center of the block if the doTileDrops game rule is enabled.
+// Returns the item stacks dropped, UnloadedChunk if the chunk of the block is not loaded,
+// or OutsideBorder if the block is outside of the world border and can therefore not be changed.
+func (dimension *Dimension) BreakBlock(vector r3.Vector, tool blocks.Tool) ([]blocks.ItemStack, error) {
+	var x, y, z = int(math.Floor(vector.X)), int(math.Floor(vector.Y)), int(math.Floor(vector.Z))
+	if !dimension.isBlockWithinBorder(x, y, z) {
+		return nil, OutsideBorder
+	}
+	var chunk, ok = dimension.GetChunk(int32(x>>4), int32(z>>4))
+	if !ok {
+		return nil, UnloadedChunk
+	}
+	var id, data = chunk.GetBlockId(x&15, y, z&15), chunk.GetBlockData(x&15, y, z&15)
+	if properties, ok := blocks.GetProperties(id); ok && !properties.IsBreakable() {
+		return nil, UnbreakableBlock
+	}
+	dimension.SetBlockAt(vector, blocks.NewLegacyBlock(0, 0, nil))
+	if !dimension.level.GetBoolGameRule(GameRuleDoTileDrops, true) {
+		return nil, nil
+	}
+
+	dimension.mutex.Lock()
+	var stacks = blocks.GetDrops(id, data, tool, dimension.random)
+	dimension.mutex.Unlock()
+	dimension.DropItemStacks(stacks, r3.Vector{X: float64(x) + 0.5, Y: float64(y) + 0.5, Z: float64(z) + 0.5})
+	return stacks, nil
+}
@@ -0,0 +1,112 @@
+package worlds
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/blockentities"
+	"github.com/irmine/worlds/chunks"
+	"math"
+)
+
+// SetBlockEntityManager sets the block entity manager of the dimension, used to create block entities from block NBT.
+// Block NBT of chunks loaded before the manager is set does not get turned into block entities.
+func (dimension *Dimension) SetBlockEntityManager(manager blockentities.Manager) {
+	dimension.mutex.Lock()
+	dimension.blockEntityManager = manager
+	dimension.mutex.Unlock()
+}
+
+// GetBlockEntityManager returns the block entity manager of the dimension.
+func (dimension *Dimension) GetBlockEntityManager() blockentities.Manager {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.blockEntityManager
+}
+
+// GetBlockEntity returns the block entity at the given vector, and a bool indicating if one was found.
+func (dimension *Dimension) GetBlockEntity(vector r3.Vector) (blockentities.BlockEntity, bool) {
+	var x, y, z = int(math.Floor(vector.X)), int(math.Floor(vector.Y)), int(math.Floor(vector.Z))
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	var blockEntity, ok = dimension.blockEntities[[3]int{x, y, z}]
+	return blockEntity, ok
+}
+
+// GetBlockEntities returns all block entities in loaded chunks of the dimension.
+func (dimension *Dimension) GetBlockEntities() []blockentities.BlockEntity {
+	dimension.mutex.RLock()
+	var result = make([]blockentities.BlockEntity, 0, len(dimension.blockEntities))
+	for _, blockEntity := range dimension.blockEntities {
+		result = append(result, blockEntity)
+	}
+	dimension.mutex.RUnlock()
+	return result
+}
+
+// loadBlockEntities creates block entities for all block NBT in the chunk with IDs registered in the block entity manager.
+func (dimension *Dimension) loadBlockEntities(chunk *chunks.Chunk) {
+	for key, nbt := range chunk.GetBlockNBT() {
+		dimension.setBlockEntity(int(chunk.X)<<4|key.X, key.Y, int(chunk.Z)<<4|key.Z, nbt)
+	}
+}
+
+// setBlockEntity replaces the block entity at the given world position with a block entity created from the NBT.
+// The block entity is only removed if the NBT is nil or its ID is not registered.
+func (dimension *Dimension) setBlockEntity(x, y, z int, nbt *gonbt.Compound) {
+	var position = [3]int{x, y, z}
+	dimension.mutex.Lock()
+	defer dimension.mutex.Unlock()
+	delete(dimension.blockEntities, position)
+	if nbt == nil || dimension.blockEntityManager == nil {
+		return
+	}
+	// The position of the block is authoritative, regardless of the position in the NBT.
+	nbt.SetTag(gonbt.NewInt("x", int32(x)))
+	nbt.SetTag(gonbt.NewInt("y", int32(y)))
+	nbt.SetTag(gonbt.NewInt("z", int32(z)))
+	if blockEntity, err := dimension.blockEntityManager.FromNBT(nbt); err == nil {
+		dimension.blockEntities[position] = blockEntity
+	}
+}
+
+// tickBlockEntities ticks all tickable block entities.
+func (dimension *Dimension) tickBlockEntities() {
+	var currentTick = dimension.level.GetCurrentTick()
+	for _, blockEntity := range dimension.GetBlockEntities() {
+		if tickable, ok := blockEntity.(blockentities.Tickable); ok {
+			tickable.Tick(currentTick)
+		}
+	}
+}
+
+// saveBlockEntities writes the NBT of the block entities in the chunk at the given chunk X and Z back to the chunk.
+// The block entities are removed from the dimension if remove is true, such as when the chunk gets unloaded.
+func (dimension *Dimension) saveBlockEntities(x, z int32, remove bool) {
+	var chunk, ok = dimension.GetChunk(x, z)
+	dimension.mutex.Lock()
+	for position, blockEntity := range dimension.blockEntities {
+		if int32(position[0]>>4) != x || int32(position[2]>>4) != z {
+			continue
+		}
+		if ok {
+			chunk.SetBlockNBTAt(position[0]&15, position[1], position[2]&15, blockEntity.Save())
+		}
+		if remove {
+			delete(dimension.blockEntities, position)
+		}
+	}
+	dimension.mutex.Unlock()
+}
+
+// saveAllBlockEntities writes the NBT of all block entities back to their chunks.
+func (dimension *Dimension) saveAllBlockEntities() {
+	dimension.mutex.RLock()
+	var saved = make(map[[2]int32]bool)
+	for position := range dimension.blockEntities {
+		saved[[2]int32{int32(position[0] >> 4), int32(position[2] >> 4)}] = true
+	}
+	dimension.mutex.RUnlock()
+	for position := range saved {
+		dimension.saveBlockEntities(position[0], position[1], false)
+	}
+}
@@ -0,0 +1,24 @@
+package worlds
+
+// BlockUpdateFlags are flags used to opt out of side effects of setting blocks.
+// Flags can be combined using bitwise OR.
+type BlockUpdateFlags byte
+
+// UpdateAll is the default, executing all side effects of setting a block.
+const UpdateAll BlockUpdateFlags = 0
+
+const (
+	// NoNeighborUpdates prevents neighbouring blocks from getting notified of the change.
+	NoNeighborUpdates BlockUpdateFlags = 1 << iota
+	// NoBroadcast prevents the change from getting sent to viewers of the dimension.
+	NoBroadcast
+	// NoLightUpdate prevents the height map and light of the chunk from getting updated.
+	NoLightUpdate
+	// NoPhysics prevents the block from running physics, such as falling or flowing, as a result of the change.
+	NoPhysics
+)
+
+// Has checks if all of the given flags are set.
+func (flags BlockUpdateFlags) Has(flag BlockUpdateFlags) bool {
+	return flags&flag == flag
+}
@@ -0,0 +1,78 @@
+package worlds
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/chunks"
+	"math"
+)
+
+// BlockTickFunction gets called for every scheduled block update that is due, with the world X, Y and Z of the block.
+type BlockTickFunction func(x, y, z int, tick chunks.ScheduledTick)
+
+// SetBlockTickFunction sets the function called for every scheduled block update that is due.
+// The function gets called before the behavior of the block, if it implements ScheduledTickBehavior.
+func (dimension *Dimension) SetBlockTickFunction(function BlockTickFunction) {
+	dimension.mutex.Lock()
+	dimension.blockTickFunction = function
+	dimension.mutex.Unlock()
+}
+
+// ScheduleBlockTick schedules an update of the block at the given vector after the given amount of ticks.
+// Updates with a lower priority happen first if scheduled at the same tick.
+// The update is stored in the chunk of the block, so it survives the chunk being unloaded and saved.
+func (dimension *Dimension) ScheduleBlockTick(vector r3.Vector, delayTicks int64, priority int32) {
+	var x, y, z = int(math.Floor(vector.X)), int(math.Floor(vector.Y)), int(math.Floor(vector.Z))
+	dimension.LoadChunk(int32(x>>4), int32(z>>4), func(chunk *chunks.Chunk) {
+		chunk.ScheduleTick(chunks.ScheduledTick{
+			X:        x & 15,
+			Y:        y,
+			Z:        z & 15,
+			BlockId:  chunk.GetBlockId(x&15, y, z&15),
+			Tick:     dimension.level.GetCurrentTick() + delayTicks,
+			Priority: priority,
+		})
+		dimension.trackBlockTicks(chunk)
+	})
+}
+
+// trackBlockTicks makes the dimension process the scheduled block updates of the chunk.
+func (dimension *Dimension) trackBlockTicks(chunk *chunks.Chunk) {
+	dimension.mutex.Lock()
+	dimension.blockTickChunks[[2]int32{chunk.X, chunk.Z}] = true
+	dimension.mutex.Unlock()
+}
+
+// processBlockTicks runs all scheduled block updates that are due in loaded chunks.
+func (dimension *Dimension) processBlockTicks() {
+	var currentTick = dimension.level.GetCurrentTick()
+	dimension.mutex.RLock()
+	var function = dimension.blockTickFunction
+	var positions = make([][2]int32, 0, len(dimension.blockTickChunks))
+	for position := range dimension.blockTickChunks {
+		positions = append(positions, position)
+	}
+	dimension.mutex.RUnlock()
+
+	for _, position := range positions {
+		var chunk, ok = dimension.GetChunk(position[0], position[1])
+		if ok {
+			var behaviors = dimension.GetBehaviorManager()
+			for _, tick := range chunk.PopDueTicks(currentTick) {
+				var x, z = int(chunk.X)<<4 | tick.X, int(chunk.Z)<<4 | tick.Z
+				if function != nil {
+					function(x, tick.Y, z, tick)
+				}
+				if behavior, ok := behaviors.Get(tick.BlockId); ok && isSameBlock(behavior, tick.BlockId, chunk.GetBlockIdUnchecked(tick.X, tick.Y, tick.Z)) {
+					if behavior, ok := behavior.(ScheduledTickBehavior); ok {
+						behavior.OnScheduledTick(dimension, x, tick.Y, z, tick)
+					}
+				}
+			}
+		}
+		if !ok || !chunk.HasScheduledTicks() {
+			dimension.mutex.Lock()
+			delete(dimension.blockTickChunks, position)
+			dimension.mutex.Unlock()
+		}
+	}
+}
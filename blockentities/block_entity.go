@@ -0,0 +1,60 @@
+package blockentities
+
+import "github.com/irmine/gonbt"
+
+// BlockEntity is a block with additional data, such as the items of a chest or the text of a sign.
+// Block entities get created from the block NBT of chunks, and are saved back to it.
+type BlockEntity interface {
+	// GetId returns the NBT ID of the block entity, such as "Chest".
+	GetId() string
+	// GetPosition returns the world X, Y and Z of the block entity.
+	GetPosition() (int, int, int)
+	// Load loads the data of the block entity from its NBT.
+	Load(nbt *gonbt.Compound)
+	// Save returns the NBT of the block entity, holding its ID, position and data.
+	Save() *gonbt.Compound
+}
+
+// Tickable is a block entity that gets ticked every tick, such as a furnace smelting items.
+type Tickable interface {
+	BlockEntity
+	// Tick ticks the block entity, with the current tick of the level.
+	Tick(currentTick int64)
+}
+
+// Base implements the basic functionality of block entities.
+// Tags of the block entity NBT not handled by the block entity are kept, so they get saved again.
+type Base struct {
+	id      string
+	x, y, z int
+	nbt     *gonbt.Compound
+}
+
+// NewBase returns a new base block entity with the given NBT ID and world position.
+func NewBase(id string, x, y, z int) *Base {
+	return &Base{id, x, y, z, gonbt.NewCompound("", make(map[string]gonbt.INamedTag))}
+}
+
+// GetId returns the NBT ID of the block entity.
+func (base *Base) GetId() string {
+	return base.id
+}
+
+// GetPosition returns the world X, Y and Z of the block entity.
+func (base *Base) GetPosition() (int, int, int) {
+	return base.x, base.y, base.z
+}
+
+// Load loads the NBT of the block entity.
+func (base *Base) Load(nbt *gonbt.Compound) {
+	base.nbt = nbt
+}
+
+// Save returns the NBT of the block entity, with its ID and position set.
+func (base *Base) Save() *gonbt.Compound {
+	base.nbt.SetTag(gonbt.NewString("id", base.id))
+	base.nbt.SetTag(gonbt.NewInt("x", int32(base.x)))
+	base.nbt.SetTag(gonbt.NewInt("y", int32(base.y)))
+	base.nbt.SetTag(gonbt.NewInt("z", int32(base.z)))
+	return base.nbt
+}
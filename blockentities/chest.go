@@ -0,0 +1,33 @@
+package blockentities
+
+import "github.com/irmine/gonbt"
+
+// ChestId is the NBT ID of chests.
+const ChestId = "Chest"
+
+// Chest is a block entity holding the items of a chest.
+type Chest struct {
+	*Base
+	// Items are the NBT of the items in the chest, each with their slot.
+	Items *gonbt.List
+}
+
+// NewChest returns a new empty chest at the given world position.
+func NewChest(x, y, z int) *Chest {
+	return &Chest{NewBase(ChestId, x, y, z), gonbt.NewList("Items", gonbt.TAG_Compound, []gonbt.INamedTag{})}
+}
+
+// Load loads the items of the chest from its NBT.
+func (chest *Chest) Load(nbt *gonbt.Compound) {
+	chest.Base.Load(nbt)
+	if items := nbt.GetList("Items", gonbt.TAG_Compound); items != nil {
+		chest.Items = items
+	}
+}
+
+// Save returns the NBT of the chest, holding its items.
+func (chest *Chest) Save() *gonbt.Compound {
+	var nbt = chest.Base.Save()
+	nbt.SetTag(chest.Items)
+	return nbt
+}
@@ -0,0 +1,87 @@
+package blockentities
+
+import "github.com/irmine/gonbt"
+
+// FurnaceId is the NBT ID of furnaces.
+const FurnaceId = "Furnace"
+
+// FurnaceCookTime is the amount of ticks it takes a furnace to smelt an item.
+const FurnaceCookTime = 200
+
+// Furnace is a tickable block entity smelting items while it has fuel burning.
+type Furnace struct {
+	*Base
+	// Items are the NBT of the items in the furnace, each with their slot.
+	Items *gonbt.List
+	// BurnTime is the amount of ticks left the current fuel burns.
+	BurnTime int16
+	// BurnDuration is the amount of ticks the current fuel burned when it started burning.
+	BurnDuration int16
+	// CookTime is the amount of ticks the current item has been smelting.
+	CookTime int16
+
+	// CanSmelt gets called every tick the furnace is burning, to check if the furnace has an item it can smelt.
+	// The furnace never smelts if nil.
+	CanSmelt func(furnace *Furnace) bool
+	// SmeltFunction gets called every time the furnace finished smelting an item, and should move the result into the output.
+	SmeltFunction func(furnace *Furnace)
+	// FuelFunction gets called when the fuel ran out while the furnace can smelt, and should consume new fuel,
+	// returning the amount of ticks it burns. The furnace stops burning if 0 is returned.
+	FuelFunction func(furnace *Furnace) int16
+}
+
+// NewFurnace returns a new furnace that is not burning at the given world position.
+func NewFurnace(x, y, z int) *Furnace {
+	return &Furnace{NewBase(FurnaceId, x, y, z), gonbt.NewList("Items", gonbt.TAG_Compound, []gonbt.INamedTag{}), 0, 0, 0, nil, nil, nil}
+}
+
+// IsBurning checks if the furnace has fuel burning.
+func (furnace *Furnace) IsBurning() bool {
+	return furnace.BurnTime > 0
+}
+
+// Tick burns the fuel of the furnace, and advances smelting while the furnace can smelt.
+func (furnace *Furnace) Tick(currentTick int64) {
+	var canSmelt = furnace.CanSmelt != nil && furnace.CanSmelt(furnace)
+	if !furnace.IsBurning() && canSmelt && furnace.FuelFunction != nil {
+		furnace.BurnTime = furnace.FuelFunction(furnace)
+		furnace.BurnDuration = furnace.BurnTime
+	}
+	if !furnace.IsBurning() {
+		furnace.CookTime = 0
+		return
+	}
+	furnace.BurnTime--
+	if !canSmelt {
+		furnace.CookTime = 0
+		return
+	}
+	furnace.CookTime++
+	if furnace.CookTime >= FurnaceCookTime {
+		furnace.CookTime = 0
+		if furnace.SmeltFunction != nil {
+			furnace.SmeltFunction(furnace)
+		}
+	}
+}
+
+// Load loads the items and burn state of the furnace from its NBT.
+func (furnace *Furnace) Load(nbt *gonbt.Compound) {
+	furnace.Base.Load(nbt)
+	if items := nbt.GetList("Items", gonbt.TAG_Compound); items != nil {
+		furnace.Items = items
+	}
+	furnace.BurnTime = nbt.GetShort("BurnTime", 0)
+	furnace.BurnDuration = nbt.GetShort("BurnDuration", 0)
+	furnace.CookTime = nbt.GetShort("CookTime", 0)
+}
+
+// Save returns the NBT of the furnace, holding its items and burn state.
+func (furnace *Furnace) Save() *gonbt.Compound {
+	var nbt = furnace.Base.Save()
+	nbt.SetTag(furnace.Items)
+	nbt.SetTag(gonbt.NewShort("BurnTime", furnace.BurnTime))
+	nbt.SetTag(gonbt.NewShort("BurnDuration", furnace.BurnDuration))
+	nbt.SetTag(gonbt.NewShort("CookTime", furnace.CookTime))
+	return nbt
+}
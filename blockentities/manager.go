@@ -0,0 +1,59 @@
+package blockentities
+
+import (
+	"errors"
+	"github.com/irmine/gonbt"
+)
+
+// Manager manages block entities and has utility functions for registering those.
+type Manager map[string]func(x, y, z int) BlockEntity
+
+// UnregisteredBlockEntity gets returned if block entity NBT has an ID that is not registered.
+var UnregisteredBlockEntity = errors.New("block entity is not registered")
+
+// NewManager returns a new block entities manager.
+func NewManager() Manager {
+	return Manager{}
+}
+
+// Register registers a new block entity function for the given NBT ID.
+// Register overwrites any block entities that might have been previously registered on the ID.
+func (manager Manager) Register(id string, blockEntityFunc func(x, y, z int) BlockEntity) {
+	manager[id] = blockEntityFunc
+}
+
+// Deregister deregisters the block entity function with the given NBT ID.
+func (manager Manager) Deregister(id string) {
+	delete(manager, id)
+}
+
+// IsRegistered checks if a block entity function with the given NBT ID is registered.
+func (manager Manager) IsRegistered(id string) bool {
+	var _, ok = manager[id]
+	return ok
+}
+
+// FromNBT creates a block entity from its NBT, using the ID and position in the NBT.
+// Returns UnregisteredBlockEntity if no block entity with the ID was registered.
+func (manager Manager) FromNBT(nbt *gonbt.Compound) (BlockEntity, error) {
+	var id = nbt.GetString("id", "")
+	if !manager.IsRegistered(id) {
+		return nil, UnregisteredBlockEntity
+	}
+	var blockEntity = manager[id](int(nbt.GetInt("x", 0)), int(nbt.GetInt("y", 0)), int(nbt.GetInt("z", 0)))
+	blockEntity.Load(nbt)
+	return blockEntity, nil
+}
+
+// RegisterDefaults registers all block entities implemented by this package.
+func RegisterDefaults(manager Manager) {
+	manager.Register(ChestId, func(x, y, z int) BlockEntity {
+		return NewChest(x, y, z)
+	})
+	manager.Register(FurnaceId, func(x, y, z int) BlockEntity {
+		return NewFurnace(x, y, z)
+	})
+	manager.Register(SignId, func(x, y, z int) BlockEntity {
+		return NewSign(x, y, z)
+	})
+}
@@ -0,0 +1,259 @@
+package blockentities
+
+import (
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/chunks"
+	"strings"
+)
+
+const (
+	// SkullId is the NBT ID of skulls.
+	SkullId = "Skull"
+	// BannerId is the NBT ID of banners.
+	BannerId = "Banner"
+)
+
+// SignLineCount is the count of lines of text on a sign.
+const SignLineCount = 4
+
+// SignNBT wraps the NBT of a sign, reading and writing its text directly from and to the compound.
+type SignNBT struct {
+	*gonbt.Compound
+}
+
+// NewSignNBT returns a sign NBT wrapper around the compound.
+func NewSignNBT(nbt *gonbt.Compound) SignNBT {
+	return SignNBT{nbt}
+}
+
+// GetLines returns the lines of text on the sign. Lines missing in the NBT are empty.
+func (sign SignNBT) GetLines() [SignLineCount]string {
+	var lines [SignLineCount]string
+	copy(lines[:], strings.SplitN(sign.GetString("Text", ""), "\n", SignLineCount))
+	return lines
+}
+
+// GetLine returns the line of text with the given index, which must be lower than SignLineCount.
+func (sign SignNBT) GetLine(index int) string {
+	return sign.GetLines()[index]
+}
+
+// SetLines sets the lines of text on the sign.
+func (sign SignNBT) SetLines(lines [SignLineCount]string) {
+	sign.SetTag(gonbt.NewString("Text", strings.Join(lines[:], "\n")))
+}
+
+// SetLine sets the line of text with the given index, which must be lower than SignLineCount.
+// Newlines in the text are replaced with spaces, so the text never spreads over other lines.
+func (sign SignNBT) SetLine(index int, text string) {
+	var lines = sign.GetLines()
+	lines[index] = strings.Replace(text, "\n", " ", -1)
+	sign.SetLines(lines)
+}
+
+// ItemNBT is an item stored in the NBT of a container, in the slot it occupies.
+type ItemNBT struct {
+	Slot   byte
+	Id     int16
+	Damage int16
+	Count  byte
+	// Tag is the NBT of the item itself, such as its enchantments. It is nil if the item has none.
+	Tag *gonbt.Compound
+}
+
+// ContainerNBT wraps the NBT of a container such as a chest, reading and writing its items directly from and to the compound.
+type ContainerNBT struct {
+	*gonbt.Compound
+}
+
+// NewContainerNBT returns a container NBT wrapper around the compound.
+func NewContainerNBT(nbt *gonbt.Compound) ContainerNBT {
+	return ContainerNBT{nbt}
+}
+
+// GetItems returns all items in the container, in the order they are stored.
+// Reading the items never changes the NBT of the container.
+func (container ContainerNBT) GetItems() []ItemNBT {
+	var list = container.GetList("Items", gonbt.TAG_Compound)
+	if list == nil {
+		return nil
+	}
+	var items []ItemNBT
+	for _, tag := range list.GetTags() {
+		if compound, ok := tag.(*gonbt.Compound); ok {
+			items = append(items, readItem(compound))
+		}
+	}
+	return items
+}
+
+// GetItem returns the item in the given slot, and a bool indicating if the slot holds an item.
+func (container ContainerNBT) GetItem(slot byte) (ItemNBT, bool) {
+	for _, item := range container.GetItems() {
+		if item.Slot == slot {
+			return item, true
+		}
+	}
+	return ItemNBT{}, false
+}
+
+// SetItem sets the item in its slot, replacing the item previously in the slot.
+// Items with a count of 0 empty the slot.
+func (container ContainerNBT) SetItem(item ItemNBT) {
+	var tags []gonbt.INamedTag
+	for _, current := range container.GetItems() {
+		if current.Slot != item.Slot {
+			tags = append(tags, writeItem(current))
+		}
+	}
+	if item.Count != 0 {
+		tags = append(tags, writeItem(item))
+	}
+	container.SetItems(tags)
+}
+
+// RemoveItem empties the given slot.
+func (container ContainerNBT) RemoveItem(slot byte) {
+	container.SetItem(ItemNBT{Slot: slot})
+}
+
+// Clear removes all items from the container.
+func (container ContainerNBT) Clear() {
+	container.SetItems(nil)
+}
+
+// SetItems replaces the item list of the container with the given item compounds.
+func (container ContainerNBT) SetItems(tags []gonbt.INamedTag) {
+	if tags == nil {
+		tags = []gonbt.INamedTag{}
+	}
+	container.SetTag(gonbt.NewList("Items", gonbt.TAG_Compound, tags))
+}
+
+// readItem reads an item from its NBT.
+func readItem(compound *gonbt.Compound) ItemNBT {
+	return ItemNBT{
+		Slot:   compound.GetByte("Slot", 0),
+		Id:     compound.GetShort("id", 0),
+		Damage: compound.GetShort("Damage", 0),
+		Count:  compound.GetByte("Count", 0),
+		Tag:    compound.GetCompound("tag"),
+	}
+}
+
+// writeItem returns the NBT of the item.
+// The tag of the item is copied, so that the NBT returned does not share it with the item.
+func writeItem(item ItemNBT) *gonbt.Compound {
+	var compound = gonbt.NewCompound("", make(map[string]gonbt.INamedTag))
+	compound.SetTag(gonbt.NewByte("Slot", item.Slot))
+	compound.SetTag(gonbt.NewShort("id", item.Id))
+	compound.SetTag(gonbt.NewShort("Damage", item.Damage))
+	compound.SetTag(gonbt.NewByte("Count", item.Count))
+	if item.Tag != nil {
+		var tag = chunks.CopyCompound(item.Tag)
+		tag.SetName("tag")
+		compound.SetTag(tag)
+	}
+	return compound
+}
+
+// SkullType is the type of a skull, such as a skeleton skull or a creeper head.
+type SkullType byte
+
+const (
+	SkullSkeleton SkullType = iota
+	SkullWitherSkeleton
+	SkullZombie
+	SkullPlayer
+	SkullCreeper
+	SkullDragon
+)
+
+// SkullNBT wraps the NBT of a skull, reading and writing its type and rotation directly from and to the compound.
+type SkullNBT struct {
+	*gonbt.Compound
+}
+
+// NewSkullNBT returns a skull NBT wrapper around the compound.
+func NewSkullNBT(nbt *gonbt.Compound) SkullNBT {
+	return SkullNBT{nbt}
+}
+
+// GetSkullType returns the type of the skull.
+func (skull SkullNBT) GetSkullType() SkullType {
+	return SkullType(skull.GetByte("SkullType", 0))
+}
+
+// SetSkullType sets the type of the skull.
+func (skull SkullNBT) SetSkullType(skullType SkullType) {
+	skull.SetTag(gonbt.NewByte("SkullType", byte(skullType)))
+}
+
+// GetRotation returns the rotation of a skull standing on the floor, in degrees.
+func (skull SkullNBT) GetRotation() float32 {
+	return skull.GetFloat("Rotation", 0)
+}
+
+// SetRotation sets the rotation of a skull standing on the floor, in degrees.
+func (skull SkullNBT) SetRotation(rotation float32) {
+	skull.SetTag(gonbt.NewFloat("Rotation", rotation))
+}
+
+// BannerPattern is a pattern drawn on a banner, with the dye color it is drawn in.
+type BannerPattern struct {
+	// Pattern is the code of the pattern, such as "bo" for a border.
+	Pattern string
+	Color   int32
+}
+
+// BannerNBT wraps the NBT of a banner, reading and writing its base color and patterns directly from and to the compound.
+type BannerNBT struct {
+	*gonbt.Compound
+}
+
+// NewBannerNBT returns a banner NBT wrapper around the compound.
+func NewBannerNBT(nbt *gonbt.Compound) BannerNBT {
+	return BannerNBT{nbt}
+}
+
+// GetBaseColor returns the dye color of the banner below its patterns.
+func (banner BannerNBT) GetBaseColor() int32 {
+	return banner.GetInt("Base", 0)
+}
+
+// SetBaseColor sets the dye color of the banner below its patterns.
+func (banner BannerNBT) SetBaseColor(color int32) {
+	banner.SetTag(gonbt.NewInt("Base", color))
+}
+
+// GetPatterns returns the patterns of the banner, from the bottom layer to the top layer.
+func (banner BannerNBT) GetPatterns() []BannerPattern {
+	var list = banner.GetList("Patterns", gonbt.TAG_Compound)
+	if list == nil {
+		return nil
+	}
+	var patterns []BannerPattern
+	for _, tag := range list.GetTags() {
+		if compound, ok := tag.(*gonbt.Compound); ok {
+			patterns = append(patterns, BannerPattern{compound.GetString("Pattern", ""), compound.GetInt("Color", 0)})
+		}
+	}
+	return patterns
+}
+
+// SetPatterns sets the patterns of the banner, from the bottom layer to the top layer.
+func (banner BannerNBT) SetPatterns(patterns []BannerPattern) {
+	var tags = make([]gonbt.INamedTag, 0, len(patterns))
+	for _, pattern := range patterns {
+		var compound = gonbt.NewCompound("", make(map[string]gonbt.INamedTag))
+		compound.SetTag(gonbt.NewString("Pattern", pattern.Pattern))
+		compound.SetTag(gonbt.NewInt("Color", pattern.Color))
+		tags = append(tags, compound)
+	}
+	banner.SetTag(gonbt.NewList("Patterns", gonbt.TAG_Compound, tags))
+}
+
+// AddPattern adds a pattern on top of the patterns of the banner.
+func (banner BannerNBT) AddPattern(pattern BannerPattern) {
+	banner.SetPatterns(append(banner.GetPatterns(), pattern))
+}
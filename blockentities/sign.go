@@ -0,0 +1,31 @@
+package blockentities
+
+import "github.com/irmine/gonbt"
+
+// SignId is the NBT ID of signs.
+const SignId = "Sign"
+
+// Sign is a block entity holding the text of a sign.
+type Sign struct {
+	*Base
+	// Text is the text on the sign, with lines separated by newlines.
+	Text string
+}
+
+// NewSign returns a new sign without text at the given world position.
+func NewSign(x, y, z int) *Sign {
+	return &Sign{NewBase(SignId, x, y, z), ""}
+}
+
+// Load loads the text of the sign from its NBT.
+func (sign *Sign) Load(nbt *gonbt.Compound) {
+	sign.Base.Load(nbt)
+	sign.Text = nbt.GetString("Text", "")
+}
+
+// Save returns the NBT of the sign, holding its text.
+func (sign *Sign) Save() *gonbt.Compound {
+	var nbt = sign.Base.Save()
+	nbt.SetTag(gonbt.NewString("Text", sign.Text))
+	return nbt
+}
@@ -0,0 +1,85 @@
+package blocks
+
+import "github.com/irmine/gonbt"
+
+// BatchChange is a change of a block in a batch, at a world X, Y and Z.
+type BatchChange struct {
+	X, Y, Z int
+	Block   Block
+}
+
+// Batch is a set of block changes applied together.
+// Setting a block at a position already in the batch replaces the change at that position.
+type Batch struct {
+	changes []BatchChange
+	indices map[[3]int]int
+}
+
+// NewBatch returns a new empty batch.
+func NewBatch() *Batch {
+	return &Batch{nil, make(map[[3]int]int)}
+}
+
+// Set sets the block at the given world position in the batch.
+func (batch *Batch) Set(x, y, z int, block Block) {
+	if index, ok := batch.indices[[3]int{x, y, z}]; ok {
+		batch.changes[index].Block = block
+		return
+	}
+	batch.indices[[3]int{x, y, z}] = len(batch.changes)
+	batch.changes = append(batch.changes, BatchChange{x, y, z, block})
+}
+
+// Get returns the block at the given world position in the batch, and a bool indicating if the batch changes the position.
+func (batch *Batch) Get(x, y, z int) (Block, bool) {
+	if index, ok := batch.indices[[3]int{x, y, z}]; ok {
+		return batch.changes[index].Block, true
+	}
+	return nil, false
+}
+
+// GetChanges returns all changes in the batch, in the order their positions were first set.
+// The slice returned should not be modified.
+func (batch *Batch) GetChanges() []BatchChange {
+	return batch.changes
+}
+
+// Len returns the count of positions changed by the batch.
+func (batch *Batch) Len() int {
+	return len(batch.changes)
+}
+
+// BlockSource is a source of blocks batches can be captured from, such as a dimension.
+type BlockSource interface {
+	// GetRawBlockAt returns the block ID, block data and block NBT at the given world position.
+	GetRawBlockAt(x, y, z int) (byte, byte, *gonbt.Compound, error)
+}
+
+// NewLegacyBlock returns a new block with the given block ID, block data and block NBT,
+// with the name and runtime ID of the block in the runtime IDs table.
+func NewLegacyBlock(id, data byte, nbt *gonbt.Compound) *BlockInstance {
+	var name, _ = GetBlockName(int(id), int(data))
+	var runtimeId, _ = GetRuntimeId(int(id), int(data))
+	var block = New(NewBlockState(name, int32(runtimeId), id, data))
+	block.SetNBT(nbt)
+	return block
+}
+
+// Capture returns a batch holding the blocks currently at all positions changed by the batch, read from the source.
+// Applying the returned batch after applying the batch undoes the batch.
+// Positions that could not be read are left out, in which case the first error that occurred is returned.
+func (batch *Batch) Capture(source BlockSource) (*Batch, error) {
+	var captured = NewBatch()
+	var err error
+	for _, change := range batch.changes {
+		var id, data, nbt, readErr = source.GetRawBlockAt(change.X, change.Y, change.Z)
+		if readErr != nil {
+			if err == nil {
+				err = readErr
+			}
+			continue
+		}
+		captured.Set(change.X, change.Y, change.Z, NewLegacyBlock(id, data, nbt))
+	}
+	return captured, err
+}
@@ -1,58 +1,219 @@
 package blocks
 
 import (
+	"crypto/sha256"
+	"errors"
 	"github.com/irmine/binutils"
-	"github.com/irmine/gomine/text"
-	"gopkg.in/yaml.v2"
+	"sync"
 )
 
 var runtimeIdsTable []byte
+var runtimeIdsTableHash [sha256.Size]byte
 var legacyToRuntimeId = make(map[int]uint32)
 var runtimeToLegacyId = make(map[uint32]int)
+var legacyIdToName = make(map[int]string)
+var runtimeIdsMutex sync.RWMutex
 
-func registerRuntimeIds() []byte {
-	if len(runtimeIdsTable) == 0 {
-		var data interface{}
-		err := yaml.Unmarshal([]byte(RuntimeIdsTable__), &data)
-		if err != nil {
-			text.DefaultLogger.Error(err)
-			return nil
-		}
-		stream := binutils.NewStream()
-		stream.ResetStream()
-		if data2, ok := data.([]interface{}); ok {
-			stream.PutUnsignedVarInt(uint32(len(data2)))
-			for k, v := range data2 {
-				if v2, ok := v.(map[interface{}]interface{}); ok {
-					blockId := v2["id"].(int)
-					blockData := v2["data"].(int)
-
-					stream.PutString(v2["name"].(string))
-					stream.PutLittleShort(int16(blockData))
-
-					legacyToRuntimeId[(blockId << 4) | blockData] = uint32(k)
-					runtimeToLegacyId[uint32(k)] = (blockId << 4) | blockData
-				}
-			}
+var (
+	// InvalidRuntimeIdsTable gets returned if the runtime IDs table is not a list.
+	InvalidRuntimeIdsTable = errors.New("runtime IDs table is not a list of entries")
+	// InvalidRuntimeIdEntry gets returned if an entry of the runtime IDs table is missing a name, ID or data.
+	InvalidRuntimeIdEntry = errors.New("runtime IDs table has an invalid entry")
+	// InvalidCustomBlock gets returned if a custom block has no name, or an ID or data out of range.
+	InvalidCustomBlock = errors.New("custom block has an invalid name, ID or data")
+	// DuplicateBlock gets returned if a custom block has an ID and data already in the runtime IDs table.
+	DuplicateBlock = errors.New("block with the given ID and data is already in the runtime IDs table")
+)
+
+// CustomBlock is a non-vanilla block, such as a block added by a behavior pack.
+// Custom blocks get appended to the runtime IDs table after all vanilla blocks.
+type CustomBlock struct {
+	Name string
+	Id   int
+	Data int
+}
+
+// customBlocks are all custom blocks registered, in the order of registration.
+var customBlocks []CustomBlock
+
+// tableEntry is an entry of a runtime IDs table, mapping a block ID and data to a block name.
+type tableEntry struct {
+	name     string
+	id, data int
+}
+
+// runtimeIdsTables are all runtime IDs tables registered, keyed by protocol version.
+var runtimeIdsTables = make(map[int32][]tableEntry)
+
+// selectedTable is the runtime IDs table selected, or nil to use RuntimeIdsTable__.
+var selectedTable []tableEntry
+var selectedProtocol int32
+
+// translation is a built runtime IDs table, with the maps between legacy IDs and runtime IDs of all blocks in it.
+type translation struct {
+	binary          []byte
+	hash            [sha256.Size]byte
+	legacyToRuntime map[int]uint32
+	runtimeToLegacy map[uint32]int
+	names           map[int]string
+}
+
+// buildTranslation builds the runtime IDs table and runtime ID maps of the entries, followed by all custom blocks.
+// Runtime IDs are assigned in the order of the entries in the table, so the output is the same every time.
+func buildTranslation(entries []tableEntry) *translation {
+	var legacyToRuntime = make(map[int]uint32, len(entries)+len(customBlocks))
+	var runtimeToLegacy = make(map[uint32]int, len(entries)+len(customBlocks))
+	var names = make(map[int]string, len(entries)+len(customBlocks))
+
+	var stream = binutils.NewStream()
+	stream.PutUnsignedVarInt(uint32(len(entries) + len(customBlocks)))
+	for k, entry := range entries {
+		stream.PutString(entry.name)
+		stream.PutLittleShort(int16(entry.data))
+
+		legacyToRuntime[(entry.id<<4)|entry.data] = uint32(k)
+		runtimeToLegacy[uint32(k)] = (entry.id << 4) | entry.data
+		names[(entry.id<<4)|entry.data] = entry.name
+	}
+	for k, block := range customBlocks {
+		var runtimeId = uint32(len(entries) + k)
+		stream.PutString(block.Name)
+		stream.PutLittleShort(int16(block.Data))
+
+		legacyToRuntime[(block.Id<<4)|block.Data] = runtimeId
+		runtimeToLegacy[runtimeId] = (block.Id << 4) | block.Data
+		names[(block.Id<<4)|block.Data] = block.Name
+	}
+	var binary = stream.GetBuffer()
+	return &translation{binary, sha256.Sum256(binary), legacyToRuntime, runtimeToLegacy, names}
+}
+
+// registerRuntimeIds builds the runtime IDs table and the runtime ID maps from the selected table, or RuntimeIdsTable__ if none was selected.
+// The existing table and maps are only replaced if building succeeded.
+func registerRuntimeIds() error {
+	var entries = selectedTable
+	if entries == nil {
+		var err error
+		if entries, err = parseJSONTable([]byte(RuntimeIdsTable__)); err != nil {
+			return err
 		}
-		runtimeIdsTable = stream.GetBuffer()
 	}
-	return runtimeIdsTable
+	var built = buildTranslation(entries)
+	runtimeIdsTable = built.binary
+	runtimeIdsTableHash = built.hash
+	legacyToRuntimeId = built.legacyToRuntime
+	runtimeToLegacyId = built.runtimeToLegacy
+	legacyIdToName = built.names
+	return nil
 }
 
-func GetRuntimeIdsTable() []byte {
-	if len(runtimeIdsTable) == 0 {
-		registerRuntimeIds()
+// ensureRuntimeIds builds the runtime IDs table if it was not yet built.
+func ensureRuntimeIds() error {
+	runtimeIdsMutex.RLock()
+	var built = len(runtimeIdsTable) != 0
+	runtimeIdsMutex.RUnlock()
+	if built {
+		return nil
+	}
+	runtimeIdsMutex.Lock()
+	defer runtimeIdsMutex.Unlock()
+	if len(runtimeIdsTable) != 0 {
+		return nil
 	}
-	return runtimeIdsTable
+	return registerRuntimeIds()
+}
+
+// RebuildRuntimeIdsTable rebuilds the runtime IDs table and runtime ID maps.
+// The previous table stays in use if an error is returned.
+func RebuildRuntimeIdsTable() error {
+	runtimeIdsMutex.Lock()
+	defer runtimeIdsMutex.Unlock()
+	return registerRuntimeIds()
 }
 
+// RegisterCustomBlock adds a custom block to the runtime IDs table, and returns the runtime ID it got assigned.
+// Custom blocks should be registered at startup, before the runtime IDs table gets sent to any client.
+// Returns DuplicateBlock if a block with the same ID and data is already in the table.
+func RegisterCustomBlock(block CustomBlock) (uint32, error) {
+	if block.Name == "" || block.Id < 0 || block.Id > 255 || block.Data < 0 || block.Data > 15 {
+		return 0, InvalidCustomBlock
+	}
+	if err := ensureRuntimeIds(); err != nil {
+		return 0, err
+	}
+	runtimeIdsMutex.Lock()
+	defer runtimeIdsMutex.Unlock()
+	if _, ok := legacyToRuntimeId[(block.Id<<4)|block.Data]; ok {
+		return 0, DuplicateBlock
+	}
+	customBlocks = append(customBlocks, block)
+	if err := registerRuntimeIds(); err != nil {
+		customBlocks = customBlocks[:len(customBlocks)-1]
+		return 0, err
+	}
+	return legacyToRuntimeId[(block.Id<<4)|block.Data], nil
+}
+
+// GetCustomBlocks returns all custom blocks registered, in the order of registration.
+func GetCustomBlocks() []CustomBlock {
+	runtimeIdsMutex.RLock()
+	defer runtimeIdsMutex.RUnlock()
+	return append([]CustomBlock{}, customBlocks...)
+}
+
+// GetRuntimeIdsTable returns the runtime IDs table used for network sending, building it if needed.
+// The table contains all vanilla blocks, followed by all custom blocks registered.
+// Returns an error if the table could not be built.
+func GetRuntimeIdsTable() ([]byte, error) {
+	if err := ensureRuntimeIds(); err != nil {
+		return nil, err
+	}
+	runtimeIdsMutex.RLock()
+	defer runtimeIdsMutex.RUnlock()
+	return runtimeIdsTable, nil
+}
+
+// GetRuntimeIdsTableHash returns the SHA-256 hash of the runtime IDs table, building it if needed.
+// The hash stays the same as long as the table does, and can be used to cache the table.
+func GetRuntimeIdsTableHash() ([sha256.Size]byte, error) {
+	if err := ensureRuntimeIds(); err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	runtimeIdsMutex.RLock()
+	defer runtimeIdsMutex.RUnlock()
+	return runtimeIdsTableHash, nil
+}
+
+// GetRuntimeId returns the runtime ID of the given block ID and block data, and a bool indicating if it was found.
 func GetRuntimeId(blockId, blockData int) (uint32, bool) {
-	v, ok := legacyToRuntimeId[(blockId << 4) | blockData]
+	if ensureRuntimeIds() != nil {
+		return 0, false
+	}
+	runtimeIdsMutex.RLock()
+	v, ok := legacyToRuntimeId[(blockId<<4)|blockData]
+	runtimeIdsMutex.RUnlock()
 	return v, ok
 }
 
+// GetLegacyId returns the legacy ID of the given runtime ID, and a bool indicating if it was found.
+// The legacy ID is the block ID shifted left by 4, OR'd with the block data.
 func GetLegacyId(runtimeId uint32) (int, bool) {
+	if ensureRuntimeIds() != nil {
+		return 0, false
+	}
+	runtimeIdsMutex.RLock()
 	v, ok := runtimeToLegacyId[runtimeId]
+	runtimeIdsMutex.RUnlock()
 	return v, ok
-}
\ No newline at end of file
+}
+
+// GetBlockName returns the Minecraft name of the given block ID and block data, and a bool indicating if it was found.
+func GetBlockName(blockId, blockData int) (string, bool) {
+	if ensureRuntimeIds() != nil {
+		return "", false
+	}
+	runtimeIdsMutex.RLock()
+	v, ok := legacyIdToName[(blockId<<4)|blockData]
+	runtimeIdsMutex.RUnlock()
+	return v, ok
+}
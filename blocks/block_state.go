@@ -7,11 +7,15 @@ type BlockState struct {
 	runtimeId int32
 	LegacyId  byte
 	data      byte
+
+	properties Properties
 }
 
 // NewBlockState returns a new block state with the given name, ID and data.
+// The block state has the properties registered for its legacy ID.
 func NewBlockState(name string, runtimeId int32, LegacyId, data byte) *BlockState {
-	return &BlockState{name, runtimeId,  LegacyId, data}
+	var properties, _ = GetProperties(LegacyId)
+	return &BlockState{name, runtimeId,  LegacyId, data, properties}
 }
 
 // GetName returns the Minecraft name of the block state.
@@ -52,3 +56,48 @@ func (state *BlockState) GetPersistentId() *gonbt.Compound {
 		"val":  gonbt.NewInt("val", int32(state.data)),
 	})
 }
+
+// GetProperties returns the properties of the block state.
+func (state *BlockState) GetProperties() Properties {
+	return state.properties
+}
+
+// SetProperties sets the properties of the block state.
+func (state *BlockState) SetProperties(properties Properties) {
+	state.properties = properties
+}
+
+// GetHardness returns the hardness of the block state, or Unbreakable if it cannot be broken.
+func (state *BlockState) GetHardness() float32 {
+	return state.properties.Hardness
+}
+
+// GetBlastResistance returns the blast resistance of the block state.
+func (state *BlockState) GetBlastResistance() float32 {
+	return state.properties.BlastResistance
+}
+
+// GetTool returns the type of tool breaking the block state faster.
+func (state *BlockState) GetTool() ToolType {
+	return state.properties.Tool
+}
+
+// GetTier returns the lowest tier of tool needed for the block state to drop.
+func (state *BlockState) GetTier() ToolTier {
+	return state.properties.Tier
+}
+
+// GetFlameEncouragement returns the chance of the block state catching fire.
+func (state *BlockState) GetFlameEncouragement() byte {
+	return state.properties.FlameEncouragement
+}
+
+// GetFlammability returns the chance of the block state burning away once on fire.
+func (state *BlockState) GetFlammability() byte {
+	return state.properties.Flammability
+}
+
+// GetLightEmission returns the light level emitted by the block state.
+func (state *BlockState) GetLightEmission() byte {
+	return GetLightEmission(state.LegacyId)
+}
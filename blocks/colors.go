@@ -0,0 +1,182 @@
+package blocks
+
+import (
+	"image/color"
+	"sync"
+)
+
+// Tint is the biome color a block is tinted with, such as grass being greener in jungles than in deserts.
+type Tint byte
+
+const (
+	TintNone Tint = iota
+	TintGrass
+	TintFoliage
+	TintWater
+)
+
+// BiomeColors are the colors blocks with a tint get in a biome.
+type BiomeColors struct {
+	Grass   color.RGBA
+	Foliage color.RGBA
+	Water   color.RGBA
+}
+
+// rgb returns an opaque color with the given hexadecimal RGB value.
+func rgb(value uint32) color.RGBA {
+	return color.RGBA{R: byte(value >> 16), G: byte(value >> 8), B: byte(value), A: 0xff}
+}
+
+// Map colors shared by many blocks.
+var (
+	colorGrass    = rgb(0x7fb238)
+	colorSand     = rgb(0xf7e9a3)
+	colorWool     = rgb(0xc7c7c7)
+	colorFire     = rgb(0xff0000)
+	colorIce      = rgb(0xa0a0ff)
+	colorMetal    = rgb(0xa7a7a7)
+	colorPlant    = rgb(0x007c00)
+	colorSnow     = rgb(0xffffff)
+	colorClay     = rgb(0xa4a8b8)
+	colorDirt     = rgb(0x976d4d)
+	colorStone    = rgb(0x707070)
+	colorWater    = rgb(0x4040ff)
+	colorWood     = rgb(0x8f7748)
+	colorQuartz   = rgb(0xfffcf5)
+	colorGold     = rgb(0xfaee4d)
+	colorDiamond  = rgb(0x5cdbd5)
+	colorLapis    = rgb(0x4a80ff)
+	colorEmerald  = rgb(0x00d93a)
+	colorPodzol   = rgb(0x815631)
+	colorNether   = rgb(0x700200)
+	colorObsidian = rgb(0x191919)
+)
+
+// dyeColors are the map colors of dyed blocks such as wool, indexed by block data.
+var dyeColors = [16]color.RGBA{
+	rgb(0xffffff), rgb(0xd87f33), rgb(0xb24cd8), rgb(0x6699d8), rgb(0xe5e533), rgb(0x7fcc19), rgb(0xf27fa5), rgb(0x4c4c4c),
+	rgb(0x999999), rgb(0x4c7f99), rgb(0x7f3fb2), rgb(0x334cb2), rgb(0x664c33), rgb(0x667f33), rgb(0x993333), rgb(0x191919),
+}
+
+// dyedBlocks are the IDs of blocks whose color is picked from the dye colors by their data.
+var dyedBlocks = map[byte]bool{35: true, 95: true, 159: true, 160: true, 171: true, 236: true, 237: true}
+
+var colorsMutex sync.RWMutex
+
+// blockColors are the map colors of blocks, keyed by block ID. Blocks not in the map have no color, and are transparent on maps.
+var blockColors = map[byte]color.RGBA{
+	1: colorStone, 2: colorGrass, 3: colorDirt, 4: colorStone, 5: colorWood, 6: colorPlant, 7: colorStone,
+	8: colorWater, 9: colorWater, 10: colorFire, 11: colorFire, 12: colorSand, 13: colorStone, 14: colorStone,
+	15: colorStone, 16: colorStone, 17: colorWood, 18: colorPlant, 19: rgb(0xe5e533), 21: colorStone, 22: colorLapis,
+	23: colorStone, 24: colorSand, 25: colorWood, 26: colorWool, 29: colorStone, 30: colorWool, 31: colorPlant,
+	32: colorWood, 33: colorStone, 37: colorPlant, 38: colorPlant, 39: colorPlant, 40: colorPlant, 41: colorGold,
+	42: colorMetal, 43: colorStone, 44: colorStone, 45: rgb(0x993333), 46: colorFire, 47: colorWood, 48: colorStone,
+	49: colorObsidian, 51: colorFire, 52: colorStone, 53: colorWood, 54: colorWood, 56: colorStone, 57: colorDiamond,
+	58: colorWood, 59: colorPlant, 60: colorDirt, 61: colorStone, 62: colorStone, 63: colorWood, 64: colorWood,
+	67: colorStone, 68: colorWood, 71: colorMetal, 73: colorStone, 74: colorStone, 78: colorSnow, 79: colorIce,
+	80: colorSnow, 81: colorPlant, 82: colorClay, 83: colorPlant, 85: colorWood, 86: rgb(0xd87f33), 87: colorNether,
+	88: rgb(0x664c33), 89: colorSand, 91: rgb(0xd87f33), 97: colorClay, 98: colorStone, 99: colorDirt, 100: colorDirt,
+	101: colorMetal, 103: rgb(0x7fcc19), 106: colorPlant, 107: colorWood, 108: rgb(0x993333), 109: colorStone,
+	110: rgb(0x7f3fb2), 111: colorPlant, 112: colorNether, 113: colorNether, 114: colorNether, 121: colorSand,
+	125: colorWood, 126: colorWood, 128: colorSand, 129: colorStone, 133: colorEmerald, 134: colorPodzol,
+	135: colorSand, 136: colorWood, 139: colorStone, 141: colorPlant, 142: colorPlant, 152: colorFire, 153: colorNether,
+	155: colorQuartz, 156: colorQuartz, 157: colorMetal, 161: colorPlant, 162: colorWood, 163: rgb(0xd87f33),
+	164: rgb(0x664c33), 170: rgb(0xe5e533), 172: rgb(0xd87f33), 173: colorObsidian, 174: colorIce, 175: colorPlant,
+	179: rgb(0xd87f33), 180: rgb(0xd87f33), 198: colorQuartz, 201: rgb(0xb24cd8), 203: rgb(0xb24cd8), 206: colorSand,
+	213: colorNether, 214: colorNether, 215: colorNether, 216: colorSand, 243: colorPodzol,
+}
+
+// tints are the tints of blocks, keyed by block ID. Blocks not in the map are not tinted.
+var tints = map[byte]Tint{
+	2: TintGrass, 31: TintGrass, 175: TintGrass, 18: TintFoliage, 161: TintFoliage, 106: TintFoliage,
+	8: TintWater, 9: TintWater,
+}
+
+// defaultBiomeColors are the colors of biomes without colors registered.
+var defaultBiomeColors = BiomeColors{rgb(0x91bd59), rgb(0x77ab2f), rgb(0x3f76e4)}
+
+// biomeColors are the colors of biomes, keyed by biome ID.
+var biomeColors = map[byte]BiomeColors{
+	0:  {rgb(0x8eb971), rgb(0x71a74d), rgb(0x3f76e4)},
+	1:  {rgb(0x91bd59), rgb(0x77ab2f), rgb(0x3f76e4)},
+	2:  {rgb(0xbfb755), rgb(0xaea42a), rgb(0x3f76e4)},
+	3:  {rgb(0x8ab689), rgb(0x6da36b), rgb(0x3f76e4)},
+	4:  {rgb(0x79c05a), rgb(0x59ae30), rgb(0x3f76e4)},
+	5:  {rgb(0x86b783), rgb(0x68a464), rgb(0x3f76e4)},
+	6:  {rgb(0x6a7039), rgb(0x6a7039), rgb(0x617b64)},
+	7:  {rgb(0x8eb971), rgb(0x71a74d), rgb(0x3f76e4)},
+	8:  {rgb(0xbfb755), rgb(0xaea42a), rgb(0x3f76e4)},
+	9:  {rgb(0x8eb971), rgb(0x71a74d), rgb(0x3f76e4)},
+	10: {rgb(0x80b497), rgb(0x60a17b), rgb(0x3938c9)},
+	11: {rgb(0x80b497), rgb(0x60a17b), rgb(0x3938c9)},
+	12: {rgb(0x80b497), rgb(0x60a17b), rgb(0x3f76e4)},
+	21: {rgb(0x59c93c), rgb(0x30bb0b), rgb(0x3f76e4)},
+	27: {rgb(0x88bb67), rgb(0x6ba941), rgb(0x3f76e4)},
+	29: {rgb(0x507a32), rgb(0x59ae30), rgb(0x3f76e4)},
+	35: {rgb(0xbfb755), rgb(0xaea42a), rgb(0x3f76e4)},
+	37: {rgb(0x90814d), rgb(0x9e814d), rgb(0x3f76e4)},
+}
+
+// GetBlockColor returns the map color of the block with the given ID and data, without any biome tint,
+// and a bool indicating if the block has a color. Blocks without a color, such as air, are transparent on maps.
+func GetBlockColor(blockId, blockData byte) (color.RGBA, bool) {
+	colorsMutex.RLock()
+	defer colorsMutex.RUnlock()
+	if dyedBlocks[blockId] {
+		return dyeColors[blockData&0x0f], true
+	}
+	var blockColor, ok = blockColors[blockId]
+	return blockColor, ok
+}
+
+// SetBlockColor sets the map color and tint of the block with the given ID.
+// Custom blocks must be registered this way in order to show up on maps.
+func SetBlockColor(blockId byte, blockColor color.RGBA, tint Tint) {
+	colorsMutex.Lock()
+	delete(dyedBlocks, blockId)
+	blockColors[blockId] = blockColor
+	tints[blockId] = tint
+	colorsMutex.Unlock()
+}
+
+// GetTint returns the tint of the block with the given ID.
+func GetTint(blockId byte) Tint {
+	colorsMutex.RLock()
+	defer colorsMutex.RUnlock()
+	return tints[blockId]
+}
+
+// GetBiomeColors returns the colors tinted blocks get in the biome with the given ID.
+func GetBiomeColors(biome byte) BiomeColors {
+	colorsMutex.RLock()
+	defer colorsMutex.RUnlock()
+	if colors, ok := biomeColors[biome]; ok {
+		return colors
+	}
+	return defaultBiomeColors
+}
+
+// SetBiomeColors sets the colors tinted blocks get in the biome with the given ID.
+func SetBiomeColors(biome byte, colors BiomeColors) {
+	colorsMutex.Lock()
+	biomeColors[biome] = colors
+	colorsMutex.Unlock()
+}
+
+// GetMapColor returns the color of the block with the given ID and data in the biome with the given ID, as drawn on maps.
+// Tinted blocks get the color of the biome, and blocks without a color are fully transparent.
+func GetMapColor(blockId, blockData, biome byte) color.RGBA {
+	var blockColor, ok = GetBlockColor(blockId, blockData)
+	if !ok {
+		return color.RGBA{}
+	}
+	switch GetTint(blockId) {
+	case TintGrass:
+		return GetBiomeColors(biome).Grass
+	case TintFoliage:
+		return GetBiomeColors(biome).Foliage
+	case TintWater:
+		return GetBiomeColors(biome).Water
+	}
+	return blockColor
+}
@@ -0,0 +1,143 @@
+package blocks
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Drop is an item dropped when a block is broken.
+// The count dropped is picked at random between MinCount and MaxCount, including both.
+// Drops with a MinCount of 0 may not drop at all.
+type Drop struct {
+	ItemId   int16
+	ItemData int16
+	MinCount int
+	MaxCount int
+}
+
+// ItemStack is an amount of an item, as dropped by a broken block.
+type ItemStack struct {
+	ItemId   int16
+	ItemData int16
+	Count    int
+}
+
+// DropTable holds the drops of a block when broken normally, and when broken with silk touch.
+type DropTable struct {
+	// Drops are the items dropped when broken without silk touch.
+	Drops []Drop
+	// SilkTouchDrops are the items dropped when broken with silk touch.
+	// The drops without silk touch are used if nil.
+	SilkTouchDrops []Drop
+	// KeepData specifies if the data of the block broken is used as item data for all drops with an item data of -1.
+	KeepData bool
+}
+
+// Tool is the tool a block gets broken with.
+type Tool struct {
+	Type      ToolType
+	Tier      ToolTier
+	SilkTouch bool
+}
+
+// NoTool is the tool used when breaking blocks by hand.
+var NoTool = Tool{ToolNone, TierNone, false}
+
+// self returns drops of the block itself, keeping the data of the block broken.
+func self(blockId byte) []Drop {
+	return []Drop{{int16(blockId), -1, 1, 1}}
+}
+
+// single returns drops of a single item with the given ID and data.
+func single(itemId, itemData int16, min, max int) []Drop {
+	return []Drop{{itemId, itemData, min, max}}
+}
+
+var dropsMutex sync.RWMutex
+
+// dropTables are the drop tables of blocks not dropping themselves, keyed by block ID.
+var dropTables = map[byte]DropTable{
+	1:   {single(4, 0, 1, 1), self(1), true},
+	2:   {single(3, 0, 1, 1), self(2), false},
+	13:  {single(13, 0, 1, 1), self(13), false},
+	16:  {single(263, 0, 1, 1), self(16), false},
+	18:  {single(6, 0, 0, 1), self(18), true},
+	20:  {nil, self(20), true},
+	21:  {single(351, 4, 4, 8), self(21), false},
+	30:  {single(287, 0, 1, 1), self(30), false},
+	31:  {single(295, 0, 0, 1), self(31), true},
+	32:  {single(280, 0, 0, 2), self(32), false},
+	47:  {single(340, 0, 3, 3), self(47), false},
+	56:  {single(264, 0, 1, 1), self(56), false},
+	59:  {single(295, 0, 1, 1), nil, false},
+	73:  {single(331, 0, 4, 5), self(73), false},
+	74:  {single(331, 0, 4, 5), self(73), false},
+	78:  {single(332, 0, 1, 1), self(78), false},
+	79:  {nil, self(79), false},
+	80:  {single(332, 0, 4, 4), self(80), false},
+	82:  {single(337, 0, 4, 4), self(82), false},
+	89:  {single(348, 0, 2, 4), self(89), false},
+	102: {nil, self(102), true},
+	103: {single(360, 0, 3, 7), self(103), false},
+	110: {single(3, 0, 1, 1), self(110), false},
+	129: {single(388, 0, 1, 1), self(129), false},
+	153: {single(406, 0, 1, 1), self(153), false},
+	160: {nil, self(160), true},
+	161: {single(6, 4, 0, 1), self(161), true},
+	174: {nil, self(174), false},
+	243: {single(3, 0, 1, 1), self(243), false},
+}
+
+// GetDropTable returns the drop table of the block with the given ID.
+// Blocks without a drop table registered drop themselves.
+func GetDropTable(blockId byte) DropTable {
+	dropsMutex.RLock()
+	defer dropsMutex.RUnlock()
+	if table, ok := dropTables[blockId]; ok {
+		return table
+	}
+	return DropTable{self(blockId), nil, true}
+}
+
+// SetDropTable sets the drop table of the block with the given ID.
+// Custom blocks must be registered this way in order to drop anything other than themselves.
+func SetDropTable(blockId byte, table DropTable) {
+	dropsMutex.Lock()
+	dropTables[blockId] = table
+	dropsMutex.Unlock()
+}
+
+// GetDrops returns the items dropped by the block with the given ID and data when broken with the tool.
+// Blocks the tool cannot harvest, such as stone broken by hand, drop nothing. Air never drops anything.
+func GetDrops(blockId, blockData byte, tool Tool, random *rand.Rand) []ItemStack {
+	if blockId == 0 {
+		return nil
+	}
+	if properties, ok := GetProperties(blockId); ok && !properties.CanHarvest(tool.Type, tool.Tier) {
+		return nil
+	}
+	var table = GetDropTable(blockId)
+	var drops = table.Drops
+	if tool.SilkTouch && table.SilkTouchDrops != nil {
+		drops = table.SilkTouchDrops
+	}
+	var stacks []ItemStack
+	for _, drop := range drops {
+		var count = drop.MinCount
+		if drop.MaxCount > drop.MinCount {
+			count += random.Intn(drop.MaxCount - drop.MinCount + 1)
+		}
+		if count <= 0 {
+			continue
+		}
+		var data = drop.ItemData
+		if data == -1 {
+			data = 0
+			if table.KeepData {
+				data = int16(blockData)
+			}
+		}
+		stacks = append(stacks, ItemStack{drop.ItemId, data, count})
+	}
+	return stacks
+}
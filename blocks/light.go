@@ -0,0 +1,48 @@
+package blocks
+
+import "sync"
+
+// FullOpacity is the light filter of opaque blocks, which let through no light at all.
+const FullOpacity byte = 15
+
+var lightMutex sync.RWMutex
+
+// lightEmissions are the light levels emitted by blocks, keyed by block ID.
+// Blocks not in the map do not emit light.
+var lightEmissions = map[byte]byte{
+	10: 15, 11: 15, 39: 1, 50: 14, 51: 15, 62: 13, 74: 9, 76: 7, 89: 15, 90: 11, 91: 15, 94: 9,
+	117: 1, 119: 15, 124: 15, 130: 7, 138: 15, 169: 15, 198: 14, 213: 3,
+}
+
+// lightFilters are the light levels filtered by blocks light passes through, keyed by block ID.
+// Blocks not in the map have no filter registered.
+var lightFilters = map[byte]byte{
+	8: 2, 9: 2, 18: 1, 20: 0, 30: 1, 44: 0, 53: 0, 54: 0, 67: 0, 79: 2, 85: 0, 89: 0, 95: 0, 101: 0,
+	102: 0, 107: 0, 108: 0, 109: 0, 113: 0, 114: 0, 126: 0, 128: 0, 134: 0, 135: 0, 136: 0, 138: 0,
+	146: 0, 156: 0, 160: 0, 161: 1, 163: 0, 164: 0, 180: 0, 182: 0, 203: 0,
+}
+
+// GetLightEmission returns the light level emitted by the block with the given ID.
+func GetLightEmission(blockId byte) byte {
+	lightMutex.RLock()
+	defer lightMutex.RUnlock()
+	return lightEmissions[blockId]
+}
+
+// GetLightFilter returns the light level filtered by the block with the given ID, and a bool indicating if the block has a filter registered.
+// Blocks without a filter registered should be treated as opaque if solid, and as letting through all light otherwise.
+func GetLightFilter(blockId byte) (byte, bool) {
+	lightMutex.RLock()
+	defer lightMutex.RUnlock()
+	var filter, ok = lightFilters[blockId]
+	return filter, ok
+}
+
+// SetLight sets the light emission and filter of the block with the given ID.
+// Custom blocks must be registered this way in order to emit or let through light.
+func SetLight(blockId, emission, filter byte) {
+	lightMutex.Lock()
+	lightEmissions[blockId] = emission & 0x0f
+	lightFilters[blockId] = filter & 0x0f
+	lightMutex.Unlock()
+}
@@ -1,40 +1,217 @@
 package blocks
 
-import "errors"
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
 
 // Manager manages blocks and has utility functions for registering those.
-type Manager map[byte]func(data byte) Block
+// Blocks may be registered while the manager is in use. Once frozen, the manager can no longer be changed,
+// and blocks are returned without locking.
+type Manager struct {
+	frozen   int32
+	mutex    sync.RWMutex
+	blocks   map[byte]func(data byte) Block
+	variants map[StateKey]func() Block
+	fallback FallbackFunction
+}
+
+// StateKey is the block ID and data of a block state.
+type StateKey struct {
+	Id   byte
+	Data byte
+}
 
-// UnregisteredBlock gets returned if an unregistered block gets requested.
+// FallbackFunction returns a block for a block ID that was not registered.
+type FallbackFunction func(blockId, blockData byte) Block
+
+// UnregisteredBlock gets returned if an unregistered block gets requested from a manager without fallback.
 var UnregisteredBlock = errors.New("block is not registered")
 
-// NewManager returns a new blocks manager.
-func NewManager() Manager {
-	return Manager{}
+// InfoUpdateId is the legacy block ID of the info update block, shown by clients for unknown blocks.
+const InfoUpdateId = 248
+
+// NewManager returns a new blocks manager without fallback.
+func NewManager() *Manager {
+	return &Manager{0, sync.RWMutex{}, make(map[byte]func(data byte) Block), make(map[StateKey]func() Block), nil}
+}
+
+// Freeze freezes the manager, after which blocks can no longer be registered or deregistered, and the fallback can no longer be set.
+// Calls changing a frozen manager do nothing. Freeze should be called once all blocks are registered, to stop locking on every block returned.
+func (manager *Manager) Freeze() {
+	manager.mutex.Lock()
+	atomic.StoreInt32(&manager.frozen, 1)
+	manager.mutex.Unlock()
+}
+
+// IsFrozen checks if the manager is frozen and can no longer be changed.
+func (manager *Manager) IsFrozen() bool {
+	return atomic.LoadInt32(&manager.frozen) == 1
+}
+
+// rLock read locks the manager if it is not frozen, and returns if it did. Frozen managers are never written to, so they need no locking.
+func (manager *Manager) rLock() bool {
+	if manager.IsFrozen() {
+		return false
+	}
+	manager.mutex.RLock()
+	return true
+}
+
+// rUnlock read unlocks the manager if it was read locked by rLock.
+func (manager *Manager) rUnlock(locked bool) {
+	if locked {
+		manager.mutex.RUnlock()
+	}
 }
 
 // Register registers a new block function for the given block ID.
 // Register overwrites any blocks that might have been previously registered on the ID.
-func (manager Manager) Register(blockId byte, blockFunc func(data byte) Block) {
-	manager[blockId] = blockFunc
+func (manager *Manager) Register(blockId byte, blockFunc func(data byte) Block) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	if manager.IsFrozen() {
+		return
+	}
+	manager.blocks[blockId] = blockFunc
+}
+
+// RegisterVariant registers a new block function for the given block ID and data, such as a single wool color.
+// Variants take precedence over the block function registered for the whole block ID.
+// RegisterVariant overwrites any variant that might have been previously registered on the ID and data.
+func (manager *Manager) RegisterVariant(blockId, blockData byte, blockFunc func() Block) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	if manager.IsFrozen() {
+		return
+	}
+	manager.variants[StateKey{blockId, blockData}] = blockFunc
+}
+
+// Deregister deregisters the block function with the given block ID, and all its variants.
+func (manager *Manager) Deregister(blockId byte) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	if manager.IsFrozen() {
+		return
+	}
+	delete(manager.blocks, blockId)
+	for key := range manager.variants {
+		if key.Id == blockId {
+			delete(manager.variants, key)
+		}
+	}
+}
+
+// DeregisterVariant deregisters the variant with the given block ID and data.
+func (manager *Manager) DeregisterVariant(blockId, blockData byte) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	if manager.IsFrozen() {
+		return
+	}
+	delete(manager.variants, StateKey{blockId, blockData})
 }
 
-// Deregister deregisters the block function with the given block ID.
-func (manager Manager) Deregister(blockId byte) {
-	delete(manager, blockId)
+// IsRegistered checks if a block function or any variant with the given block ID is registered.
+func (manager *Manager) IsRegistered(blockId byte) bool {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+	if _, ok := manager.blocks[blockId]; ok {
+		return true
+	}
+	for key := range manager.variants {
+		if key.Id == blockId {
+			return true
+		}
+	}
+	return false
 }
 
-// IsRegistered checks if a block function with the given block ID is registered.
-func (manager Manager) IsRegistered(blockId byte) bool {
-	var _, ok = manager[blockId]
+// IsVariantRegistered checks if the block with the given block ID and data can be returned without fallback,
+// either by a variant or by the block function of the block ID.
+func (manager *Manager) IsVariantRegistered(blockId, blockData byte) bool {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+	if _, ok := manager.variants[StateKey{blockId, blockData}]; ok {
+		return true
+	}
+	var _, ok = manager.blocks[blockId]
 	return ok
 }
 
-// Get returns a block by its block ID and block data.
-// Returns an error if a block with the given block ID was not registered.
-func (manager Manager) Get(blockId byte, blockData byte) (Block, error) {
-	if !manager.IsRegistered(blockId) {
-		return nil, UnregisteredBlock
+// GetStates returns the block IDs and data of all states registered, sorted by block ID and data.
+// Block IDs with a block function registered for the whole ID have all 16 data values included.
+func (manager *Manager) GetStates() []StateKey {
+	manager.mutex.RLock()
+	var keys = make(map[StateKey]bool, len(manager.variants))
+	for key := range manager.variants {
+		keys[key] = true
+	}
+	for id := range manager.blocks {
+		for data := byte(0); data < 16; data++ {
+			keys[StateKey{id, data}] = true
+		}
+	}
+	manager.mutex.RUnlock()
+
+	var states = make([]StateKey, 0, len(keys))
+	for key := range keys {
+		states = append(states, key)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Id != states[j].Id {
+			return states[i].Id < states[j].Id
+		}
+		return states[i].Data < states[j].Data
+	})
+	return states
+}
+
+// SetFallback sets the function returning blocks for block IDs that were not registered.
+// Get returns UnregisteredBlock for those block IDs if nil.
+func (manager *Manager) SetFallback(function FallbackFunction) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	if manager.IsFrozen() {
+		return
 	}
-	return manager[blockId](blockData), nil
+	manager.fallback = function
+}
+
+// GetFallback returns the function returning blocks for block IDs that were not registered, or nil if not set.
+func (manager *Manager) GetFallback() FallbackFunction {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+	return manager.fallback
+}
+
+// Get returns a block by its block ID and block data, preferring the variant registered for the data.
+// If a block with the given block ID was not registered, the block of the fallback is returned.
+// Returns UnregisteredBlock if the manager has no fallback.
+func (manager *Manager) Get(blockId byte, blockData byte) (Block, error) {
+	var locked = manager.rLock()
+	var variantFunc, isVariant = manager.variants[StateKey{blockId, blockData}]
+	var blockFunc, ok = manager.blocks[blockId]
+	var fallback = manager.fallback
+	manager.rUnlock(locked)
+	if isVariant {
+		return variantFunc(), nil
+	}
+	if ok {
+		return blockFunc(blockData), nil
+	}
+	if fallback != nil {
+		return fallback(blockId, blockData), nil
+	}
+	return nil, UnregisteredBlock
+}
+
+// InfoUpdateFallback is a fallback returning a block keeping the block ID and data, but shown to clients as an info update block.
+// Using it as fallback keeps unknown blocks intact when the chunk holding them gets saved.
+func InfoUpdateFallback(blockId, blockData byte) Block {
+	var runtimeId, _ = GetRuntimeId(InfoUpdateId, 0)
+	return New(NewBlockState("minecraft:info_update", int32(runtimeId), blockId, blockData))
 }
@@ -0,0 +1,92 @@
+package blocks
+
+import "sync"
+
+// ToolType is a type of tool breaking blocks faster.
+type ToolType byte
+
+const (
+	ToolNone ToolType = iota
+	ToolPickaxe
+	ToolAxe
+	ToolShovel
+	ToolHoe
+	ToolSword
+	ToolShears
+)
+
+// ToolTier is a tier of tool, such as wooden or iron.
+type ToolTier byte
+
+const (
+	TierNone ToolTier = iota
+	TierWood
+	TierGold
+	TierStone
+	TierIron
+	TierDiamond
+)
+
+// Unbreakable is the hardness of blocks that cannot be broken, such as bedrock.
+const Unbreakable float32 = -1
+
+// Properties are the properties of a block used for breaking, explosions and fire spread.
+type Properties struct {
+	// Hardness determines how long it takes to break the block. Blocks with a hardness of Unbreakable cannot be broken.
+	Hardness float32
+	// BlastResistance determines how well the block resists explosions.
+	BlastResistance float32
+	// Tool is the type of tool breaking the block faster.
+	Tool ToolType
+	// Tier is the lowest tier of the tool needed for the block to drop when broken.
+	// Blocks with a tier of TierNone always drop.
+	Tier ToolTier
+	// FlameEncouragement is the chance of the block catching fire from fire next to it.
+	FlameEncouragement byte
+	// Flammability is the chance of the block burning away once on fire. Blocks with a flammability of 0 never burn away.
+	Flammability byte
+}
+
+var propertiesMutex sync.RWMutex
+
+// GetProperties returns the properties of the block with the given ID, and a bool indicating if the block has any.
+func GetProperties(blockId byte) (Properties, bool) {
+	propertiesMutex.RLock()
+	defer propertiesMutex.RUnlock()
+	var properties, ok = vanillaProperties[blockId]
+	return properties, ok
+}
+
+// SetProperties sets the properties of the block with the given ID.
+// Custom blocks must be registered this way in order to have properties.
+func SetProperties(blockId byte, properties Properties) {
+	propertiesMutex.Lock()
+	vanillaProperties[blockId] = properties
+	propertiesMutex.Unlock()
+}
+
+// IsBreakable checks if the block can be broken.
+func (properties Properties) IsBreakable() bool {
+	return properties.Hardness >= 0
+}
+
+// IsFlammable checks if the block can catch fire.
+func (properties Properties) IsFlammable() bool {
+	return properties.FlameEncouragement > 0
+}
+
+// CanHarvest checks if the block drops when broken with a tool of the given type and tier.
+func (properties Properties) CanHarvest(tool ToolType, tier ToolTier) bool {
+	if properties.Tier == TierNone {
+		return true
+	}
+	return tool == properties.Tool && tierLevel(tier) >= tierLevel(properties.Tier)
+}
+
+// tierLevel returns the harvest level of the tier. Gold tools harvest the same blocks as wooden tools.
+func tierLevel(tier ToolTier) int {
+	if tier == TierGold {
+		return int(TierWood)
+	}
+	return int(tier)
+}
@@ -0,0 +1,254 @@
+package blocks
+
+// vanillaProperties are the properties of all vanilla blocks, keyed by block ID.
+var vanillaProperties = map[byte]Properties{
+	0:   {Hardness: 0, BlastResistance: 0},
+	1:   {Hardness: 1.5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	2:   {Hardness: 0.6, BlastResistance: 0.6, Tool: ToolShovel},
+	3:   {Hardness: 0.5, BlastResistance: 0.5, Tool: ToolShovel},
+	4:   {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	5:   {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	6:   {Hardness: 0, BlastResistance: 0},
+	7:   {Hardness: -1, BlastResistance: 3600000},
+	8:   {Hardness: 100, BlastResistance: 100},
+	9:   {Hardness: 100, BlastResistance: 100},
+	10:  {Hardness: 100, BlastResistance: 100},
+	11:  {Hardness: 100, BlastResistance: 100},
+	12:  {Hardness: 0.5, BlastResistance: 0.5, Tool: ToolShovel},
+	13:  {Hardness: 0.6, BlastResistance: 0.6, Tool: ToolShovel},
+	14:  {Hardness: 3, BlastResistance: 3, Tool: ToolPickaxe, Tier: TierIron},
+	15:  {Hardness: 3, BlastResistance: 3, Tool: ToolPickaxe, Tier: TierStone},
+	16:  {Hardness: 3, BlastResistance: 3, Tool: ToolPickaxe, Tier: TierWood},
+	17:  {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 5},
+	18:  {Hardness: 0.2, BlastResistance: 0.2, Tool: ToolShears, FlameEncouragement: 30, Flammability: 60},
+	19:  {Hardness: 0.6, BlastResistance: 0.6},
+	20:  {Hardness: 0.3, BlastResistance: 0.3},
+	21:  {Hardness: 3, BlastResistance: 3, Tool: ToolPickaxe, Tier: TierStone},
+	22:  {Hardness: 3, BlastResistance: 3, Tool: ToolPickaxe, Tier: TierStone},
+	23:  {Hardness: 3.5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	24:  {Hardness: 0.8, BlastResistance: 0.8, Tool: ToolPickaxe, Tier: TierWood},
+	25:  {Hardness: 0.8, BlastResistance: 0.8, Tool: ToolAxe},
+	26:  {Hardness: 0.2, BlastResistance: 0.2},
+	27:  {Hardness: 0.7, BlastResistance: 0.7},
+	28:  {Hardness: 0.7, BlastResistance: 0.7},
+	29:  {Hardness: 0.5, BlastResistance: 0.5},
+	30:  {Hardness: 4, BlastResistance: 4, Tool: ToolShears},
+	31:  {Hardness: 0, BlastResistance: 0, Tool: ToolShears, FlameEncouragement: 60, Flammability: 100},
+	32:  {Hardness: 0, BlastResistance: 0, FlameEncouragement: 60, Flammability: 100},
+	33:  {Hardness: 0.5, BlastResistance: 0.5},
+	34:  {Hardness: 0.5, BlastResistance: 0.5},
+	35:  {Hardness: 0.8, BlastResistance: 0.8, Tool: ToolShears, FlameEncouragement: 30, Flammability: 60},
+	36:  {Hardness: 0, BlastResistance: 0},
+	37:  {Hardness: 0, BlastResistance: 0, FlameEncouragement: 60, Flammability: 100},
+	38:  {Hardness: 0, BlastResistance: 0, FlameEncouragement: 60, Flammability: 100},
+	39:  {Hardness: 0, BlastResistance: 0},
+	40:  {Hardness: 0, BlastResistance: 0},
+	41:  {Hardness: 3, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierIron},
+	42:  {Hardness: 5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierStone},
+	43:  {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	44:  {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	45:  {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	46:  {Hardness: 0, BlastResistance: 0, FlameEncouragement: 15, Flammability: 100},
+	47:  {Hardness: 1.5, BlastResistance: 1.5, Tool: ToolAxe, FlameEncouragement: 30, Flammability: 20},
+	48:  {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	49:  {Hardness: 50, BlastResistance: 1200, Tool: ToolPickaxe, Tier: TierDiamond},
+	50:  {Hardness: 0, BlastResistance: 0},
+	51:  {Hardness: 0, BlastResistance: 0},
+	52:  {Hardness: 5, BlastResistance: 5, Tool: ToolPickaxe, Tier: TierWood},
+	53:  {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	54:  {Hardness: 2.5, BlastResistance: 2.5, Tool: ToolAxe},
+	55:  {Hardness: 0, BlastResistance: 0},
+	56:  {Hardness: 3, BlastResistance: 3, Tool: ToolPickaxe, Tier: TierIron},
+	57:  {Hardness: 5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierIron},
+	58:  {Hardness: 2.5, BlastResistance: 2.5, Tool: ToolAxe},
+	59:  {Hardness: 0, BlastResistance: 0},
+	60:  {Hardness: 0.6, BlastResistance: 0.6, Tool: ToolShovel},
+	61:  {Hardness: 3.5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	62:  {Hardness: 3.5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	63:  {Hardness: 1, BlastResistance: 1, Tool: ToolAxe},
+	64:  {Hardness: 3, BlastResistance: 3, Tool: ToolAxe},
+	65:  {Hardness: 0.4, BlastResistance: 0.4},
+	66:  {Hardness: 0.7, BlastResistance: 0.7},
+	67:  {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	68:  {Hardness: 1, BlastResistance: 1, Tool: ToolAxe},
+	69:  {Hardness: 0.5, BlastResistance: 0.5},
+	70:  {Hardness: 0.5, BlastResistance: 0.5},
+	71:  {Hardness: 5, BlastResistance: 5, Tool: ToolPickaxe, Tier: TierWood},
+	72:  {Hardness: 0.5, BlastResistance: 0.5, Tool: ToolAxe},
+	73:  {Hardness: 3, BlastResistance: 3, Tool: ToolPickaxe, Tier: TierIron},
+	74:  {Hardness: 3, BlastResistance: 3, Tool: ToolPickaxe, Tier: TierIron},
+	75:  {Hardness: 0, BlastResistance: 0},
+	76:  {Hardness: 0, BlastResistance: 0},
+	77:  {Hardness: 0.5, BlastResistance: 0.5},
+	78:  {Hardness: 0.1, BlastResistance: 0.1, Tool: ToolShovel},
+	79:  {Hardness: 0.5, BlastResistance: 0.5},
+	80:  {Hardness: 0.2, BlastResistance: 0.2, Tool: ToolShovel},
+	81:  {Hardness: 0.4, BlastResistance: 0.4},
+	82:  {Hardness: 0.6, BlastResistance: 0.6, Tool: ToolShovel},
+	83:  {Hardness: 0, BlastResistance: 0},
+	84:  {Hardness: 2, BlastResistance: 3, Tool: ToolAxe},
+	85:  {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	86:  {Hardness: 1, BlastResistance: 1, Tool: ToolAxe},
+	87:  {Hardness: 0.4, BlastResistance: 0.4, Tool: ToolPickaxe, Tier: TierWood},
+	88:  {Hardness: 0.5, BlastResistance: 0.5, Tool: ToolShovel},
+	89:  {Hardness: 0.3, BlastResistance: 0.3},
+	90:  {Hardness: -1, BlastResistance: 3600000},
+	91:  {Hardness: 1, BlastResistance: 1, Tool: ToolAxe},
+	92:  {Hardness: 0.5, BlastResistance: 0.5},
+	93:  {Hardness: 0, BlastResistance: 0},
+	94:  {Hardness: 0, BlastResistance: 0},
+	95:  {Hardness: -1, BlastResistance: 3600000},
+	96:  {Hardness: 3, BlastResistance: 3, Tool: ToolAxe},
+	97:  {Hardness: 0.75, BlastResistance: 0.75, Tool: ToolPickaxe},
+	98:  {Hardness: 1.5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	99:  {Hardness: 0.2, BlastResistance: 0.2, Tool: ToolAxe},
+	100: {Hardness: 0.2, BlastResistance: 0.2, Tool: ToolAxe},
+	101: {Hardness: 5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	102: {Hardness: 0.3, BlastResistance: 0.3},
+	103: {Hardness: 1, BlastResistance: 1, Tool: ToolAxe},
+	104: {Hardness: 0, BlastResistance: 0},
+	105: {Hardness: 0, BlastResistance: 0},
+	106: {Hardness: 0.2, BlastResistance: 0.2, Tool: ToolShears, FlameEncouragement: 15, Flammability: 100},
+	107: {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	108: {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	109: {Hardness: 1.5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	110: {Hardness: 0.6, BlastResistance: 0.6, Tool: ToolShovel},
+	111: {Hardness: 0, BlastResistance: 0},
+	112: {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	113: {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	114: {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	115: {Hardness: 0, BlastResistance: 0},
+	116: {Hardness: 5, BlastResistance: 1200, Tool: ToolPickaxe, Tier: TierWood},
+	117: {Hardness: 0.5, BlastResistance: 0.5, Tool: ToolPickaxe, Tier: TierWood},
+	118: {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	119: {Hardness: -1, BlastResistance: 3600000},
+	120: {Hardness: -1, BlastResistance: 3600000},
+	121: {Hardness: 3, BlastResistance: 9, Tool: ToolPickaxe, Tier: TierWood},
+	122: {Hardness: 3, BlastResistance: 9, Tool: ToolPickaxe, Tier: TierWood},
+	123: {Hardness: 0.3, BlastResistance: 0.3},
+	124: {Hardness: 0.3, BlastResistance: 0.3},
+	125: {Hardness: 3.5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	126: {Hardness: 0.7, BlastResistance: 0.7},
+	127: {Hardness: 0.2, BlastResistance: 0.2, Tool: ToolAxe},
+	128: {Hardness: 0.8, BlastResistance: 0.8, Tool: ToolPickaxe, Tier: TierWood},
+	129: {Hardness: 3, BlastResistance: 3, Tool: ToolPickaxe, Tier: TierIron},
+	130: {Hardness: 22.5, BlastResistance: 600, Tool: ToolPickaxe, Tier: TierWood},
+	131: {Hardness: 0, BlastResistance: 0},
+	132: {Hardness: 0, BlastResistance: 0},
+	133: {Hardness: 5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierIron},
+	134: {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	135: {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	136: {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	137: {Hardness: -1, BlastResistance: 3600000},
+	138: {Hardness: 3, BlastResistance: 3},
+	139: {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	140: {Hardness: 0, BlastResistance: 0},
+	141: {Hardness: 0, BlastResistance: 0},
+	142: {Hardness: 0, BlastResistance: 0},
+	143: {Hardness: 0.5, BlastResistance: 0.5, Tool: ToolAxe},
+	144: {Hardness: 1, BlastResistance: 1},
+	145: {Hardness: 5, BlastResistance: 1200, Tool: ToolPickaxe, Tier: TierWood},
+	146: {Hardness: 2.5, BlastResistance: 2.5, Tool: ToolAxe},
+	147: {Hardness: 0.5, BlastResistance: 0.5},
+	148: {Hardness: 0.5, BlastResistance: 0.5},
+	149: {Hardness: 0, BlastResistance: 0},
+	150: {Hardness: 0, BlastResistance: 0},
+	151: {Hardness: 0.2, BlastResistance: 0.2, Tool: ToolAxe},
+	152: {Hardness: 5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	153: {Hardness: 3, BlastResistance: 3, Tool: ToolPickaxe, Tier: TierWood},
+	154: {Hardness: 3, BlastResistance: 4.8, Tool: ToolPickaxe, Tier: TierWood},
+	155: {Hardness: 0.8, BlastResistance: 0.8, Tool: ToolPickaxe, Tier: TierWood},
+	156: {Hardness: 0.8, BlastResistance: 0.8, Tool: ToolPickaxe, Tier: TierWood},
+	157: {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	158: {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	159: {Hardness: 1.25, BlastResistance: 4.2, Tool: ToolPickaxe, Tier: TierWood},
+	160: {Hardness: 0.3, BlastResistance: 0.3},
+	161: {Hardness: 0.2, BlastResistance: 0.2, Tool: ToolShears, FlameEncouragement: 30, Flammability: 60},
+	162: {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 5},
+	163: {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	164: {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	165: {Hardness: 0, BlastResistance: 0},
+	167: {Hardness: 5, BlastResistance: 5, Tool: ToolPickaxe, Tier: TierStone},
+	168: {Hardness: 1.5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	169: {Hardness: 0.3, BlastResistance: 0.3},
+	170: {Hardness: 0.5, BlastResistance: 0.5, Tool: ToolHoe, FlameEncouragement: 60, Flammability: 20},
+	171: {Hardness: 0.1, BlastResistance: 0.1, Tool: ToolShears, FlameEncouragement: 60, Flammability: 20},
+	172: {Hardness: 1.25, BlastResistance: 4.2, Tool: ToolPickaxe, Tier: TierWood},
+	173: {Hardness: 5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood, FlameEncouragement: 5, Flammability: 5},
+	174: {Hardness: 0.5, BlastResistance: 0.5},
+	175: {Hardness: 0, BlastResistance: 0, FlameEncouragement: 60, Flammability: 100},
+	176: {Hardness: 1, BlastResistance: 1, Tool: ToolAxe},
+	177: {Hardness: 1, BlastResistance: 1, Tool: ToolAxe},
+	178: {Hardness: 0.2, BlastResistance: 0.2, Tool: ToolAxe},
+	179: {Hardness: 0.8, BlastResistance: 0.8, Tool: ToolPickaxe, Tier: TierWood},
+	180: {Hardness: 0.8, BlastResistance: 0.8, Tool: ToolPickaxe, Tier: TierWood},
+	181: {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	182: {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	183: {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	184: {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	185: {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	186: {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	187: {Hardness: 2, BlastResistance: 3, Tool: ToolAxe, FlameEncouragement: 5, Flammability: 20},
+	188: {Hardness: -1, BlastResistance: 3600000},
+	189: {Hardness: -1, BlastResistance: 3600000},
+	190: {Hardness: 10, BlastResistance: 10},
+	191: {Hardness: 10, BlastResistance: 10},
+	192: {Hardness: 2.5, BlastResistance: 2.5},
+	193: {Hardness: 3, BlastResistance: 3, Tool: ToolAxe},
+	194: {Hardness: 3, BlastResistance: 3, Tool: ToolAxe},
+	195: {Hardness: 3, BlastResistance: 3, Tool: ToolAxe},
+	196: {Hardness: 3, BlastResistance: 3, Tool: ToolAxe},
+	197: {Hardness: 3, BlastResistance: 3, Tool: ToolAxe},
+	198: {Hardness: 0.6, BlastResistance: 0.6, Tool: ToolShovel},
+	199: {Hardness: 0, BlastResistance: 0, Tool: ToolAxe},
+	200: {Hardness: 0.4, BlastResistance: 0.4},
+	201: {Hardness: 1.5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	202: {Hardness: 0, BlastResistance: 0},
+	203: {Hardness: 1.5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	204: {Hardness: 0, BlastResistance: 0},
+	205: {Hardness: 2.5, BlastResistance: 2.5},
+	206: {Hardness: 0.8, BlastResistance: 9, Tool: ToolPickaxe, Tier: TierWood},
+	207: {Hardness: 0.5, BlastResistance: 0.5},
+	208: {Hardness: 0, BlastResistance: 0},
+	209: {Hardness: -1, BlastResistance: 3600000},
+	213: {Hardness: 0.5, BlastResistance: 0.5, Tool: ToolPickaxe, Tier: TierWood},
+	214: {Hardness: 1, BlastResistance: 1, Tool: ToolPickaxe, Tier: TierWood},
+	215: {Hardness: 2, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	216: {Hardness: 2, BlastResistance: 2, Tool: ToolPickaxe, Tier: TierWood},
+	218: {Hardness: 2.5, BlastResistance: 2.5},
+	219: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	220: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	221: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	222: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	223: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	224: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	225: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	226: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	227: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	228: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	229: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	231: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	232: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	233: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	234: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	235: {Hardness: 1.4, BlastResistance: 1.4, Tool: ToolPickaxe, Tier: TierWood},
+	236: {Hardness: 1.8, BlastResistance: 1.8, Tool: ToolPickaxe, Tier: TierWood},
+	237: {Hardness: 0.5, BlastResistance: 0.5, Tool: ToolShovel},
+	238: {Hardness: 2.5, BlastResistance: 2.5},
+	239: {Hardness: 0, BlastResistance: 0},
+	240: {Hardness: 0.4, BlastResistance: 0.4},
+	241: {Hardness: 0.3, BlastResistance: 0.3},
+	243: {Hardness: 0.5, BlastResistance: 0.5, Tool: ToolShovel},
+	244: {Hardness: 0, BlastResistance: 0},
+	245: {Hardness: 3.5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	246: {Hardness: 10, BlastResistance: 1200, Tool: ToolPickaxe, Tier: TierDiamond},
+	247: {Hardness: 3, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	248: {Hardness: 0, BlastResistance: 0},
+	249: {Hardness: 0, BlastResistance: 0},
+	250: {Hardness: -1, BlastResistance: 3600000},
+	251: {Hardness: 3.5, BlastResistance: 6, Tool: ToolPickaxe, Tier: TierWood},
+	252: {Hardness: -1, BlastResistance: 3600000},
+	253: {Hardness: 10, BlastResistance: 10},
+	254: {Hardness: 10, BlastResistance: 10},
+	255: {Hardness: 0, BlastResistance: 0},
+}
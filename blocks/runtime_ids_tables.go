@@ -0,0 +1,136 @@
+package blocks
+
+import (
+	"errors"
+	"github.com/irmine/binutils"
+	"github.com/irmine/gonbt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// UnknownProtocol gets returned if a runtime IDs table gets selected for a protocol without a registered table.
+var UnknownProtocol = errors.New("no runtime IDs table is registered for the protocol")
+
+// parseJSONTable parses a JSON runtime IDs table: a list of objects holding the name, id and data of every block.
+func parseJSONTable(data []byte) ([]tableEntry, error) {
+	var table interface{}
+	if err := yaml.Unmarshal(data, &table); err != nil {
+		return nil, err
+	}
+	var values, ok = table.([]interface{})
+	if !ok {
+		return nil, InvalidRuntimeIdsTable
+	}
+	var entries = make([]tableEntry, 0, len(values))
+	for _, v := range values {
+		var entry, ok = v.(map[interface{}]interface{})
+		if !ok {
+			return nil, InvalidRuntimeIdEntry
+		}
+		blockId, ok := entry["id"].(int)
+		if !ok {
+			return nil, InvalidRuntimeIdEntry
+		}
+		blockData, ok := entry["data"].(int)
+		if !ok {
+			return nil, InvalidRuntimeIdEntry
+		}
+		name, ok := entry["name"].(string)
+		if !ok {
+			return nil, InvalidRuntimeIdEntry
+		}
+		entries = append(entries, tableEntry{name, blockId, blockData})
+	}
+	return entries, nil
+}
+
+// parseNBTTable parses an NBT runtime IDs table: an uncompressed little endian compound,
+// holding a "Palette" list of compounds with the name, id and data of every block.
+// InvalidRuntimeIdsTable is returned if the data could not be read as NBT.
+func parseNBTTable(data []byte) (entries []tableEntry, err error) {
+	defer func() {
+		if recover() != nil {
+			entries, err = nil, InvalidRuntimeIdsTable
+		}
+	}()
+	var compound = gonbt.NewReader(data, false, binutils.LittleEndian).ReadUncompressedIntoCompound()
+	if compound == nil {
+		return nil, InvalidRuntimeIdsTable
+	}
+	var palette = compound.GetList("Palette", gonbt.TAG_Compound)
+	if palette == nil {
+		return nil, InvalidRuntimeIdsTable
+	}
+	entries = make([]tableEntry, 0, len(palette.GetTags()))
+	for _, tag := range palette.GetTags() {
+		var entry, ok = tag.(*gonbt.Compound)
+		if !ok || !entry.HasTag("name") || !entry.HasTag("id") || !entry.HasTag("data") {
+			return nil, InvalidRuntimeIdEntry
+		}
+		entries = append(entries, tableEntry{entry.GetString("name", ""), int(entry.GetShort("id", 0)), int(entry.GetShort("data", 0))})
+	}
+	return entries, nil
+}
+
+// RegisterRuntimeIdsTable registers a JSON runtime IDs table for the given protocol version, in the same format as RuntimeIdsTable__.
+// The table is only used once selected using SelectRuntimeIdsTable.
+func RegisterRuntimeIdsTable(protocol int32, data []byte) error {
+	var entries, err = parseJSONTable(data)
+	if err != nil {
+		return err
+	}
+	runtimeIdsMutex.Lock()
+	runtimeIdsTables[protocol] = entries
+	runtimeIdsMutex.Unlock()
+	return nil
+}
+
+// LoadRuntimeIdsTableFile registers the runtime IDs table in the file at the given path for the given protocol version.
+// Files with the .nbt or .dat extension are read as NBT tables, all other files as JSON tables.
+// The table is only used once selected using SelectRuntimeIdsTable.
+func LoadRuntimeIdsTableFile(protocol int32, path string) error {
+	var data, err = ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".nbt", ".dat":
+		entries, err := parseNBTTable(data)
+		if err != nil {
+			return err
+		}
+		runtimeIdsMutex.Lock()
+		runtimeIdsTables[protocol] = entries
+		runtimeIdsMutex.Unlock()
+		return nil
+	}
+	return RegisterRuntimeIdsTable(protocol, data)
+}
+
+// SelectRuntimeIdsTable selects the runtime IDs table registered for the given protocol version, and rebuilds the runtime IDs table with it.
+// Returns UnknownProtocol if no table was registered for the protocol. The previous table stays in use if an error is returned.
+func SelectRuntimeIdsTable(protocol int32) error {
+	runtimeIdsMutex.Lock()
+	defer runtimeIdsMutex.Unlock()
+	var entries, ok = runtimeIdsTables[protocol]
+	if !ok {
+		return UnknownProtocol
+	}
+	var previous, previousProtocol = selectedTable, selectedProtocol
+	selectedTable, selectedProtocol = entries, protocol
+	if err := registerRuntimeIds(); err != nil {
+		selectedTable, selectedProtocol = previous, previousProtocol
+		return err
+	}
+	return nil
+}
+
+// GetSelectedProtocol returns the protocol version of the runtime IDs table selected,
+// and a bool indicating if one was selected. The built-in table is used if none was selected.
+func GetSelectedProtocol() (int32, bool) {
+	runtimeIdsMutex.RLock()
+	defer runtimeIdsMutex.RUnlock()
+	return selectedProtocol, selectedTable != nil
+}
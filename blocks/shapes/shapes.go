@@ -0,0 +1,132 @@
+package shapes
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/blocks"
+	"github.com/irmine/worlds/utils"
+	"sync"
+)
+
+// Shape is the collision shape of a block.
+type Shape byte
+
+const (
+	// FullCube is the shape of blocks filling the entire block space, such as stone.
+	FullCube Shape = iota
+	// None is the shape of blocks without collision, such as air, flowers and liquids.
+	None
+	// Slab is the shape of half blocks, in either the bottom or top half of the block space.
+	Slab
+	// Stairs is the shape of stairs, a slab with a quarter block on top in the direction the stairs ascend.
+	Stairs
+	// Fence is the shape of fences, walls and closed fence gates: a post one and a half blocks tall.
+	Fence
+	// Thin is the shape of blocks only covering the bottom of the block space, such as carpet.
+	Thin
+)
+
+var shapesMutex sync.RWMutex
+
+// shapes are the shapes of blocks, keyed by block ID.
+// Blocks not in the map are full cubes if solid, as registered in the blocks package, and have no collision otherwise.
+var shapes = map[byte]Shape{
+	44: Slab, 158: Slab, 182: Slab,
+
+	53: Stairs, 67: Stairs, 108: Stairs, 109: Stairs, 114: Stairs, 128: Stairs, 134: Stairs, 135: Stairs,
+	136: Stairs, 156: Stairs, 163: Stairs, 164: Stairs, 180: Stairs, 203: Stairs,
+
+	85: Fence, 107: Fence, 113: Fence, 139: Fence, 183: Fence, 184: Fence, 185: Fence, 186: Fence, 187: Fence,
+
+	78: Thin, 171: Thin,
+}
+
+// fenceGates are the block IDs of fence gates, which have no collision when open.
+var fenceGates = map[byte]bool{107: true, 183: true, 184: true, 185: true, 186: true, 187: true}
+
+// GetShape returns the shape of the block with the given ID.
+func GetShape(id byte) Shape {
+	shapesMutex.RLock()
+	defer shapesMutex.RUnlock()
+	if shape, ok := shapes[id]; ok {
+		return shape
+	}
+	if !blocks.IsSolid(id) {
+		return None
+	}
+	return FullCube
+}
+
+// SetShape sets the shape of the block with the given ID.
+// Custom blocks must be registered this way in order to have a shape other than a full cube.
+func SetShape(id byte, shape Shape) {
+	shapesMutex.Lock()
+	shapes[id] = shape
+	shapesMutex.Unlock()
+}
+
+// HasCollision checks if entities collide with the block with the given ID and data.
+func HasCollision(id, data byte) bool {
+	return len(GetBoxes(id, data)) != 0
+}
+
+// GetBoxes returns the collision boxes of the block with the given ID and data, relative to the minimum corner of the block.
+// Blocks without collision have no boxes.
+func GetBoxes(id, data byte) []utils.AABB {
+	switch GetShape(id) {
+	case None:
+		return nil
+	case Slab:
+		if data&0x08 != 0 {
+			return []utils.AABB{newBox(0, 0.5, 0, 1, 1, 1)}
+		}
+		return []utils.AABB{newBox(0, 0, 0, 1, 0.5, 1)}
+	case Stairs:
+		return getStairsBoxes(data)
+	case Fence:
+		if fenceGates[id] && data&0x04 != 0 {
+			return nil
+		}
+		return []utils.AABB{newBox(0.375, 0, 0.375, 0.625, 1.5, 0.625)}
+	case Thin:
+		return []utils.AABB{newBox(0, 0, 0, 1, 0.0625, 1)}
+	}
+	return []utils.AABB{newBox(0, 0, 0, 1, 1, 1)}
+}
+
+// getStairsBoxes returns the collision boxes of stairs with the given data.
+// The lowest two bits are the direction the stairs ascend in: east, west, south or north.
+// The third bit is set if the stairs are upside down.
+func getStairsBoxes(data byte) []utils.AABB {
+	var slabMinY, stepMinY = 0.0, 0.5
+	if data&0x04 != 0 {
+		slabMinY, stepMinY = 0.5, 0
+	}
+	var slab = newBox(0, slabMinY, 0, 1, slabMinY+0.5, 1)
+	var step utils.AABB
+	switch data & 0x03 {
+	case 0:
+		step = newBox(0.5, stepMinY, 0, 1, stepMinY+0.5, 1)
+	case 1:
+		step = newBox(0, stepMinY, 0, 0.5, stepMinY+0.5, 1)
+	case 2:
+		step = newBox(0, stepMinY, 0.5, 1, stepMinY+0.5, 1)
+	default:
+		step = newBox(0, stepMinY, 0, 1, stepMinY+0.5, 0.5)
+	}
+	return []utils.AABB{slab, step}
+}
+
+// GetWorldBoxes returns the collision boxes of the block with the given ID and data at the given world position.
+func GetWorldBoxes(id, data byte, x, y, z int) []utils.AABB {
+	var boxes = GetBoxes(id, data)
+	var offset = r3.Vector{X: float64(x), Y: float64(y), Z: float64(z)}
+	for i, box := range boxes {
+		boxes[i] = box.Offset(offset)
+	}
+	return boxes
+}
+
+// newBox returns a new bounding box between the given minimum and maximum corners.
+func newBox(minX, minY, minZ, maxX, maxY, maxZ float64) utils.AABB {
+	return utils.AABB{Min: r3.Vector{X: minX, Y: minY, Z: minZ}, Max: r3.Vector{X: maxX, Y: maxY, Z: maxZ}}
+}
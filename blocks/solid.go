@@ -0,0 +1,36 @@
+package blocks
+
+import "sync"
+
+var solidMutex sync.RWMutex
+
+// nonSolidBlocks are all block IDs that can not be stood on, and can be spawned inside of.
+// Blocks not in the map are solid.
+var nonSolidBlocks = map[byte]bool{
+	0: true, 6: true, 8: true, 9: true, 10: true, 11: true, 27: true, 28: true, 30: true, 31: true,
+	32: true, 37: true, 38: true, 39: true, 40: true, 50: true, 51: true, 55: true, 59: true, 63: true,
+	65: true, 66: true, 68: true, 69: true, 70: true, 72: true, 75: true, 76: true, 77: true, 78: true,
+	83: true, 90: true, 104: true, 105: true, 106: true, 115: true, 119: true, 126: true, 131: true,
+	132: true, 141: true, 142: true, 143: true, 147: true, 148: true, 171: true, 175: true, 176: true,
+	177: true, 202: true, 204: true, 209: true, 239: true, 244: true,
+}
+
+// IsSolid checks if the block with the given ID can be stood on.
+// Solidity decides the spawnability of blocks, the default collision shape of blocks and the default light filter of blocks.
+func IsSolid(blockId byte) bool {
+	solidMutex.RLock()
+	defer solidMutex.RUnlock()
+	return !nonSolidBlocks[blockId]
+}
+
+// SetSolid sets if the block with the given ID can be stood on.
+// Custom blocks must be registered this way in order to not be solid.
+func SetSolid(blockId byte, solid bool) {
+	solidMutex.Lock()
+	if solid {
+		delete(nonSolidBlocks, blockId)
+	} else {
+		nonSolidBlocks[blockId] = true
+	}
+	solidMutex.Unlock()
+}
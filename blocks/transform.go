@@ -0,0 +1,176 @@
+package blocks
+
+import "sync"
+
+// Direction is a horizontal direction a block can face.
+// Directions are ordered clockwise, so rotating a direction by 90 degrees adds one.
+type Direction byte
+
+const (
+	North Direction = iota
+	East
+	South
+	West
+)
+
+// Rotation is a clockwise rotation around the Y axis, in steps of 90 degrees.
+type Rotation byte
+
+const (
+	Rotate0 Rotation = iota
+	Rotate90
+	Rotate180
+	Rotate270
+)
+
+// Mirror is a mirror of blocks along a horizontal axis.
+type Mirror byte
+
+const (
+	MirrorNone Mirror = iota
+	// MirrorX mirrors blocks along the X axis, swapping east and west.
+	MirrorX
+	// MirrorZ mirrors blocks along the Z axis, swapping north and south.
+	MirrorZ
+)
+
+// Rotate returns the direction rotated clockwise by the rotation.
+func (direction Direction) Rotate(rotation Rotation) Direction {
+	return (direction + Direction(rotation)) % 4
+}
+
+// GetOffset returns the X and Z offset of the neighbouring block in the direction.
+func (direction Direction) GetOffset() (int, int) {
+	switch direction {
+	case North:
+		return 0, -1
+	case East:
+		return 1, 0
+	case South:
+		return 0, 1
+	}
+	return -1, 0
+}
+
+// Opposite returns the direction opposite to the direction.
+func (direction Direction) Opposite() Direction {
+	return direction ^ 2
+}
+
+// Mirror returns the direction mirrored by the mirror.
+func (direction Direction) Mirror(mirror Mirror) Direction {
+	switch {
+	case mirror == MirrorX && (direction == East || direction == West):
+		return direction ^ 2
+	case mirror == MirrorZ && (direction == North || direction == South):
+		return direction ^ 2
+	}
+	return direction
+}
+
+// Orientation describes how the orientation of a block is stored in its data.
+// The bits of the data in Mask hold the orientation, with Values holding the masked data of every direction, indexed by direction.
+// Blocks oriented along an axis rather than a direction, such as logs, have the same value for opposite directions.
+// Data not matching any of the values, such as standing torches, is never transformed.
+type Orientation struct {
+	Mask   byte
+	Values [4]byte
+}
+
+var (
+	// StairsOrientation is the orientation of stairs, by the direction they ascend to.
+	StairsOrientation = Orientation{0x03, [4]byte{3, 0, 2, 1}}
+	// AxisOrientation is the orientation of logs and other pillars, by the axis they lie along.
+	AxisOrientation = Orientation{0x0c, [4]byte{0x08, 0x04, 0x08, 0x04}}
+	// TorchOrientation is the orientation of torches, by the direction they face away from the wall they are attached to.
+	TorchOrientation = Orientation{0x07, [4]byte{4, 1, 3, 2}}
+	// FacingOrientation is the orientation of furnaces, chests, ladders and wall signs, by the direction they face.
+	FacingOrientation = Orientation{0x07, [4]byte{2, 5, 3, 4}}
+)
+
+var orientationsMutex sync.RWMutex
+
+// orientations are the orientations of blocks, keyed by block ID. Blocks not in the map are never transformed.
+var orientations = map[byte]Orientation{
+	17: AxisOrientation, 162: AxisOrientation, 170: AxisOrientation,
+	50: TorchOrientation, 75: TorchOrientation, 76: TorchOrientation,
+	53: StairsOrientation, 67: StairsOrientation, 108: StairsOrientation, 109: StairsOrientation, 114: StairsOrientation,
+	128: StairsOrientation, 134: StairsOrientation, 135: StairsOrientation, 136: StairsOrientation, 156: StairsOrientation,
+	163: StairsOrientation, 164: StairsOrientation, 180: StairsOrientation, 203: StairsOrientation,
+	54: FacingOrientation, 61: FacingOrientation, 62: FacingOrientation, 65: FacingOrientation, 68: FacingOrientation,
+	130: FacingOrientation, 146: FacingOrientation, 177: FacingOrientation,
+}
+
+// GetOrientation returns the orientation of the block with the given ID, and a bool indicating if the block has one.
+func GetOrientation(blockId byte) (Orientation, bool) {
+	orientationsMutex.RLock()
+	defer orientationsMutex.RUnlock()
+	var orientation, ok = orientations[blockId]
+	return orientation, ok
+}
+
+// SetOrientation sets the orientation of the block with the given ID.
+// Custom blocks must be registered this way in order to be rotated and mirrored.
+func SetOrientation(blockId byte, orientation Orientation) {
+	orientationsMutex.Lock()
+	orientations[blockId] = orientation
+	orientationsMutex.Unlock()
+}
+
+// GetDirection returns the direction stored in the data, and a bool indicating if the data holds a direction.
+func (orientation Orientation) GetDirection(data byte) (Direction, bool) {
+	for direction, value := range orientation.Values {
+		if data&orientation.Mask == value {
+			return Direction(direction), true
+		}
+	}
+	return 0, false
+}
+
+// SetDirection returns the data with the direction stored in it.
+func (orientation Orientation) SetDirection(data byte, direction Direction) byte {
+	return data&^orientation.Mask | orientation.Values[direction]
+}
+
+// transform returns the data with its direction transformed by the function.
+func (orientation Orientation) transform(data byte, function func(direction Direction) Direction) byte {
+	var direction, ok = orientation.GetDirection(data)
+	if !ok {
+		return data
+	}
+	return orientation.SetDirection(data, function(direction))
+}
+
+// Rotate returns the data of the block with the given ID and data rotated clockwise by the rotation.
+// The data of blocks without an orientation is returned unchanged.
+func Rotate(blockId, blockData byte, rotation Rotation) byte {
+	var orientation, ok = GetOrientation(blockId)
+	if !ok {
+		return blockData
+	}
+	return orientation.transform(blockData, func(direction Direction) Direction {
+		return direction.Rotate(rotation)
+	})
+}
+
+// MirrorData returns the data of the block with the given ID and data mirrored by the mirror.
+// The data of blocks without an orientation is returned unchanged.
+func MirrorData(blockId, blockData byte, mirror Mirror) byte {
+	var orientation, ok = GetOrientation(blockId)
+	if !ok {
+		return blockData
+	}
+	return orientation.transform(blockData, func(direction Direction) Direction {
+		return direction.Mirror(mirror)
+	})
+}
+
+// RotateBlock rotates the block clockwise by the rotation, by changing its data.
+func RotateBlock(block Block, rotation Rotation) {
+	block.SetData(Rotate(block.GetId(), block.GetData(), rotation))
+}
+
+// MirrorBlock mirrors the block by the mirror, by changing its data.
+func MirrorBlock(block Block, mirror Mirror) {
+	block.SetData(MirrorData(block.GetId(), block.GetData(), mirror))
+}
@@ -0,0 +1,119 @@
+package blocks
+
+import (
+	"crypto/sha256"
+	"sort"
+	"sync"
+)
+
+// Translator translates block IDs and data to runtime IDs for multiple protocol versions at once,
+// so that a server can send blocks to clients of different versions in the same world.
+// Every protocol added uses the runtime IDs table registered for it, followed by all custom blocks.
+type Translator struct {
+	mutex        sync.RWMutex
+	translations map[int32]*translation
+}
+
+// NewTranslator returns a new translator without any protocols added.
+func NewTranslator() *Translator {
+	return &Translator{sync.RWMutex{}, make(map[int32]*translation)}
+}
+
+// AddProtocol builds the runtime IDs table registered for the given protocol version, and adds it to the translator.
+// Protocols already added are rebuilt, which must be done after registering custom blocks.
+// Returns UnknownProtocol if no table was registered for the protocol.
+func (translator *Translator) AddProtocol(protocol int32) error {
+	runtimeIdsMutex.RLock()
+	var entries, ok = runtimeIdsTables[protocol]
+	var built *translation
+	if ok {
+		built = buildTranslation(entries)
+	}
+	runtimeIdsMutex.RUnlock()
+	if !ok {
+		return UnknownProtocol
+	}
+	translator.mutex.Lock()
+	translator.translations[protocol] = built
+	translator.mutex.Unlock()
+	return nil
+}
+
+// RemoveProtocol removes the given protocol version from the translator.
+func (translator *Translator) RemoveProtocol(protocol int32) {
+	translator.mutex.Lock()
+	delete(translator.translations, protocol)
+	translator.mutex.Unlock()
+}
+
+// HasProtocol checks if the given protocol version was added to the translator.
+func (translator *Translator) HasProtocol(protocol int32) bool {
+	translator.mutex.RLock()
+	defer translator.mutex.RUnlock()
+	var _, ok = translator.translations[protocol]
+	return ok
+}
+
+// GetProtocols returns all protocol versions added to the translator, sorted from low to high.
+func (translator *Translator) GetProtocols() []int32 {
+	translator.mutex.RLock()
+	var protocols = make([]int32, 0, len(translator.translations))
+	for protocol := range translator.translations {
+		protocols = append(protocols, protocol)
+	}
+	translator.mutex.RUnlock()
+	sort.Slice(protocols, func(i, j int) bool {
+		return protocols[i] < protocols[j]
+	})
+	return protocols
+}
+
+// getTranslation returns the translation of the given protocol version, and a bool indicating if it was added.
+func (translator *Translator) getTranslation(protocol int32) (*translation, bool) {
+	translator.mutex.RLock()
+	defer translator.mutex.RUnlock()
+	var built, ok = translator.translations[protocol]
+	return built, ok
+}
+
+// GetRuntimeId returns the runtime ID of the given block ID and block data for the protocol version,
+// and a bool indicating if it was found. It is never found for protocols not added to the translator.
+func (translator *Translator) GetRuntimeId(protocol int32, blockId, blockData int) (uint32, bool) {
+	var built, ok = translator.getTranslation(protocol)
+	if !ok {
+		return 0, false
+	}
+	runtimeId, ok := built.legacyToRuntime[(blockId<<4)|blockData]
+	return runtimeId, ok
+}
+
+// GetLegacyId returns the legacy ID of the given runtime ID for the protocol version, and a bool indicating if it was found.
+// The legacy ID is the block ID shifted left by 4, OR'd with the block data.
+func (translator *Translator) GetLegacyId(protocol int32, runtimeId uint32) (int, bool) {
+	var built, ok = translator.getTranslation(protocol)
+	if !ok {
+		return 0, false
+	}
+	legacyId, ok := built.runtimeToLegacy[runtimeId]
+	return legacyId, ok
+}
+
+// GetRuntimeIdsTable returns the runtime IDs table of the protocol version used for network sending,
+// and a bool indicating if the protocol was added.
+func (translator *Translator) GetRuntimeIdsTable(protocol int32) ([]byte, bool) {
+	var built, ok = translator.getTranslation(protocol)
+	if !ok {
+		return nil, false
+	}
+	return built.binary, true
+}
+
+// GetRuntimeIdsTableHash returns the SHA-256 hash of the runtime IDs table of the protocol version,
+// and a bool indicating if the protocol was added.
+func (translator *Translator) GetRuntimeIdsTableHash(protocol int32) ([sha256.Size]byte, bool) {
+	var built, ok = translator.getTranslation(protocol)
+	if !ok {
+		return [sha256.Size]byte{}, false
+	}
+	return built.hash, true
+}
@@ -0,0 +1,61 @@
+package vanilla
+
+import (
+	"github.com/irmine/worlds/blocks"
+	"github.com/irmine/worlds/light"
+)
+
+// Block is a vanilla block, carrying the name, properties and light properties of the block.
+type Block struct {
+	*blocks.BlockInstance
+}
+
+// NewBlock returns a new vanilla block with the given ID and data.
+// Returns false if the block ID and data is not a vanilla block.
+func NewBlock(id, data byte) (*Block, bool) {
+	if _, ok := blocks.GetProperties(id); !ok {
+		return nil, false
+	}
+	var name, ok = blocks.GetBlockName(int(id), int(data))
+	if !ok {
+		// Not every data value has an entry, in which case the block has the name of its default data.
+		if name, ok = blocks.GetBlockName(int(id), 0); !ok {
+			return nil, false
+		}
+	}
+	var runtimeId, _ = blocks.GetRuntimeId(int(id), int(data))
+	return &Block{blocks.New(blocks.NewBlockState(name, int32(runtimeId), id, data))}, true
+}
+
+// IsBreakable checks if the block can be broken.
+func (block *Block) IsBreakable() bool {
+	return block.GetProperties().IsBreakable()
+}
+
+// GetLightEmission returns the light level emitted by the block.
+func (block *Block) GetLightEmission() byte {
+	return light.GetEmission(block.GetId())
+}
+
+// GetLightFilter returns the light level filtered by the block when light passes through it.
+func (block *Block) GetLightFilter() byte {
+	return light.GetFilter(block.GetId())
+}
+
+// RegisterVanilla registers all vanilla blocks into the manager.
+// Blocks already registered on vanilla block IDs are overwritten.
+func RegisterVanilla(manager *blocks.Manager) {
+	for i := 0; i < 256; i++ {
+		var id = byte(i)
+		if _, ok := blocks.GetProperties(id); !ok {
+			continue
+		}
+		if _, ok := blocks.GetBlockName(i, 0); !ok {
+			continue
+		}
+		manager.Register(id, func(data byte) blocks.Block {
+			var block, _ = NewBlock(id, data)
+			return block
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package worlds
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/utils"
+	"math"
+)
+
+// SetBorderWalls enables or disables border walls in the dimension.
+// With border walls enabled, unloaded chunks get treated as solid by entity physics and liquid flow,
+// preventing entities and liquids from falling into unloaded chunks.
+func (dimension *Dimension) SetBorderWalls(enabled bool) {
+	dimension.mutex.Lock()
+	dimension.borderWalls = enabled
+	dimension.mutex.Unlock()
+}
+
+// HasBorderWalls checks if border walls are enabled in the dimension.
+func (dimension *Dimension) HasBorderWalls() bool {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.borderWalls
+}
+
+// IsBorderWallAt checks if the given vector lies in an unloaded chunk that should be treated as solid.
+// Always returns false if border walls are disabled.
+func (dimension *Dimension) IsBorderWallAt(vector r3.Vector) bool {
+	if !dimension.HasBorderWalls() {
+		return false
+	}
+	var x, z = int32(math.Floor(vector.X)) >> 4, int32(math.Floor(vector.Z)) >> 4
+	return !dimension.IsChunkLoaded(x, z)
+}
+
+// GetBorderWallBoxes returns the collision boxes of the border walls intersecting the given bounding box.
+// Every unloaded chunk in the bounding box is a wall spanning the height of the bounding box.
+// Always returns no boxes if border walls are disabled.
+func (dimension *Dimension) GetBorderWallBoxes(box utils.AABB) []utils.AABB {
+	if !dimension.HasBorderWalls() {
+		return nil
+	}
+	var minX, _, minZ, maxX, _, maxZ = box.GetBlockBounds()
+	var boxes []utils.AABB
+	for chunkX := minX >> 4; chunkX <= maxX>>4; chunkX++ {
+		for chunkZ := minZ >> 4; chunkZ <= maxZ>>4; chunkZ++ {
+			if dimension.IsChunkLoaded(int32(chunkX), int32(chunkZ)) {
+				continue
+			}
+			boxes = append(boxes, utils.AABB{
+				Min: r3.Vector{X: float64(chunkX << 4), Y: box.Min.Y, Z: float64(chunkZ << 4)},
+				Max: r3.Vector{X: float64(chunkX<<4 + 16), Y: box.Max.Y, Z: float64(chunkZ<<4 + 16)},
+			})
+		}
+	}
+	return boxes
+}
@@ -0,0 +1,51 @@
+package chunks
+
+import (
+	"github.com/irmine/gonbt"
+	"sync/atomic"
+)
+
+// BlockWrite is a block written to a chunk, at a position local to the chunk.
+// X and Z are local to the chunk, Y is the Y in the world.
+type BlockWrite struct {
+	X, Y, Z  int
+	Id, Data byte
+	NBT      *gonbt.Compound
+}
+
+// SetBlocks writes all blocks to the chunk, locking the chunk only once.
+// The block NBT at every position written is replaced by the NBT of the write, and removed if nil.
+// Writes outside of the sub chunk range of the chunk are ignored.
+func (chunk *Chunk) SetBlocks(writes []BlockWrite) {
+	chunk.Lock()
+	for _, write := range writes {
+		if !chunk.subChunkRange.ContainsY(write.Y) {
+			continue
+		}
+		var key = chunk.subChunkRange.GetKey(write.Y)
+		var subChunk, ok = chunk.subChunks[key]
+		if !ok {
+			subChunk = AcquireSubChunk()
+			chunk.subChunks[key] = subChunk
+		}
+		subChunk.SetBlockId(write.X, write.Y&15, write.Z, write.Id)
+		subChunk.SetBlockData(write.X, write.Y&15, write.Z, write.Data)
+
+		var nbtKey = GetBlockNBTKey(write.X, write.Y, write.Z)
+		var _, exists = chunk.blockNBT[nbtKey]
+		if write.NBT == nil {
+			if exists {
+				atomic.AddInt32(&chunk.blockEntityCount, -1)
+				delete(chunk.blockNBT, nbtKey)
+			}
+			continue
+		}
+		if !exists {
+			atomic.AddInt32(&chunk.blockEntityCount, 1)
+		}
+		chunk.blockNBT[nbtKey] = write.NBT
+	}
+	chunk.Unlock()
+	chunk.SetDirty(true)
+	chunk.InvalidateCache()
+}
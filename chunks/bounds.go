@@ -0,0 +1,127 @@
+package chunks
+
+import (
+	"errors"
+	"fmt"
+)
+
+// OutOfBoundsPosition gets returned by checked block accessors if a position is outside of the chunk or sub chunk.
+var OutOfBoundsPosition = errors.New("position is out of bounds")
+
+// positionError returns an error wrapping OutOfBoundsPosition, describing the position and where it was used.
+func positionError(place string, x, y, z int) error {
+	return fmt.Errorf("%w: %v, %v, %v in %v", OutOfBoundsPosition, x, y, z, place)
+}
+
+// ValidatePosition checks if the given position is within the sub chunk.
+// Returns an error wrapping OutOfBoundsPosition if it is not.
+func (subChunk *SubChunk) ValidatePosition(x, y, z int) error {
+	if x < 0 || x > 15 || y < 0 || y > 15 || z < 0 || z > 15 {
+		return positionError("sub chunk", x, y, z)
+	}
+	return nil
+}
+
+// GetBlockIdChecked returns the block ID at the given position, or an error if the position is outside of the sub chunk.
+func (subChunk *SubChunk) GetBlockIdChecked(x, y, z int) (byte, error) {
+	if err := subChunk.ValidatePosition(x, y, z); err != nil {
+		return 0, err
+	}
+	return subChunk.GetBlockId(x, y, z), nil
+}
+
+// SetBlockIdChecked sets the block ID at the given position, or returns an error if the position is outside of the sub chunk.
+func (subChunk *SubChunk) SetBlockIdChecked(x, y, z int, id byte) error {
+	if err := subChunk.ValidatePosition(x, y, z); err != nil {
+		return err
+	}
+	subChunk.SetBlockId(x, y, z, id)
+	return nil
+}
+
+// GetBlockDataChecked returns the block data at the given position, or an error if the position is outside of the sub chunk.
+func (subChunk *SubChunk) GetBlockDataChecked(x, y, z int) (byte, error) {
+	if err := subChunk.ValidatePosition(x, y, z); err != nil {
+		return 0, err
+	}
+	return subChunk.GetBlockData(x, y, z), nil
+}
+
+// SetBlockDataChecked sets the block data at the given position, or returns an error if the position is outside of the sub chunk.
+func (subChunk *SubChunk) SetBlockDataChecked(x, y, z int, data byte) error {
+	if err := subChunk.ValidatePosition(x, y, z); err != nil {
+		return err
+	}
+	subChunk.SetBlockData(x, y, z, data)
+	return nil
+}
+
+// ValidatePosition checks if the given position is within the chunk.
+// X and Z are local to the chunk, Y must be within the sub chunk range of the chunk.
+// Returns an error wrapping OutOfBoundsPosition if it is not.
+func (chunk *Chunk) ValidatePosition(x, y, z int) error {
+	if x < 0 || x > 15 || z < 0 || z > 15 || !chunk.subChunkRange.ContainsY(y) {
+		return positionError(fmt.Sprintf("chunk %v, %v", chunk.X, chunk.Z), x, y, z)
+	}
+	return nil
+}
+
+// GetBlockIdChecked returns the block ID at the given position, or an error if the position is outside of the chunk.
+func (chunk *Chunk) GetBlockIdChecked(x, y, z int) (byte, error) {
+	if err := chunk.ValidatePosition(x, y, z); err != nil {
+		return 0, err
+	}
+	return chunk.GetBlockId(x, y, z), nil
+}
+
+// SetBlockIdChecked sets the block ID at the given position, or returns an error if the position is outside of the chunk.
+func (chunk *Chunk) SetBlockIdChecked(x, y, z int, id byte) error {
+	if err := chunk.ValidatePosition(x, y, z); err != nil {
+		return err
+	}
+	chunk.SetBlockId(x, y, z, id)
+	return nil
+}
+
+// GetBlockDataChecked returns the block data at the given position, or an error if the position is outside of the chunk.
+func (chunk *Chunk) GetBlockDataChecked(x, y, z int) (byte, error) {
+	if err := chunk.ValidatePosition(x, y, z); err != nil {
+		return 0, err
+	}
+	return chunk.GetBlockData(x, y, z), nil
+}
+
+// SetBlockDataChecked sets the block data at the given position, or returns an error if the position is outside of the chunk.
+func (chunk *Chunk) SetBlockDataChecked(x, y, z int, data byte) error {
+	if err := chunk.ValidatePosition(x, y, z); err != nil {
+		return err
+	}
+	chunk.SetBlockData(x, y, z, data)
+	return nil
+}
+
+// GetBlockIdUnchecked returns the block ID at the given position without validating it.
+// Unlike GetBlockId, sub chunks that do not exist are not created but read as air.
+// The position must be within the chunk, or GetBlockIdUnchecked may panic.
+func (chunk *Chunk) GetBlockIdUnchecked(x, y, z int) byte {
+	chunk.RLock()
+	var subChunk, ok = chunk.subChunks[chunk.subChunkRange.GetKey(y)]
+	chunk.RUnlock()
+	if !ok {
+		return 0
+	}
+	return subChunk.BlockIds[(x<<8)|(z<<4)|(y&15)]
+}
+
+// GetBlockDataUnchecked returns the block data at the given position without validating it.
+// Unlike GetBlockData, sub chunks that do not exist are not created but read as air.
+// The position must be within the chunk, or GetBlockDataUnchecked may panic.
+func (chunk *Chunk) GetBlockDataUnchecked(x, y, z int) byte {
+	chunk.RLock()
+	var subChunk, ok = chunk.subChunks[chunk.subChunkRange.GetKey(y)]
+	chunk.RUnlock()
+	if !ok {
+		return 0
+	}
+	return subChunk.GetBlockData(x, y&15, z)
+}
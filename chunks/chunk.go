@@ -5,7 +5,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/irmine/binutils"
 	"github.com/irmine/gonbt"
+	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 // Chunk is a segment of the world, holding blocks, block data, biomes, etc.
@@ -22,14 +24,48 @@ type Chunk struct {
 	*sync.RWMutex
 	viewers   map[uuid.UUID]Viewer
 	entities  map[uint64]ChunkEntity
-	blockNBT  map[int]*gonbt.Compound
+	blockNBT  map[BlockNBTKey]*gonbt.Compound
 	subChunks map[byte]*SubChunk
+
+	subChunkRange  SubChunkRange
+	subChunkFormat SubChunkFormat
+
+	scheduledTicks []ScheduledTick
+	relativeTicks  bool
+	entityNBT      []*gonbt.Compound
+
+	entityCount         int32
+	blockEntityCount    int32
+	dirty               int32
+	blockEntitiesLoaded int32
+	lightPopulating     int32
+
+	binaryMutex   sync.Mutex
+	binary        []byte
+	binaryVersion uint64
+}
+
+// Summary is a lightweight summary of a chunk.
+// Summaries can be retrieved without locking the chunk, see GetSummary.
+type Summary struct {
+	X, Z             int32
+	EntityCount      int
+	BlockEntityCount int
+	// HighestY is the Y of the highest block in the chunk according to the height map,
+	// or one below the sub chunk range of the chunk if the chunk is empty. It is best-effort, see GetSummary.
+	HighestY int16
+	Dirty    bool
 }
 
-// New returns a new chunk with the given X and Z.
+// New returns a new chunk with the given X and Z, holding the default range of sub chunks.
 func New(x, z int32) *Chunk {
+	return NewWithRange(x, z, DefaultSubChunkRange)
+}
+
+// NewWithRange returns a new chunk with the given X and Z, holding the given range of sub chunks.
+func NewWithRange(x, z int32, subChunkRange SubChunkRange) *Chunk {
 	return &Chunk{x, z,
-		true,
+		false,
 		true,
 		make([]byte, 256),
 		make([]int16, 256),
@@ -38,39 +74,123 @@ func New(x, z int32) *Chunk {
 		&sync.RWMutex{},
 		make(map[uuid.UUID]Viewer),
 		make(map[uint64]ChunkEntity),
-		make(map[int]*gonbt.Compound),
+		make(map[BlockNBTKey]*gonbt.Compound),
 		make(map[byte]*SubChunk),
+		subChunkRange,
+		SubChunkFormatLegacy,
+		nil,
+		false,
+		nil,
+		0,
+		0,
+		1,
+		0,
+		0,
+		sync.Mutex{},
+		nil,
+		0,
+	}
+}
+
+// GetSummary returns a summary of the chunk, containing counts of entities and block entities.
+// GetSummary does not lock the chunk. The counts and dirty flag are read atomically, but the highest Y is read
+// from the height map without synchronization, so it is a best-effort value while the height map is being changed.
+func (chunk *Chunk) GetSummary() Summary {
+	var highest = int16(chunk.subChunkRange.GetMinY() - 1)
+	for _, height := range chunk.HeightMap {
+		if height-1 > highest {
+			highest = height - 1
+		}
+	}
+	return Summary{
+		chunk.X, chunk.Z,
+		int(atomic.LoadInt32(&chunk.entityCount)),
+		int(atomic.LoadInt32(&chunk.blockEntityCount)),
+		highest,
+		chunk.IsDirty(),
+	}
+}
+
+// IsDirty checks if the chunk was modified since it was last marked clean.
+// New chunks are always dirty, chunks read from a region are clean.
+func (chunk *Chunk) IsDirty() bool {
+	return atomic.LoadInt32(&chunk.dirty) == 1
+}
+
+// SetDirty marks the chunk as dirty or clean.
+func (chunk *Chunk) SetDirty(dirty bool) {
+	if dirty {
+		atomic.StoreInt32(&chunk.dirty, 1)
+	} else {
+		atomic.StoreInt32(&chunk.dirty, 0)
 	}
 }
 
+// SetBlockEntitiesLoaded marks the block entities of the chunk as created from its block NBT.
+// Returns false if they were already marked as loaded, in which case they should not be created again.
+func (chunk *Chunk) SetBlockEntitiesLoaded() bool {
+	return atomic.CompareAndSwapInt32(&chunk.blockEntitiesLoaded, 0, 1)
+}
+
+// SetLightPopulating marks the light of the chunk as being populated.
+// Returns false if the light of the chunk was already populated or is being populated, in which case it should not be populated again.
+func (chunk *Chunk) SetLightPopulating() bool {
+	return !chunk.LightPopulated && atomic.CompareAndSwapInt32(&chunk.lightPopulating, 0, 1)
+}
+
 // GetViewers returns all viewers of the chunk.
 // Viewers are all players that have the chunk within their view distance.
+// The viewers are copied on write, so the map returned can be iterated safely without holding the chunk lock,
+// even while viewers get added or removed. The map returned must not be modified.
 func (chunk *Chunk) GetViewers() map[uuid.UUID]Viewer {
+	chunk.RLock()
+	defer chunk.RUnlock()
 	return chunk.viewers
 }
 
 // AddViewer adds a viewer of the chunk.
 func (chunk *Chunk) AddViewer(player Viewer) {
 	chunk.Lock()
-	chunk.viewers[player.GetUUID()] = player
+	var viewers = copyViewers(chunk.viewers, 1)
+	viewers[player.GetUUID()] = player
+	chunk.viewers = viewers
 	chunk.Unlock()
 }
 
 // RemoveViewer removes a viewer from the chunk.
 func (chunk *Chunk) RemoveViewer(player Viewer) {
 	chunk.Lock()
-	delete(chunk.viewers, player.GetUUID())
+	if _, ok := chunk.viewers[player.GetUUID()]; ok {
+		var viewers = copyViewers(chunk.viewers, 0)
+		delete(viewers, player.GetUUID())
+		chunk.viewers = viewers
+	}
 	chunk.Unlock()
 }
 
+// copyViewers returns a copy of the viewers, with room for the given amount of extra viewers.
+func copyViewers(viewers map[uuid.UUID]Viewer, extra int) map[uuid.UUID]Viewer {
+	var c = make(map[uuid.UUID]Viewer, len(viewers)+extra)
+	for id, viewer := range viewers {
+		c[id] = viewer
+	}
+	return c
+}
+
 // GetBiome returns the biome at the given column.
 func (chunk *Chunk) GetBiome(x, z int) byte {
+	chunk.RLock()
+	defer chunk.RUnlock()
 	return chunk.Biomes[chunk.GetBiomeIndex(x, z)]
 }
 
 // SetBiome sets the biome at the given column.
 func (chunk *Chunk) SetBiome(x, z int, biome byte) {
+	chunk.Lock()
 	chunk.Biomes[chunk.GetBiomeIndex(x, z)] = biome
+	chunk.Unlock()
+	chunk.SetDirty(true)
+	chunk.InvalidateCache()
 }
 
 // AddEntity adds a new entity to the chunk.
@@ -79,14 +199,22 @@ func (chunk *Chunk) AddEntity(entity ChunkEntity) error {
 		return errors.New("cannot add closed entity to chunk")
 	}
 	chunk.Lock()
+	if _, ok := chunk.entities[entity.GetRuntimeId()]; !ok {
+		atomic.AddInt32(&chunk.entityCount, 1)
+	}
 	chunk.entities[entity.GetRuntimeId()] = entity
 	chunk.Unlock()
+	chunk.SetDirty(true)
 	return nil
 }
 
 // RemoveEntity removes an entity with the given runtimeId from the chunk.
 func (chunk *Chunk) RemoveEntity(runtimeId uint64) {
 	chunk.Lock()
+	if _, ok := chunk.entities[runtimeId]; ok {
+		atomic.AddInt32(&chunk.entityCount, -1)
+		chunk.SetDirty(true)
+	}
 	delete(chunk.entities, runtimeId)
 	chunk.Unlock()
 }
@@ -98,26 +226,38 @@ func (chunk *Chunk) GetEntities() map[uint64]ChunkEntity {
 
 // SetBlockNBTAt sets the given compound at the given position.
 func (chunk *Chunk) SetBlockNBTAt(x, y, z int, nbt *gonbt.Compound) {
-	chunk.Lock()
 	if nbt == nil {
-		delete(chunk.blockNBT, GetBlockNBTIndex(x, y, z))
-	} else {
-		chunk.blockNBT[GetBlockNBTIndex(x, y, z)] = nbt
+		chunk.RemoveBlockNBTAt(x, y, z)
+		return
+	}
+	var key = GetBlockNBTKey(x, y, z)
+	chunk.Lock()
+	if _, ok := chunk.blockNBT[key]; !ok {
+		atomic.AddInt32(&chunk.blockEntityCount, 1)
 	}
+	chunk.blockNBT[key] = nbt
 	chunk.Unlock()
+	chunk.SetDirty(true)
+	chunk.InvalidateCache()
 }
 
 // RemoveBlockNBTAt removes the block NBT at the given position.
 func (chunk *Chunk) RemoveBlockNBTAt(x, y, z int) {
+	var key = GetBlockNBTKey(x, y, z)
 	chunk.Lock()
-	delete(chunk.blockNBT, GetBlockNBTIndex(x, y, z))
+	if _, ok := chunk.blockNBT[key]; ok {
+		atomic.AddInt32(&chunk.blockEntityCount, -1)
+		chunk.SetDirty(true)
+	}
+	delete(chunk.blockNBT, key)
 	chunk.Unlock()
+	chunk.InvalidateCache()
 }
 
 // BlockNBTExistsAt checks if any block NBT exists at the given position.
 func (chunk *Chunk) BlockNBTExistsAt(x, y, z int) bool {
 	chunk.RLock()
-	var _, ok = chunk.blockNBT[GetBlockNBTIndex(x, y, z)]
+	var _, ok = chunk.blockNBT[GetBlockNBTKey(x, y, z)]
 	chunk.RUnlock()
 	return ok
 }
@@ -126,11 +266,22 @@ func (chunk *Chunk) BlockNBTExistsAt(x, y, z int) bool {
 // Returns a bool if any block NBT was found at that position
 func (chunk *Chunk) GetBlockNBTAt(x, y, z int) (*gonbt.Compound, bool) {
 	chunk.RLock()
-	var c, ok = chunk.blockNBT[GetBlockNBTIndex(x, y, z)]
+	var c, ok = chunk.blockNBT[GetBlockNBTKey(x, y, z)]
 	chunk.RUnlock()
 	return c, ok
 }
 
+// GetBlockNBT returns a copy of all block NBT in the chunk, keyed by their position.
+func (chunk *Chunk) GetBlockNBT() map[BlockNBTKey]*gonbt.Compound {
+	chunk.RLock()
+	var blockNBT = make(map[BlockNBTKey]*gonbt.Compound, len(chunk.blockNBT))
+	for key, compound := range chunk.blockNBT {
+		blockNBT[key] = compound
+	}
+	chunk.RUnlock()
+	return blockNBT
+}
+
 // GetBiomeIndex returns the biome index of a column in a chunk.
 func (chunk *Chunk) GetBiomeIndex(x, z int) int {
 	return (x << 4) | z
@@ -147,53 +298,105 @@ func (chunk *Chunk) GetHeightMapIndex(x, z int) int {
 }
 
 // SetBlockId sets the given block ID at the given position.
+// Positions outside of the sub chunk range of the chunk are ignored.
 func (chunk *Chunk) SetBlockId(x, y, z int, blockId byte) {
-	chunk.GetSubChunk(byte(y>>4)).SetBlockId(x, y&15, z, blockId)
+	if !chunk.subChunkRange.ContainsY(y) {
+		return
+	}
+	chunk.GetSubChunk(chunk.subChunkRange.GetKey(y)).SetBlockId(x, y&15, z, blockId)
+	chunk.SetDirty(true)
+	chunk.InvalidateCache()
 }
 
 // GetBlockId returns the block ID of a block at the given position.
+// Positions outside of the sub chunk range of the chunk are air.
 func (chunk *Chunk) GetBlockId(x, y, z int) byte {
-	return chunk.GetSubChunk(byte(y>>4)).GetBlockId(x, y&15, z)
+	if !chunk.subChunkRange.ContainsY(y) {
+		return 0
+	}
+	return chunk.GetSubChunk(chunk.subChunkRange.GetKey(y)).GetBlockId(x, y&15, z)
 }
 
 // SetBlockData sets the block data of a block at the given position.
+// Positions outside of the sub chunk range of the chunk are ignored.
 func (chunk *Chunk) SetBlockData(x, y, z int, data byte) {
-	chunk.GetSubChunk(byte(y>>4)).SetBlockData(x, y&15, z, data)
+	if !chunk.subChunkRange.ContainsY(y) {
+		return
+	}
+	chunk.GetSubChunk(chunk.subChunkRange.GetKey(y)).SetBlockData(x, y&15, z, data)
+	chunk.SetDirty(true)
+	chunk.InvalidateCache()
 }
 
 // GetBlockData returns the block data of a block at the given position.
 func (chunk *Chunk) GetBlockData(x, y, z int) byte {
-	return chunk.GetSubChunk(byte(y>>4)).GetBlockData(x, y&15, z)
+	if !chunk.subChunkRange.ContainsY(y) {
+		return 0
+	}
+	return chunk.GetSubChunk(chunk.subChunkRange.GetKey(y)).GetBlockData(x, y&15, z)
 }
 
 // SetBlockLight sets the block light on a position in this chunk.
+// The cached binary of the chunk is invalidated if the light level changed.
 func (chunk *Chunk) SetBlockLight(x, y, z int, level byte) {
-	chunk.GetSubChunk(byte(y>>4)).SetBlockLight(x, y&15, z, level)
+	if !chunk.subChunkRange.ContainsY(y) {
+		return
+	}
+	var subChunk = chunk.GetSubChunk(chunk.subChunkRange.GetKey(y))
+	if subChunk.GetBlockLight(x, y&15, z) == level&0x0f {
+		return
+	}
+	subChunk.SetBlockLight(x, y&15, z, level)
+	chunk.InvalidateCache()
 }
 
 // GetBlockLight returns the block light on a position in this chunk.
 func (chunk *Chunk) GetBlockLight(x, y, z int) byte {
-	return chunk.GetSubChunk(byte(y>>4)).GetBlockLight(x, y&15, z)
+	if !chunk.subChunkRange.ContainsY(y) {
+		return 0
+	}
+	return chunk.GetSubChunk(chunk.subChunkRange.GetKey(y)).GetBlockLight(x, y&15, z)
 }
 
 // SetSkyLight sets the sky light on a position in this chunk.
+// The cached binary of the chunk is invalidated if the light level changed.
 func (chunk *Chunk) SetSkyLight(x, y, z int, level byte) {
-	chunk.GetSubChunk(byte(y>>4)).SetSkyLight(x, y&15, z, level)
+	if !chunk.subChunkRange.ContainsY(y) {
+		return
+	}
+	var subChunk = chunk.GetSubChunk(chunk.subChunkRange.GetKey(y))
+	if subChunk.GetSkyLight(x, y&15, z) == level&0x0f {
+		return
+	}
+	subChunk.SetSkyLight(x, y&15, z, level)
+	chunk.InvalidateCache()
 }
 
 // GetSkyLight returns the sky light on a position in this chunk.
+// Positions above the sub chunk range of the chunk have full sky light.
 func (chunk *Chunk) GetSkyLight(x, y, z int) byte {
-	return chunk.GetSubChunk(byte(y>>4)).GetSkyLight(x, y&15, z)
+	if !chunk.subChunkRange.ContainsY(y) {
+		if y > chunk.subChunkRange.GetMaxY() {
+			return 15
+		}
+		return 0
+	}
+	return chunk.GetSubChunk(chunk.subChunkRange.GetKey(y)).GetSkyLight(x, y&15, z)
 }
 
 // SetSubChunk sets a SubChunk on a position in this chunk.
+// The key of the sub chunk is relative to the bottom of the sub chunk range of the chunk.
 func (chunk *Chunk) SetSubChunk(y byte, subChunk *SubChunk) {
 	chunk.Lock()
 	chunk.subChunks[y] = subChunk
 	chunk.Unlock()
+	chunk.SetDirty(true)
+	chunk.InvalidateCache()
 }
 
-// GetSubChunk returns a SubChunk on a given height index in this chunk.
+// GetSubChunk returns a SubChunk on a given key in this chunk, creating it if it did not yet exist.
+// The key of the sub chunk is relative to the bottom of the sub chunk range of the chunk.
+// The sub chunk remains owned by the chunk and must not be used after the chunk is released, see Release.
 func (chunk *Chunk) GetSubChunk(y byte) *SubChunk {
 	chunk.RLock()
 	if sub, ok := chunk.subChunks[y]; ok {
@@ -203,7 +406,10 @@ func (chunk *Chunk) GetSubChunk(y byte) *SubChunk {
 	chunk.RUnlock()
 	chunk.Lock()
 	defer chunk.Unlock()
-	chunk.subChunks[y] = NewSubChunk()
+	if sub, ok := chunk.subChunks[y]; ok {
+		return sub
+	}
+	chunk.subChunks[y] = AcquireSubChunk()
 	return chunk.subChunks[y]
 }
 
@@ -215,18 +421,48 @@ func (chunk *Chunk) SubChunkExists(y byte) bool {
 	return ok
 }
 
-// GetSubChunks returns all sub chunks in a Y => sub chunk map.
+// GetSubChunks returns a copy of all sub chunks in a key => sub chunk map.
+// Keys are relative to the bottom of the sub chunk range of the chunk.
+// The sub chunks remain owned by the chunk: they get released to the pool when the chunk is unloaded,
+// and must not be used after. Use RangeSubChunks to read sub chunks while the chunk may get unloaded.
 func (chunk *Chunk) GetSubChunks() map[byte]*SubChunk {
-	return chunk.subChunks
+	chunk.RLock()
+	defer chunk.RUnlock()
+	var subChunks = make(map[byte]*SubChunk, len(chunk.subChunks))
+	for key, subChunk := range chunk.subChunks {
+		subChunks[key] = subChunk
+	}
+	return subChunks
+}
+
+// RangeSubChunks calls the function for every sub chunk of the chunk, ordered by their key.
+// The chunk is read locked while ranging, so its sub chunks cannot be released in the meantime.
+// The function must not retain the sub chunks after returning, nor call methods of the chunk that lock it.
+func (chunk *Chunk) RangeSubChunks(function func(key byte, subChunk *SubChunk)) {
+	chunk.RLock()
+	defer chunk.RUnlock()
+	var keys = make([]int, 0, len(chunk.subChunks))
+	for key := range chunk.subChunks {
+		keys = append(keys, int(key))
+	}
+	sort.Ints(keys)
+	for _, key := range keys {
+		function(byte(key), chunk.subChunks[byte(key)])
+	}
 }
 
 // SetHeightMapAt sets the height map at the given column to the given value.
 func (chunk *Chunk) SetHeightMapAt(x, z int, value int16) {
+	chunk.Lock()
 	chunk.HeightMap[chunk.GetHeightMapIndex(x, z)] = value
+	chunk.Unlock()
+	chunk.InvalidateCache()
 }
 
 // GetHeightMapAt returns the height map value at the given column.
 func (chunk *Chunk) GetHeightMapAt(x, z int) int16 {
+	chunk.RLock()
+	defer chunk.RUnlock()
 	return chunk.HeightMap[chunk.GetHeightMapIndex(x, z)]
 }
 
@@ -239,11 +475,12 @@ func (chunk *Chunk) RecalculateHeightMap() {
 	}
 }
 
-// GetHighestSubChunk returns the highest non-empty sub chunk index
+// GetHighestSubChunk returns the highest non-empty sub chunk key, or -1 if all sub chunks are empty.
+// The key is relative to the bottom of the sub chunk range of the chunk.
 func (chunk *Chunk) GetHighestSubChunkIndex() int {
 	chunk.RLock()
 	defer chunk.RUnlock()
-	for y := 15; y >= 0; y-- {
+	for y := chunk.subChunkRange.GetCount() - 1; y >= 0; y-- {
 		if _, ok := chunk.subChunks[byte(y)]; !ok {
 			continue
 		}
@@ -270,17 +507,18 @@ func (chunk *Chunk) GetHighestBlockData(x, z int) byte {
 	return chunk.GetHighestSubChunk().GetHighestBlockData(x, z)
 }
 
-// GetFilledSubChunks returns the count of filled sub chunks in the chunk.
+// GetFilledSubChunks returns the count of sub chunks up to and including the highest non-empty sub chunk.
+// This is the count of sub chunks written in the binary representation of the chunk,
+// so empty sub chunks above the highest non-empty one are never sent.
 func (chunk *Chunk) GetFilledSubChunks() byte {
-	//chunk.PruneEmptySubChunks()
-	return byte(len(chunk.subChunks))
+	return byte(chunk.GetHighestSubChunkIndex() + 1)
 }
 
 // PruneEmptySubChunks prunes all empty sub chunks that are not covered by filled ones.
 // It works from up to down and returns immediately if a non-empty chunk was found.
 func (chunk *Chunk) PruneEmptySubChunks() {
 	chunk.Lock()
-	for y := 15; y >= 0; y-- {
+	for y := chunk.subChunkRange.GetCount() - 1; y >= 0; y-- {
 		if _, ok := chunk.subChunks[byte(y)]; !ok {
 			continue
 		}
@@ -292,46 +530,134 @@ func (chunk *Chunk) PruneEmptySubChunks() {
 	chunk.Unlock()
 }
 
+// GetHighestBlockY returns the Y of the highest block in the given column.
+// Returns one below the sub chunk range of the chunk if the column is empty.
 func (chunk *Chunk) GetHighestBlockY(x, z int) int16 {
+	var empty = int16(chunk.subChunkRange.GetMinY() - 1)
 	var index = chunk.GetHighestSubChunkIndex()
 	if index == -1 {
-		return -1
+		return empty
 	}
-	var y int16
-	for y = int16(index); y >= 0; y-- {
-		var height = chunk.GetSubChunk(byte(y)).GetHighestBlockY(x, z) | (y << 4)
-		if height != -1{
-			return height
+	for key := index; key >= 0; key-- {
+		var height = chunk.GetSubChunk(byte(key)).GetHighestBlockY(x, z)
+		if height != -1 {
+			return int16(chunk.subChunkRange.GetKeyY(byte(key))) + height
 		}
 	}
-	return -1
+	return empty
 }
 
 // ToBinary converts the chunk to its binary representation, used for network sending.
+// The binary representation is cached, see CachedBinary.
 func (chunk *Chunk) ToBinary() []byte {
+	return chunk.CachedBinary()
+}
+
+// CachedBinary returns the cached binary representation of the chunk, serializing the chunk if the cache was invalidated.
+// The cache is invalidated by all block, biome, height map and block NBT changes made through the chunk.
+// Changes made directly to sub chunks, the height map or biomes must be followed by InvalidateCache.
+// The returned slice is shared between callers and must not be modified.
+func (chunk *Chunk) CachedBinary() []byte {
+	chunk.binaryMutex.Lock()
+	if chunk.binary != nil {
+		defer chunk.binaryMutex.Unlock()
+		return chunk.binary
+	}
+	var version = chunk.binaryVersion
+	chunk.binaryMutex.Unlock()
+
+	var binary = chunk.toBinary()
+
+	chunk.binaryMutex.Lock()
+	// The chunk could have been modified while serializing, in which case the result is already outdated.
+	if chunk.binaryVersion == version {
+		chunk.binary = binary
+	}
+	chunk.binaryMutex.Unlock()
+	return binary
+}
+
+// InvalidateCache invalidates the cached binary representation of the chunk.
+func (chunk *Chunk) InvalidateCache() {
+	chunk.binaryMutex.Lock()
+	chunk.binary = nil
+	chunk.binaryVersion++
+	chunk.binaryMutex.Unlock()
+}
+
+// toBinary serializes the chunk to its binary representation, without using the cache.
+// Sub chunks are written from the bottom of the sub chunk range of the chunk, in the sub chunk format of the chunk.
+func (chunk *Chunk) toBinary() []byte {
 	var stream = binutils.NewStream()
 	var subChunkCount = chunk.GetFilledSubChunks()
+	var format = chunk.GetSubChunkFormat()
 	stream.PutByte(subChunkCount)
-	//chunk.RLock()
+	chunk.RLock()
 	for i := byte(0); i < subChunkCount; i++ {
-		if _, ok := chunk.subChunks[i]; !ok {
-			stream.PutBytes(make([]byte, 4096+2048+1))
+		// Sub chunks missing below the highest non-empty sub chunk are written as air.
+		if subChunk, ok := chunk.subChunks[i]; ok {
+			stream.PutBytes(getSubChunkBinary(subChunk, format))
 		} else {
-			stream.PutBytes(chunk.subChunks[i].ToBinary())
+			stream.PutBytes(getSubChunkBinary(emptySubChunk, format))
 		}
 	}
-	//chunk.RUnlock()
 	for i := 255; i >= 0; i-- {
 		stream.PutLittleShort(chunk.HeightMap[i])
 	}
 	for _, biome := range chunk.Biomes {
 		stream.PutByte(byte(biome))
 	}
+	chunk.RUnlock()
 	stream.PutByte(0)
+	stream.PutBytes(chunk.GetBlockNBTBinary())
 	return stream.GetBuffer()
 }
 
+// GetBlockNBTBinary returns the network little endian NBT of all block NBT in the chunk, ordered by Y, X and Z.
+// Block NBT of chunks sent this way gets rendered by clients, so signs and chests show their content.
+func (chunk *Chunk) GetBlockNBTBinary() []byte {
+	chunk.RLock()
+	var keys = make([]BlockNBTKey, 0, len(chunk.blockNBT))
+	for key := range chunk.blockNBT {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].less(keys[j])
+	})
+	var writer = gonbt.NewWriter(true, binutils.LittleEndian)
+	for _, key := range keys {
+		writer.WriteUncompressedCompound(chunk.blockNBT[key])
+	}
+	chunk.RUnlock()
+	return writer.GetData()
+}
+
 // GetBlockNBTIndex returns the block NBT index of the given X, Y and Z.
+// Indices are unique for every Y, including negative ones.
+//
+// Deprecated: Block NBT is keyed by BlockNBTKey instead of packed indices. Use GetBlockNBTKey instead.
 func GetBlockNBTIndex(x, y, z int) int {
-	return ((y & 256) << 8) | ((x & 15) << 4) | (z & 15)
+	return (y << 8) | ((x & 15) << 4) | (z & 15)
+}
+
+// BlockNBTKey is the key of block NBT in a chunk.
+// X and Z are local to the chunk, Y is the Y in the world and may be negative.
+type BlockNBTKey struct {
+	X, Y, Z int
+}
+
+// GetBlockNBTKey returns the block NBT key of the given X, Y and Z.
+func GetBlockNBTKey(x, y, z int) BlockNBTKey {
+	return BlockNBTKey{x & 15, y, z & 15}
+}
+
+// less checks if the key comes before the other key, ordering keys by Y, X and Z.
+func (key BlockNBTKey) less(other BlockNBTKey) bool {
+	if key.Y != other.Y {
+		return key.Y < other.Y
+	}
+	if key.X != other.X {
+		return key.X < other.X
+	}
+	return key.Z < other.Z
 }
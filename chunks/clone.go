@@ -0,0 +1,47 @@
+package chunks
+
+import (
+	"github.com/irmine/binutils"
+	"github.com/irmine/gonbt"
+	"sync/atomic"
+)
+
+// Clone returns a deep copy of the sub chunk.
+func (subChunk *SubChunk) Clone() *SubChunk {
+	var clone = AcquireSubChunk()
+	copy(clone.BlockIds, subChunk.BlockIds)
+	copy(clone.BlockData, subChunk.BlockData)
+	copy(clone.BlockLight, subChunk.BlockLight)
+	copy(clone.SkyLight, subChunk.SkyLight)
+	clone.blockCount = subChunk.blockCount
+	return clone
+}
+
+// Clone returns a deep copy of the chunk, copying its sub chunks, biomes, height map and block NBT.
+// Viewers and entities of the chunk are not copied, and the clone is always dirty.
+func (chunk *Chunk) Clone() *Chunk {
+	var clone = NewWithRange(chunk.X, chunk.Z, chunk.GetSubChunkRange())
+	clone.subChunkFormat = chunk.GetSubChunkFormat()
+	clone.LightPopulated = chunk.LightPopulated
+	clone.TerrainPopulated = chunk.TerrainPopulated
+	clone.InhabitedTime = chunk.InhabitedTime
+	clone.LastUpdate = chunk.LastUpdate
+
+	chunk.RLock()
+	clone.Biomes = append([]byte{}, chunk.Biomes...)
+	clone.HeightMap = append([]int16{}, chunk.HeightMap...)
+	for y, subChunk := range chunk.subChunks {
+		clone.subChunks[y] = subChunk.Clone()
+	}
+	for key, compound := range chunk.blockNBT {
+		clone.blockNBT[key] = CopyCompound(compound)
+	}
+	chunk.RUnlock()
+	atomic.StoreInt32(&clone.blockEntityCount, int32(len(clone.blockNBT)))
+	return clone
+}
+
+// CopyCompound returns a deep copy of the compound, by writing and reading it back.
+func CopyCompound(compound *gonbt.Compound) *gonbt.Compound {
+	return gonbt.NewReader(encodeCompound(compound), false, binutils.BigEndian).ReadUncompressedIntoCompound()
+}
@@ -0,0 +1,26 @@
+package chunks
+
+import (
+	"github.com/irmine/worlds/blocks"
+	"image/color"
+)
+
+// GetMapColors returns the map colors of all columns in the chunk, indexed by biome index.
+// The color of a column is the color of its highest block with a map color, tinted by the biome of the column.
+// Columns without any block with a map color are fully transparent.
+func (chunk *Chunk) GetMapColors() [256]color.RGBA {
+	var colors [256]color.RGBA
+	var minY = chunk.subChunkRange.GetMinY()
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := int(chunk.GetHighestBlockY(x, z)); y >= minY; y-- {
+				var id, data = chunk.GetBlockIdUnchecked(x, y, z), chunk.GetBlockDataUnchecked(x, y, z)
+				if _, ok := blocks.GetBlockColor(id, data); ok {
+					colors[chunk.GetBiomeIndex(x, z)] = blocks.GetMapColor(id, data, chunk.GetBiome(x, z))
+					break
+				}
+			}
+		}
+	}
+	return colors
+}
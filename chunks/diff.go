@@ -0,0 +1,144 @@
+package chunks
+
+import (
+	"bytes"
+	"github.com/irmine/binutils"
+	"github.com/irmine/gonbt"
+)
+
+// BlockChange is a block that differs between two chunks.
+// X and Z are local to the chunk, Y is the Y in the world.
+type BlockChange struct {
+	X, Y, Z        int
+	OldId, OldData byte
+	NewId, NewData byte
+}
+
+// BiomeChange is a biome of a column that differs between two chunks.
+type BiomeChange struct {
+	X, Z     int
+	Old, New byte
+}
+
+// BlockNBTChange is block NBT that differs between two chunks.
+// Old or New is nil if the chunk had no block NBT at the position.
+type BlockNBTChange struct {
+	X, Y, Z  int
+	Old, New *gonbt.Compound
+}
+
+// ChunkDiff holds all differences in blocks, biomes and block NBT between two chunks.
+type ChunkDiff struct {
+	Blocks   []BlockChange
+	Biomes   []BiomeChange
+	BlockNBT []BlockNBTChange
+}
+
+// IsEmpty checks if the diff has no changes.
+func (diff ChunkDiff) IsEmpty() bool {
+	return len(diff.Blocks) == 0 && len(diff.Biomes) == 0 && len(diff.BlockNBT) == 0
+}
+
+// Diff returns the differences from the chunk to the other chunk, with the chunk holding the old values.
+// Sub chunks that do not exist are treated as air, so chunks with different sub chunk ranges can be compared.
+func (chunk *Chunk) Diff(other *Chunk) ChunkDiff {
+	var diff = ChunkDiff{}
+	if chunk == other {
+		return diff
+	}
+	for _, index := range subChunkIndexUnion(chunk, other) {
+		var before, after = chunk.getSubChunkAt(index), other.getSubChunkAt(index)
+		if before == nil && after == nil {
+			continue
+		}
+		if before != nil && after != nil && bytes.Equal(before.BlockIds, after.BlockIds) && bytes.Equal(before.BlockData, after.BlockData) {
+			continue
+		}
+		if before == nil {
+			before = emptySubChunk
+		}
+		if after == nil {
+			after = emptySubChunk
+		}
+		for x := 0; x < 16; x++ {
+			for z := 0; z < 16; z++ {
+				for y := 0; y < 16; y++ {
+					var change = BlockChange{x, index<<4 | y, z, before.GetBlockId(x, y, z), before.GetBlockData(x, y, z), after.GetBlockId(x, y, z), after.GetBlockData(x, y, z)}
+					if change.OldId != change.NewId || change.OldData != change.NewData {
+						diff.Blocks = append(diff.Blocks, change)
+					}
+				}
+			}
+		}
+	}
+
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			if before, after := chunk.GetBiome(x, z), other.GetBiome(x, z); before != after {
+				diff.Biomes = append(diff.Biomes, BiomeChange{x, z, before, after})
+			}
+		}
+	}
+
+	chunk.RLock()
+	other.RLock()
+	for key, before := range chunk.blockNBT {
+		var after = other.blockNBT[key]
+		if after == nil || !bytes.Equal(encodeCompound(before), encodeCompound(after)) {
+			diff.BlockNBT = append(diff.BlockNBT, BlockNBTChange{key.X, key.Y, key.Z, before, after})
+		}
+	}
+	for key, after := range other.blockNBT {
+		if _, ok := chunk.blockNBT[key]; !ok {
+			diff.BlockNBT = append(diff.BlockNBT, BlockNBTChange{key.X, key.Y, key.Z, nil, after})
+		}
+	}
+	other.RUnlock()
+	chunk.RUnlock()
+	return diff
+}
+
+// emptySubChunk is a sub chunk of air with full sky light, used in place of sub chunks that do not exist.
+var emptySubChunk = newEmptySubChunk()
+
+// newEmptySubChunk returns a new sub chunk of air with full sky light.
+func newEmptySubChunk() *SubChunk {
+	var subChunk = NewSubChunk()
+	for i := range subChunk.SkyLight {
+		subChunk.SkyLight[i] = 0xff
+	}
+	return subChunk
+}
+
+// getSubChunkAt returns the sub chunk at the given sub chunk index, or nil if it does not exist.
+func (chunk *Chunk) getSubChunkAt(index int) *SubChunk {
+	if !chunk.subChunkRange.ContainsIndex(index) {
+		return nil
+	}
+	chunk.RLock()
+	defer chunk.RUnlock()
+	return chunk.subChunks[byte(index-int(chunk.subChunkRange.Min))]
+}
+
+// subChunkIndexUnion returns all sub chunk indices within the sub chunk range of either chunk, from low to high.
+func subChunkIndexUnion(a, b *Chunk) []int {
+	var min, max = int(a.subChunkRange.Min), int(a.subChunkRange.Max)
+	if int(b.subChunkRange.Min) < min {
+		min = int(b.subChunkRange.Min)
+	}
+	if int(b.subChunkRange.Max) > max {
+		max = int(b.subChunkRange.Max)
+	}
+	var indices = make([]int, 0, max-min+1)
+	for i := min; i <= max; i++ {
+		indices = append(indices, i)
+	}
+	return indices
+}
+
+// encodeCompound returns the binary representation of the compound, used to compare compounds.
+func encodeCompound(compound *gonbt.Compound) []byte {
+	var writer = gonbt.NewWriter(false, binutils.BigEndian)
+	writer.WriteUncompressedCompound(compound)
+	return writer.GetData()
+}
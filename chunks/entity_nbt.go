@@ -0,0 +1,115 @@
+package chunks
+
+import (
+	"errors"
+	"github.com/golang/geo/r3"
+	"github.com/irmine/gonbt"
+)
+
+// EntityFactory creates an entity of the given entity type from its NBT.
+// The entity type is the numeric id of the NBT, or 0 if the NBT holds a string identifier as id, which should be resolved by the factory.
+// The entity returned gets its position set from the NBT afterwards.
+type EntityFactory func(entityType uint32, nbt *gonbt.Compound) (ChunkEntity, error)
+
+// InvalidEntityNBT gets returned if entity NBT has no valid position.
+var InvalidEntityNBT = errors.New("entity NBT has no valid position")
+
+// EntitiesToNBT returns the NBT of all entities in the chunk, used to persist them.
+// Every compound holds the NBT of the entity, with its entity type as id and its position as Pos.
+// Entity NBT set using SetEntityNBT that was not yet loaded is included as is.
+// Viewers are left out, as they are not persisted in chunks.
+func (chunk *Chunk) EntitiesToNBT() *gonbt.List {
+	chunk.RLock()
+	var tags = make([]gonbt.INamedTag, 0, len(chunk.entities)+len(chunk.entityNBT))
+	for _, entity := range chunk.entities {
+		if _, ok := entity.(Viewer); ok || entity.IsClosed() {
+			continue
+		}
+		var compound = gonbt.NewCompound("", make(map[string]gonbt.INamedTag))
+		if nbt := entity.GetNBT(); nbt != nil {
+			compound = CopyCompound(nbt)
+		}
+		var position = entity.GetPosition()
+		compound.SetTag(gonbt.NewInt("id", int32(entity.GetEntityType())))
+		compound.SetTag(gonbt.NewList("Pos", gonbt.TAG_Double, []gonbt.INamedTag{
+			gonbt.NewDouble("", position.X), gonbt.NewDouble("", position.Y), gonbt.NewDouble("", position.Z),
+		}))
+		tags = append(tags, compound)
+	}
+	for _, compound := range chunk.entityNBT {
+		tags = append(tags, compound)
+	}
+	chunk.RUnlock()
+	return gonbt.NewList("Entities", gonbt.TAG_Compound, tags)
+}
+
+// SetEntityNBT sets the NBT of entities of the chunk that are not yet loaded, such as when reading the chunk from disk.
+// The entities get created once LoadEntitiesFromNBT is called.
+func (chunk *Chunk) SetEntityNBT(list *gonbt.List) {
+	var compounds []*gonbt.Compound
+	for _, tag := range list.GetTags() {
+		if compound, ok := tag.(*gonbt.Compound); ok {
+			compounds = append(compounds, compound)
+		}
+	}
+	chunk.Lock()
+	chunk.entityNBT = compounds
+	chunk.Unlock()
+}
+
+// HasEntityNBT checks if the chunk has NBT of entities that are not yet loaded.
+func (chunk *Chunk) HasEntityNBT() bool {
+	chunk.RLock()
+	defer chunk.RUnlock()
+	return len(chunk.entityNBT) != 0
+}
+
+// LoadEntitiesFromNBT creates all entities of the entity NBT of the chunk using the factory, and clears the entity NBT.
+// The entities are returned with their position set, and should be added to the dimension of the chunk.
+// Entities that could not be created are skipped, and the first error that occurred is returned.
+func (chunk *Chunk) LoadEntitiesFromNBT(factory EntityFactory) ([]ChunkEntity, error) {
+	chunk.Lock()
+	var compounds = chunk.entityNBT
+	chunk.entityNBT = nil
+	chunk.Unlock()
+
+	var entities = make([]ChunkEntity, 0, len(compounds))
+	var err error
+	for _, compound := range compounds {
+		var position, ok = readEntityPosition(compound)
+		if !ok {
+			if err == nil {
+				err = InvalidEntityNBT
+			}
+			continue
+		}
+		entity, factoryErr := factory(uint32(compound.GetInt("id", 0)), compound)
+		if factoryErr != nil {
+			if err == nil {
+				err = factoryErr
+			}
+			continue
+		}
+		entity.SetNBT(compound)
+		entity.SetPosition(position)
+		entities = append(entities, entity)
+	}
+	return entities, err
+}
+
+// readEntityPosition reads the position of entity NBT from its Pos list.
+func readEntityPosition(compound *gonbt.Compound) (r3.Vector, bool) {
+	var list = compound.GetList("Pos", gonbt.TAG_Double)
+	if list == nil || len(list.GetTags()) != 3 {
+		return r3.Vector{}, false
+	}
+	var values [3]float64
+	for i, tag := range list.GetTags() {
+		value, ok := tag.Interface().(float64)
+		if !ok {
+			return r3.Vector{}, false
+		}
+		values[i] = value
+	}
+	return r3.Vector{X: values[0], Y: values[1], Z: values[2]}, true
+}
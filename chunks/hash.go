@@ -0,0 +1,76 @@
+package chunks
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/irmine/gonbt"
+	"hash"
+	"hash/fnv"
+	"sort"
+)
+
+// Hash returns a hash of the blocks, biomes and block NBT of the chunk.
+// The hash is stable: chunks with the same content always have the same hash, regardless of
+// how the content was created, which sub chunks only containing air exist, or the order of NBT tags.
+// Light, the height map and entities are not part of the hash.
+func (chunk *Chunk) Hash() uint64 {
+	var h = fnv.New64a()
+	var buffer [8]byte
+
+	chunk.RLock()
+	for i := int(chunk.subChunkRange.Min); i <= int(chunk.subChunkRange.Max); i++ {
+		var subChunk, ok = chunk.subChunks[byte(i-int(chunk.subChunkRange.Min))]
+		if !ok || subChunk.IsAllAir() {
+			continue
+		}
+		h.Write([]byte{byte(int8(i))})
+		h.Write(subChunk.BlockIds)
+		h.Write(subChunk.BlockData)
+	}
+	h.Write(chunk.Biomes)
+
+	var keys = make([]BlockNBTKey, 0, len(chunk.blockNBT))
+	for key := range chunk.blockNBT {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].less(keys[j])
+	})
+	for _, key := range keys {
+		for _, value := range [3]int{key.X, key.Y, key.Z} {
+			binary.LittleEndian.PutUint64(buffer[:], uint64(value))
+			h.Write(buffer[:])
+		}
+		hashTag(h, chunk.blockNBT[key])
+	}
+	chunk.RUnlock()
+	return h.Sum64()
+}
+
+// hashTag writes the type, name and value of the tag to the hash.
+// Tags of compounds are written ordered by name, so the order tags were set in does not matter.
+func hashTag(h hash.Hash64, tag gonbt.INamedTag) {
+	fmt.Fprintf(h, "%d%q", tag.GetType(), tag.GetName())
+	switch tag := tag.(type) {
+	case *gonbt.Compound:
+		var tags = tag.GetTags()
+		var names = make([]string, 0, len(tags))
+		for name := range tags {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(h, "{%d", len(names))
+		for _, name := range names {
+			hashTag(h, tags[name])
+		}
+		h.Write([]byte{'}'})
+	case *gonbt.List:
+		fmt.Fprintf(h, "[%d", len(tag.GetTags()))
+		for _, child := range tag.GetTags() {
+			hashTag(h, child)
+		}
+		h.Write([]byte{']'})
+	default:
+		fmt.Fprintf(h, "%#v", tag.Interface())
+	}
+}
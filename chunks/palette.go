@@ -0,0 +1,274 @@
+package chunks
+
+import (
+	"errors"
+	"github.com/irmine/binutils"
+	"github.com/irmine/worlds/blocks"
+)
+
+// PalettedSubChunkVersion is the version of the network format of paletted sub chunks.
+const PalettedSubChunkVersion = 8
+
+// SubChunkFormat is the network format sub chunks of a chunk get serialized in.
+type SubChunkFormat byte
+
+const (
+	// SubChunkFormatLegacy serializes sub chunks as block IDs, block data and light, see SubChunk.ToBinary.
+	SubChunkFormatLegacy SubChunkFormat = iota
+	// SubChunkFormatPaletted serializes sub chunks as paletted sub chunks of runtime IDs, see PalettedSubChunk.ToBinary.
+	SubChunkFormatPaletted
+)
+
+// UnknownLegacyBlock gets returned if a legacy block ID and data has no runtime ID.
+var UnknownLegacyBlock = errors.New("legacy block has no runtime ID")
+
+// paletteBitSizes are all bits per block a block storage may use, from small to large.
+var paletteBitSizes = []byte{1, 2, 3, 4, 5, 6, 8, 16}
+
+// BlockStorage is a layer of 4096 blocks, stored as indices into a palette of block runtime IDs.
+// The indices are packed into 32 bit words, using the least bits per block able to index the palette.
+type BlockStorage struct {
+	bitsPerBlock byte
+	words        []uint32
+	palette      []uint32
+}
+
+// NewBlockStorage returns a new block storage filled with the given block runtime ID.
+func NewBlockStorage(runtimeId uint32) *BlockStorage {
+	return &BlockStorage{paletteBitSizes[0], make([]uint32, wordCount(paletteBitSizes[0])), []uint32{runtimeId}}
+}
+
+// wordCount returns the count of words needed to store 4096 blocks with the given bits per block.
+func wordCount(bitsPerBlock byte) int {
+	var blocksPerWord = 32 / int(bitsPerBlock)
+	return (4096 + blocksPerWord - 1) / blocksPerWord
+}
+
+// GetBitsPerBlock returns the bits every block uses in the storage.
+func (storage *BlockStorage) GetBitsPerBlock() byte {
+	return storage.bitsPerBlock
+}
+
+// GetPalette returns the palette of block runtime IDs of the storage.
+// The palette returned should not be modified.
+func (storage *BlockStorage) GetPalette() []uint32 {
+	return storage.palette
+}
+
+// getIndex returns the palette index at the given block index.
+func (storage *BlockStorage) getIndex(index int) uint32 {
+	var blocksPerWord = 32 / int(storage.bitsPerBlock)
+	var shift = uint(index%blocksPerWord) * uint(storage.bitsPerBlock)
+	return (storage.words[index/blocksPerWord] >> shift) & (1<<storage.bitsPerBlock - 1)
+}
+
+// setIndex sets the palette index at the given block index.
+func (storage *BlockStorage) setIndex(index int, paletteIndex uint32) {
+	var blocksPerWord = 32 / int(storage.bitsPerBlock)
+	var shift = uint(index%blocksPerWord) * uint(storage.bitsPerBlock)
+	var mask = uint32(1<<storage.bitsPerBlock-1) << shift
+	storage.words[index/blocksPerWord] = storage.words[index/blocksPerWord]&^mask | (paletteIndex<<shift)&mask
+}
+
+// GetRuntimeId returns the block runtime ID at the given position.
+func (storage *BlockStorage) GetRuntimeId(x, y, z int) uint32 {
+	return storage.palette[storage.getIndex((x<<8)|(z<<4)|y)]
+}
+
+// SetRuntimeId sets the block runtime ID at the given position.
+// The palette grows to fit the runtime ID if it was not yet in it.
+func (storage *BlockStorage) SetRuntimeId(x, y, z int, runtimeId uint32) {
+	var paletteIndex = -1
+	for i, id := range storage.palette {
+		if id == runtimeId {
+			paletteIndex = i
+			break
+		}
+	}
+	if paletteIndex == -1 {
+		if len(storage.palette) >= 4096 {
+			// A palette can never use more entries than there are blocks, so the rest must be unused.
+			storage.Compact()
+		}
+		paletteIndex = len(storage.palette)
+		storage.palette = append(storage.palette, runtimeId)
+		if len(storage.palette) > 1<<storage.bitsPerBlock {
+			storage.resize(len(storage.palette))
+		}
+	}
+	storage.setIndex((x<<8)|(z<<4)|y, uint32(paletteIndex))
+}
+
+// resize repacks the storage using the least bits per block able to index a palette of the given size.
+func (storage *BlockStorage) resize(paletteSize int) {
+	var bitsPerBlock = paletteBitSizes[len(paletteBitSizes)-1]
+	for _, size := range paletteBitSizes {
+		if paletteSize <= 1<<size {
+			bitsPerBlock = size
+			break
+		}
+	}
+	if bitsPerBlock == storage.bitsPerBlock {
+		return
+	}
+	var resized = &BlockStorage{bitsPerBlock, make([]uint32, wordCount(bitsPerBlock)), storage.palette}
+	for i := 0; i < 4096; i++ {
+		resized.setIndex(i, storage.getIndex(i))
+	}
+	storage.bitsPerBlock, storage.words = resized.bitsPerBlock, resized.words
+}
+
+// Compact removes all runtime IDs no longer used from the palette, and shrinks the storage if possible.
+func (storage *BlockStorage) Compact() {
+	var used = make([]bool, len(storage.palette))
+	for i := 0; i < 4096; i++ {
+		used[storage.getIndex(i)] = true
+	}
+	var remapped = make([]uint32, len(storage.palette))
+	var palette = make([]uint32, 0, len(storage.palette))
+	for i, id := range storage.palette {
+		if used[i] {
+			remapped[i] = uint32(len(palette))
+			palette = append(palette, id)
+		}
+	}
+	for i := 0; i < 4096; i++ {
+		storage.setIndex(i, remapped[storage.getIndex(i)])
+	}
+	storage.palette = palette
+	storage.resize(len(palette))
+}
+
+// IsUniform checks if all blocks in the storage have the given runtime ID.
+func (storage *BlockStorage) IsUniform(runtimeId uint32) bool {
+	for i := 0; i < 4096; i++ {
+		if storage.palette[storage.getIndex(i)] != runtimeId {
+			return false
+		}
+	}
+	return true
+}
+
+// writeTo writes the network representation of the storage to the stream.
+func (storage *BlockStorage) writeTo(stream *binutils.Stream) {
+	// The lowest bit indicates the palette holds runtime IDs, rather than block NBT.
+	stream.PutByte(storage.bitsPerBlock<<1 | 1)
+	for _, word := range storage.words {
+		stream.PutLittleInt(int32(word))
+	}
+	stream.PutVarInt(int32(len(storage.palette)))
+	for _, id := range storage.palette {
+		stream.PutVarInt(int32(id))
+	}
+}
+
+// PalettedSubChunk is a 16x16x16 segment in a chunk, storing blocks as runtime IDs in layers of block storages.
+// The first layer holds the blocks, the second layer usually holds liquids inside of blocks.
+type PalettedSubChunk struct {
+	air    uint32
+	layers []*BlockStorage
+}
+
+// NewPalettedSubChunk returns a new paletted sub chunk filled with air, using the given runtime ID for air.
+func NewPalettedSubChunk(airRuntimeId uint32) *PalettedSubChunk {
+	return &PalettedSubChunk{airRuntimeId, []*BlockStorage{NewBlockStorage(airRuntimeId)}}
+}
+
+// NewPalettedSubChunkFromLegacy converts the block IDs and data of a sub chunk to a paletted sub chunk.
+// Returns UnknownLegacyBlock if the sub chunk contains a block without runtime ID.
+func NewPalettedSubChunkFromLegacy(subChunk *SubChunk) (*PalettedSubChunk, error) {
+	var air, ok = blocks.GetRuntimeId(0, 0)
+	if !ok {
+		return nil, UnknownLegacyBlock
+	}
+	var paletted = NewPalettedSubChunk(air)
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := 0; y < 16; y++ {
+				var id, data = subChunk.GetBlockId(x, y, z), subChunk.GetBlockData(x, y, z)
+				if id == 0 {
+					continue
+				}
+				runtimeId, ok := blocks.GetRuntimeId(int(id), int(data))
+				if !ok {
+					return nil, UnknownLegacyBlock
+				}
+				paletted.SetRuntimeId(x, y, z, 0, runtimeId)
+			}
+		}
+	}
+	return paletted, nil
+}
+
+// GetLayerCount returns the count of layers in the sub chunk.
+func (subChunk *PalettedSubChunk) GetLayerCount() int {
+	return len(subChunk.layers)
+}
+
+// GetLayer returns the block storage of the given layer, creating it and any layers below it if needed.
+func (subChunk *PalettedSubChunk) GetLayer(layer int) *BlockStorage {
+	for len(subChunk.layers) <= layer {
+		subChunk.layers = append(subChunk.layers, NewBlockStorage(subChunk.air))
+	}
+	return subChunk.layers[layer]
+}
+
+// GetRuntimeId returns the block runtime ID at the given position in the given layer.
+// Returns the runtime ID of air if the layer does not exist.
+func (subChunk *PalettedSubChunk) GetRuntimeId(x, y, z, layer int) uint32 {
+	if layer >= len(subChunk.layers) {
+		return subChunk.air
+	}
+	return subChunk.layers[layer].GetRuntimeId(x, y, z)
+}
+
+// SetRuntimeId sets the block runtime ID at the given position in the given layer.
+func (subChunk *PalettedSubChunk) SetRuntimeId(x, y, z, layer int, runtimeId uint32) {
+	if layer >= len(subChunk.layers) && runtimeId == subChunk.air {
+		return
+	}
+	subChunk.GetLayer(layer).SetRuntimeId(x, y, z, runtimeId)
+}
+
+// IsAllAir checks if the sub chunk is completely made up out of air.
+func (subChunk *PalettedSubChunk) IsAllAir() bool {
+	for _, layer := range subChunk.layers {
+		if !layer.IsUniform(subChunk.air) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compact compacts all layers, and removes trailing layers only containing air.
+func (subChunk *PalettedSubChunk) Compact() {
+	for _, layer := range subChunk.layers {
+		layer.Compact()
+	}
+	for len(subChunk.layers) > 1 && subChunk.layers[len(subChunk.layers)-1].IsUniform(subChunk.air) {
+		subChunk.layers = subChunk.layers[:len(subChunk.layers)-1]
+	}
+}
+
+// ToBinary returns the binary representation of the sub chunk used for network sending,
+// in the sub chunk version 8 format.
+func (subChunk *PalettedSubChunk) ToBinary() []byte {
+	var stream = binutils.NewStream()
+	stream.PutByte(PalettedSubChunkVersion)
+	stream.PutByte(byte(len(subChunk.layers)))
+	for _, layer := range subChunk.layers {
+		layer.writeTo(stream)
+	}
+	return stream.GetBuffer()
+}
+
+// getSubChunkBinary returns the binary representation of the sub chunk in the given format.
+// Sub chunks holding blocks without runtime ID are written in the legacy format, which can be mixed with paletted sub chunks.
+func getSubChunkBinary(subChunk *SubChunk, format SubChunkFormat) []byte {
+	if format == SubChunkFormatPaletted {
+		if paletted, err := NewPalettedSubChunkFromLegacy(subChunk); err == nil {
+			return paletted.ToBinary()
+		}
+	}
+	return subChunk.ToBinary()
+}
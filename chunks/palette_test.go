@@ -0,0 +1,120 @@
+package chunks
+
+import (
+	"testing"
+)
+
+// fillStorage sets every block of the storage to one of the given amount of runtime IDs, starting at offset.
+func fillStorage(storage *BlockStorage, count int, offset uint32) {
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := 0; y < 16; y++ {
+				storage.SetRuntimeId(x, y, z, offset+uint32(((x<<8)|(z<<4)|y)%count))
+			}
+		}
+	}
+}
+
+// checkStorage checks that every block of the storage holds the runtime ID set by fillStorage.
+func checkStorage(t *testing.T, storage *BlockStorage, count int, offset uint32) {
+	t.Helper()
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := 0; y < 16; y++ {
+				var expected = offset + uint32(((x<<8)|(z<<4)|y)%count)
+				if id := storage.GetRuntimeId(x, y, z); id != expected {
+					t.Fatalf("runtime ID at %v %v %v: got %v, expected %v", x, y, z, id, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestBlockStoragePacking(t *testing.T) {
+	var tests = []struct {
+		bitsPerBlock byte
+		paletteSize  int
+		words        int
+	}{
+		{3, 5, 410},
+		{3, 8, 410},
+		{5, 17, 683},
+		{5, 32, 683},
+		{6, 33, 820},
+		{6, 64, 820},
+	}
+	for _, test := range tests {
+		var storage = NewBlockStorage(0)
+		// The air runtime ID is part of the palette, so it counts towards its size.
+		fillStorage(storage, test.paletteSize, 0)
+		if bits := storage.GetBitsPerBlock(); bits != test.bitsPerBlock {
+			t.Errorf("palette of %v: got %v bits per block, expected %v", test.paletteSize, bits, test.bitsPerBlock)
+		}
+		if len(storage.words) != test.words {
+			t.Errorf("palette of %v: got %v words, expected %v", test.paletteSize, len(storage.words), test.words)
+		}
+		checkStorage(t, storage, test.paletteSize, 0)
+	}
+}
+
+func TestBlockStorageResize(t *testing.T) {
+	var storage = NewBlockStorage(0)
+	fillStorage(storage, 6, 0)
+	if bits := storage.GetBitsPerBlock(); bits != 3 {
+		t.Fatalf("got %v bits per block, expected 3", bits)
+	}
+	// Adding a runtime ID to a full palette repacks all blocks set before.
+	storage.SetRuntimeId(0, 0, 0, 100)
+	storage.SetRuntimeId(0, 1, 0, 101)
+	storage.SetRuntimeId(0, 2, 0, 102)
+	if bits := storage.GetBitsPerBlock(); bits != 4 {
+		t.Fatalf("got %v bits per block, expected 4", bits)
+	}
+	for y, expected := range []uint32{100, 101, 102} {
+		if id := storage.GetRuntimeId(0, y, 0); id != expected {
+			t.Errorf("runtime ID at 0 %v 0: got %v, expected %v", y, id, expected)
+		}
+	}
+	for i := 3; i < 4096; i++ {
+		if id := storage.GetRuntimeId(i>>8, i&15, (i>>4)&15); id != uint32(i%6) {
+			t.Fatalf("runtime ID at index %v: got %v, expected %v", i, id, i%6)
+		}
+	}
+}
+
+func TestBlockStorageCompact(t *testing.T) {
+	var storage = NewBlockStorage(0)
+	fillStorage(storage, 40, 0)
+	if bits := storage.GetBitsPerBlock(); bits != 6 {
+		t.Fatalf("got %v bits per block, expected 6", bits)
+	}
+	// Overwriting all blocks leaves the old runtime IDs unused in the palette.
+	fillStorage(storage, 3, 1000)
+	if len(storage.GetPalette()) != 43 {
+		t.Fatalf("got palette of %v, expected 43", len(storage.GetPalette()))
+	}
+	storage.Compact()
+	if len(storage.GetPalette()) != 3 {
+		t.Errorf("got palette of %v after compacting, expected 3", len(storage.GetPalette()))
+	}
+	if bits := storage.GetBitsPerBlock(); bits != 2 {
+		t.Errorf("got %v bits per block after compacting, expected 2", bits)
+	}
+	checkStorage(t, storage, 3, 1000)
+}
+
+func TestPalettedSubChunkCompact(t *testing.T) {
+	var subChunk = NewPalettedSubChunk(0)
+	subChunk.SetRuntimeId(1, 2, 3, 1, 7)
+	if subChunk.GetLayerCount() != 2 {
+		t.Fatalf("got %v layers, expected 2", subChunk.GetLayerCount())
+	}
+	subChunk.SetRuntimeId(1, 2, 3, 1, 0)
+	subChunk.Compact()
+	if subChunk.GetLayerCount() != 1 {
+		t.Errorf("got %v layers after compacting, expected 1", subChunk.GetLayerCount())
+	}
+	if !subChunk.IsAllAir() {
+		t.Error("sub chunk is not all air after compacting")
+	}
+}
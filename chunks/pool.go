@@ -0,0 +1,58 @@
+package chunks
+
+import "sync"
+
+// subChunkPool pools sub chunks of unloaded chunks, so that their buffers can be reused by new sub chunks.
+var subChunkPool = sync.Pool{
+	New: func() interface{} {
+		return NewSubChunk()
+	},
+}
+
+// AcquireSubChunk returns an empty sub chunk, reusing the buffers of a released sub chunk if available.
+func AcquireSubChunk() *SubChunk {
+	return subChunkPool.Get().(*SubChunk)
+}
+
+// ReleaseSubChunk resets the sub chunk and returns it to the pool, so that its buffers can be reused.
+// The sub chunk must no longer be used after releasing it.
+func ReleaseSubChunk(subChunk *SubChunk) {
+	subChunk.Reset()
+	subChunkPool.Put(subChunk)
+}
+
+// Reset zeroes all blocks and light of the sub chunk.
+// Buffers that do not have the correct length are replaced with new ones.
+func (subChunk *SubChunk) Reset() {
+	subChunk.BlockIds = resetBuffer(subChunk.BlockIds, 4096)
+	subChunk.BlockData = resetBuffer(subChunk.BlockData, 2048)
+	subChunk.BlockLight = resetBuffer(subChunk.BlockLight, 2048)
+	subChunk.SkyLight = resetBuffer(subChunk.SkyLight, 2048)
+	subChunk.blockCount = 0
+}
+
+// resetBuffer zeroes the buffer, or returns a new buffer of the given length if it has a different length.
+func resetBuffer(buffer []byte, length int) []byte {
+	if len(buffer) != length {
+		return make([]byte, length)
+	}
+	for i := range buffer {
+		buffer[i] = 0
+	}
+	return buffer
+}
+
+// Release releases all sub chunks of the chunk to the pool, leaving the chunk empty.
+// Release is called when a chunk gets unloaded, and the chunk and its sub chunks should no longer be used after.
+// Sub chunks retrieved with GetSubChunk or GetSubChunks before releasing get reused by other chunks,
+// so code reading sub chunks of chunks that may get unloaded should use RangeSubChunks.
+func (chunk *Chunk) Release() {
+	chunk.Lock()
+	var subChunks = chunk.subChunks
+	chunk.subChunks = make(map[byte]*SubChunk)
+	chunk.Unlock()
+	chunk.InvalidateCache()
+	for _, subChunk := range subChunks {
+		ReleaseSubChunk(subChunk)
+	}
+}
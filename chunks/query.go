@@ -0,0 +1,41 @@
+package chunks
+
+import "github.com/irmine/worlds/utils"
+
+// BoxBlock is a block intersecting a bounding box, at a world X, Y and Z.
+type BoxBlock struct {
+	X, Y, Z  int
+	Id, Data byte
+}
+
+// GetBlocksInBox returns all blocks in the chunk intersecting the given bounding box in world coordinates, including air.
+// Only the part of the bounding box within the chunk and its sub chunk range is queried.
+func (chunk *Chunk) GetBlocksInBox(box utils.AABB) []BoxBlock {
+	var minX, minY, minZ, maxX, maxY, maxZ = box.GetBlockBounds()
+	var baseX, baseZ = int(chunk.X) << 4, int(chunk.Z) << 4
+	minX, maxX = clampInt(minX, baseX, baseX+15), clampInt(maxX, baseX-1, baseX+15)
+	minZ, maxZ = clampInt(minZ, baseZ, baseZ+15), clampInt(maxZ, baseZ-1, baseZ+15)
+	minY = clampInt(minY, chunk.subChunkRange.GetMinY(), chunk.subChunkRange.GetMaxY())
+	maxY = clampInt(maxY, chunk.subChunkRange.GetMinY()-1, chunk.subChunkRange.GetMaxY())
+
+	var result []BoxBlock
+	for x := minX; x <= maxX; x++ {
+		for z := minZ; z <= maxZ; z++ {
+			for y := minY; y <= maxY; y++ {
+				result = append(result, BoxBlock{x, y, z, chunk.GetBlockId(x&15, y, z&15), chunk.GetBlockData(x&15, y, z&15)})
+			}
+		}
+	}
+	return result
+}
+
+// clampInt clamps the value between the minimum and maximum.
+func clampInt(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,42 @@
+package chunks
+
+import "math/rand"
+
+// RandomTickBlock is a block picked to be random ticked, at a world X, Y and Z.
+type RandomTickBlock struct {
+	X, Y, Z  int
+	Id, Data byte
+}
+
+// RandomTickBlocks picks count random positions in every non-empty sub chunk of the chunk, like the random tick speed does,
+// and returns the blocks at those positions. Air picked is skipped, as air is never random ticked.
+// The same position may be picked more than once in a sub chunk.
+func (chunk *Chunk) RandomTickBlocks(random *rand.Rand, count int) []RandomTickBlock {
+	if count <= 0 {
+		return nil
+	}
+	var baseX, baseZ = int(chunk.X) << 4, int(chunk.Z) << 4
+	var result []RandomTickBlock
+
+	chunk.RLock()
+	// Sub chunks are visited from low to high, so that the same random source always picks the same blocks.
+	for key := 0; key < chunk.subChunkRange.GetCount(); key++ {
+		var subChunk, ok = chunk.subChunks[byte(key)]
+		if !ok || subChunk.IsAllAir() {
+			continue
+		}
+		var baseY = chunk.subChunkRange.GetKeyY(byte(key))
+		for i := 0; i < count; i++ {
+			// A single random number holds all three coordinates, like vanilla picks positions.
+			var position = random.Intn(4096)
+			var x, y, z = position >> 8, position & 0x0f, (position >> 4) & 0x0f
+			var id = subChunk.GetBlockId(x, y, z)
+			if id == 0 {
+				continue
+			}
+			result = append(result, RandomTickBlock{baseX | x, baseY + y, baseZ | z, id, subChunk.GetBlockData(x, y, z)})
+		}
+	}
+	chunk.RUnlock()
+	return result
+}
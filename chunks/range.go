@@ -0,0 +1,102 @@
+package chunks
+
+// SubChunkRange is the inclusive range of sub chunk indices a chunk can hold.
+// A sub chunk index is the Y of a sub chunk divided by 16, and may be negative.
+// Sub chunks are keyed relative to the bottom of the range, so the lowest sub chunk always has key 0.
+type SubChunkRange struct {
+	Min, Max int8
+}
+
+var (
+	// DefaultSubChunkRange is the range of sub chunks of pre 1.18 worlds, from Y 0 up to Y 255.
+	DefaultSubChunkRange = SubChunkRange{0, 15}
+	// ExtendedSubChunkRange is the range of sub chunks of 1.18 style worlds, from Y -64 up to Y 319.
+	ExtendedSubChunkRange = SubChunkRange{-4, 19}
+	// NetherSubChunkRange is the range of sub chunks of the nether, from Y 0 up to Y 127.
+	NetherSubChunkRange = SubChunkRange{0, 7}
+)
+
+// GetMinY returns the lowest Y in the range.
+func (r SubChunkRange) GetMinY() int {
+	return int(r.Min) << 4
+}
+
+// GetMaxY returns the highest Y in the range.
+func (r SubChunkRange) GetMaxY() int {
+	return int(r.Max)<<4 | 15
+}
+
+// GetCount returns the count of sub chunks in the range.
+func (r SubChunkRange) GetCount() int {
+	return int(r.Max) - int(r.Min) + 1
+}
+
+// ContainsY checks if the given Y lies within the range.
+func (r SubChunkRange) ContainsY(y int) bool {
+	return y >= r.GetMinY() && y <= r.GetMaxY()
+}
+
+// ContainsIndex checks if the sub chunk index lies within the range.
+func (r SubChunkRange) ContainsIndex(index int) bool {
+	return index >= int(r.Min) && index <= int(r.Max)
+}
+
+// GetKey returns the sub chunk key of the given Y, relative to the bottom of the range.
+func (r SubChunkRange) GetKey(y int) byte {
+	return byte((y >> 4) - int(r.Min))
+}
+
+// GetKeyY returns the lowest Y of the sub chunk with the given key.
+func (r SubChunkRange) GetKeyY(key byte) int {
+	return (int(key) + int(r.Min)) << 4
+}
+
+// GetSubChunkRange returns the range of sub chunks the chunk can hold.
+func (chunk *Chunk) GetSubChunkRange() SubChunkRange {
+	return chunk.subChunkRange
+}
+
+// GetSubChunkFormat returns the format sub chunks of the chunk get serialized in for network sending.
+func (chunk *Chunk) GetSubChunkFormat() SubChunkFormat {
+	chunk.binaryMutex.Lock()
+	defer chunk.binaryMutex.Unlock()
+	return chunk.subChunkFormat
+}
+
+// SetSubChunkFormat sets the format sub chunks of the chunk get serialized in for network sending.
+// The cached binary representation of the chunk is invalidated if the format changed.
+func (chunk *Chunk) SetSubChunkFormat(format SubChunkFormat) {
+	chunk.binaryMutex.Lock()
+	if chunk.subChunkFormat == format {
+		chunk.binaryMutex.Unlock()
+		return
+	}
+	chunk.subChunkFormat = format
+	chunk.binaryMutex.Unlock()
+	chunk.InvalidateCache()
+}
+
+// SetSubChunkRange sets the range of sub chunks the chunk can hold.
+// Sub chunks keep their Y, and sub chunks no longer within the range are released.
+func (chunk *Chunk) SetSubChunkRange(subChunkRange SubChunkRange) {
+	chunk.Lock()
+	var previous = chunk.subChunkRange
+	var subChunks = make(map[byte]*SubChunk, len(chunk.subChunks))
+	var released []*SubChunk
+	for key, subChunk := range chunk.subChunks {
+		var index = int(key) + int(previous.Min)
+		if !subChunkRange.ContainsIndex(index) {
+			released = append(released, subChunk)
+			continue
+		}
+		subChunks[byte(index-int(subChunkRange.Min))] = subChunk
+	}
+	chunk.subChunks = subChunks
+	chunk.subChunkRange = subChunkRange
+	chunk.Unlock()
+	chunk.SetDirty(true)
+	chunk.InvalidateCache()
+	for _, subChunk := range released {
+		ReleaseSubChunk(subChunk)
+	}
+}
@@ -0,0 +1,101 @@
+package chunks
+
+import (
+	"github.com/irmine/gonbt"
+	"sync/atomic"
+)
+
+// Snapshot is an immutable copy of a chunk at the time it was taken.
+// Snapshots are safe for concurrent use without locking, so they can be handed to other goroutines,
+// such as for network serialization or map rendering, without blocking the chunk.
+type Snapshot struct {
+	chunk *Chunk
+}
+
+// Snapshot returns an immutable snapshot of the chunk.
+func (chunk *Chunk) Snapshot() *Snapshot {
+	var clone = chunk.Clone()
+	clone.entityCount = atomic.LoadInt32(&chunk.entityCount)
+	clone.dirty = atomic.LoadInt32(&chunk.dirty)
+	clone.binary = clone.toBinary()
+	return &Snapshot{clone}
+}
+
+// GetX returns the chunk X of the snapshot.
+func (snapshot *Snapshot) GetX() int32 {
+	return snapshot.chunk.X
+}
+
+// GetZ returns the chunk Z of the snapshot.
+func (snapshot *Snapshot) GetZ() int32 {
+	return snapshot.chunk.Z
+}
+
+// GetSubChunkRange returns the range of sub chunks of the snapshot.
+func (snapshot *Snapshot) GetSubChunkRange() SubChunkRange {
+	return snapshot.chunk.subChunkRange
+}
+
+// getSubChunk returns the sub chunk the given Y is in, or an empty sub chunk if it does not exist.
+// Unlike the chunk, the snapshot never creates sub chunks on access.
+func (snapshot *Snapshot) getSubChunk(y int) *SubChunk {
+	if subChunk := snapshot.chunk.getSubChunkAt(y >> 4); subChunk != nil {
+		return subChunk
+	}
+	return emptySubChunk
+}
+
+// GetBlockId returns the block ID of a block at the given position.
+func (snapshot *Snapshot) GetBlockId(x, y, z int) byte {
+	return snapshot.getSubChunk(y).GetBlockId(x, y&15, z)
+}
+
+// GetBlockData returns the block data of a block at the given position.
+func (snapshot *Snapshot) GetBlockData(x, y, z int) byte {
+	return snapshot.getSubChunk(y).GetBlockData(x, y&15, z)
+}
+
+// GetBlockLight returns the block light at the given position.
+func (snapshot *Snapshot) GetBlockLight(x, y, z int) byte {
+	return snapshot.getSubChunk(y).GetBlockLight(x, y&15, z)
+}
+
+// GetSkyLight returns the sky light at the given position.
+// Positions above the sub chunk range or in sub chunks that do not exist have full sky light.
+func (snapshot *Snapshot) GetSkyLight(x, y, z int) byte {
+	if subChunk := snapshot.chunk.getSubChunkAt(y >> 4); subChunk != nil {
+		return subChunk.GetSkyLight(x, y&15, z)
+	}
+	if y < snapshot.chunk.subChunkRange.GetMinY() {
+		return 0
+	}
+	return 15
+}
+
+// GetBiome returns the biome at the given column.
+func (snapshot *Snapshot) GetBiome(x, z int) byte {
+	return snapshot.chunk.GetBiome(x, z)
+}
+
+// GetHeightMapAt returns the height map value at the given column.
+func (snapshot *Snapshot) GetHeightMapAt(x, z int) int16 {
+	return snapshot.chunk.GetHeightMapAt(x, z)
+}
+
+// GetBlockNBTAt returns the block NBT at the given position, and a bool indicating if any was found.
+// The compound returned belongs to the snapshot and should not be modified.
+func (snapshot *Snapshot) GetBlockNBTAt(x, y, z int) (*gonbt.Compound, bool) {
+	var compound, ok = snapshot.chunk.blockNBT[GetBlockNBTKey(x, y, z)]
+	return compound, ok
+}
+
+// GetSummary returns a summary of the chunk at the time the snapshot was taken.
+func (snapshot *Snapshot) GetSummary() Summary {
+	return snapshot.chunk.GetSummary()
+}
+
+// ToBinary returns the binary representation of the snapshot used for network sending.
+// It is serialized once when the snapshot is taken, and the returned slice must not be modified.
+func (snapshot *Snapshot) ToBinary() []byte {
+	return snapshot.chunk.binary
+}
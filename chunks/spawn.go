@@ -0,0 +1,142 @@
+package chunks
+
+import (
+	"github.com/irmine/worlds/blocks"
+	"math/rand"
+)
+
+// IsSolidBlock checks if the block with the given ID can be stood on, as registered in the blocks package.
+func IsSolidBlock(id byte) bool {
+	return blocks.IsSolid(id)
+}
+
+// IsLiquidBlock checks if the block with the given ID is water or lava.
+func IsLiquidBlock(id byte) bool {
+	return id >= 8 && id <= 11
+}
+
+// SpawnConstraints are the constraints a position in a chunk must meet to be spawnable.
+type SpawnConstraints struct {
+	// MinLight and MaxLight are the inclusive range of light the position must have.
+	// The light of a position is the highest of its block light and sky light.
+	MinLight, MaxLight byte
+	// Clearance is the amount of non-solid, non-liquid blocks needed above the floor.
+	Clearance int
+	// MinY and MaxY are the inclusive range of Y values the position may have.
+	MinY, MaxY int
+	// IsSolid checks if a block can be stood on. Defaults to IsSolidBlock if nil.
+	IsSolid func(id byte) bool
+	// Weight returns the weight of a spawnable position. Positions with a higher weight are picked more often.
+	// Positions with a weight of 0 or lower are never picked. Every position has a weight of 1 if nil.
+	Weight func(position blocks.Position) float64
+}
+
+// DefaultSpawnConstraints returns the spawn constraints of most mobs:
+// a solid floor with 2 blocks of clearance, at any light level.
+func DefaultSpawnConstraints() SpawnConstraints {
+	return SpawnConstraints{0, 15, 2, 1, 255, nil, nil}
+}
+
+// GetSpawnPositions returns all positions in the chunk that meet the given constraints.
+// The positions returned are world positions of the block a spawned entity stands in, right above the floor.
+func (chunk *Chunk) GetSpawnPositions(constraints SpawnConstraints) []blocks.Position {
+	var isSolid = constraints.IsSolid
+	if isSolid == nil {
+		isSolid = IsSolidBlock
+	}
+	var subChunkRange = chunk.GetSubChunkRange()
+	var minY = constraints.MinY
+	if minY < subChunkRange.GetMinY()+1 {
+		minY = subChunkRange.GetMinY() + 1
+	}
+
+	var positions []blocks.Position
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			var maxY = int(chunk.GetHighestBlockY(x, z)) + 1
+			if maxY > constraints.MaxY {
+				maxY = constraints.MaxY
+			}
+			for y := minY; y <= maxY && y+constraints.Clearance <= subChunkRange.GetMaxY()+1; y++ {
+				if !chunk.isSpawnable(x, y, z, constraints, isSolid) {
+					continue
+				}
+				positions = append(positions, blocks.NewPosition(chunk.X<<4|int32(x), uint32(y), chunk.Z<<4|int32(z)))
+			}
+		}
+	}
+	return positions
+}
+
+// IsSpawnable checks if the given position in the chunk meets the constraints.
+// Positions outside of the sub chunk range of the chunk, or without room for the clearance, are never spawnable.
+func (chunk *Chunk) IsSpawnable(x, y, z int, constraints SpawnConstraints) bool {
+	var subChunkRange = chunk.GetSubChunkRange()
+	if y < constraints.MinY || y > constraints.MaxY || y <= subChunkRange.GetMinY() || y+constraints.Clearance > subChunkRange.GetMaxY()+1 {
+		return false
+	}
+	var isSolid = constraints.IsSolid
+	if isSolid == nil {
+		isSolid = IsSolidBlock
+	}
+	return chunk.isSpawnable(x, y, z, constraints, isSolid)
+}
+
+// isSpawnable checks if the given position in the chunk meets the constraints.
+func (chunk *Chunk) isSpawnable(x, y, z int, constraints SpawnConstraints, isSolid func(id byte) bool) bool {
+	var floor = chunk.GetBlockId(x, y-1, z)
+	if !isSolid(floor) || IsLiquidBlock(floor) {
+		return false
+	}
+	for i := 0; i < constraints.Clearance; i++ {
+		var id = chunk.GetBlockId(x, y+i, z)
+		if isSolid(id) || IsLiquidBlock(id) {
+			return false
+		}
+	}
+	var light = chunk.GetBlockLight(x, y, z)
+	if skyLight := chunk.GetSkyLight(x, y, z); skyLight > light {
+		light = skyLight
+	}
+	return light >= constraints.MinLight && light <= constraints.MaxLight
+}
+
+// PickSpawnPosition picks a random spawnable position in the chunk, weighted by the weight function of the constraints.
+// Returns false if no position in the chunk meets the constraints.
+func (chunk *Chunk) PickSpawnPosition(constraints SpawnConstraints, random *rand.Rand) (blocks.Position, bool) {
+	var positions = chunk.GetSpawnPositions(constraints)
+	if constraints.Weight == nil {
+		if len(positions) == 0 {
+			return blocks.Position{}, false
+		}
+		return positions[random.Intn(len(positions))], true
+	}
+
+	var weights = make([]float64, len(positions))
+	var total float64
+	for i, position := range positions {
+		if weight := constraints.Weight(position); weight > 0 {
+			weights[i] = weight
+			total += weight
+		}
+	}
+	if total == 0 {
+		return blocks.Position{}, false
+	}
+	var target = random.Float64() * total
+	for i, weight := range weights {
+		if weight == 0 {
+			continue
+		}
+		target -= weight
+		if target < 0 {
+			return positions[i], true
+		}
+	}
+	for i := len(weights) - 1; i >= 0; i-- {
+		if weights[i] > 0 {
+			return positions[i], true
+		}
+	}
+	return blocks.Position{}, false
+}
@@ -7,16 +7,35 @@ type SubChunk struct {
 	BlockData  []byte
 	BlockLight []byte
 	SkyLight   []byte
+
+	// blockCount is the count of non-air blocks in the sub chunk.
+	blockCount int
 }
 
 // NewSubChunk returns a new sub chunk.
 func NewSubChunk() *SubChunk {
-	return &SubChunk{make([]byte, 4096), make([]byte, 2048), make([]byte, 2048), make([]byte, 2048)}
+	return &SubChunk{make([]byte, 4096), make([]byte, 2048), make([]byte, 2048), make([]byte, 2048), 0}
 }
 
 // IsAllAir checks if the sub chunk is completely made up out of air.
 func (subChunk *SubChunk) IsAllAir() bool {
-	return string(subChunk.BlockIds) == string(make([]byte, 4096))
+	return subChunk.blockCount == 0
+}
+
+// GetBlockCount returns the count of non-air blocks in the sub chunk.
+func (subChunk *SubChunk) GetBlockCount() int {
+	return subChunk.blockCount
+}
+
+// RecountBlocks recounts the non-air blocks in the sub chunk.
+// RecountBlocks must be called after modifying the block IDs of the sub chunk directly.
+func (subChunk *SubChunk) RecountBlocks() {
+	subChunk.blockCount = 0
+	for _, id := range subChunk.BlockIds {
+		if id != 0 {
+			subChunk.blockCount++
+		}
+	}
 }
 
 // GetIdIndex returns the block ID index of the given position.
@@ -36,7 +55,13 @@ func (subChunk *SubChunk) GetBlockId(x, y, z int) byte {
 
 // SetBlockId sets the block ID at the given position to the given ID.
 func (subChunk *SubChunk) SetBlockId(x, y, z int, id byte) {
-	subChunk.BlockIds[subChunk.GetIdIndex(x, y, z)] = id
+	var i = subChunk.GetIdIndex(x, y, z)
+	if previous := subChunk.BlockIds[i]; previous == 0 && id != 0 {
+		subChunk.blockCount++
+	} else if previous != 0 && id == 0 {
+		subChunk.blockCount--
+	}
+	subChunk.BlockIds[i] = id
 }
 
 // GetBlockLight returns the block light on the given position.
@@ -123,8 +148,13 @@ func (subChunk *SubChunk) GetHighestBlockY(x, z int) int16 {
 }
 
 // ToBinary returns the binary representation of the sub chunk used for network sending.
+// The sky light and block light of the sub chunk are written after the blocks.
 func (subChunk *SubChunk) ToBinary() []byte {
-	var bytes = append([]byte{0x00}, subChunk.BlockIds...)
+	var bytes = make([]byte, 0, 1+4096+2048*3)
+	bytes = append(bytes, 0x00)
+	bytes = append(bytes, subChunk.BlockIds...)
 	bytes = append(bytes, subChunk.BlockData...)
+	bytes = append(bytes, subChunk.SkyLight...)
+	bytes = append(bytes, subChunk.BlockLight...)
 	return bytes
 }
@@ -0,0 +1,118 @@
+package chunks
+
+import "sort"
+
+// ScheduledTick is a block update scheduled to happen at a tick, such as falling sand or a redstone repeater switching.
+// X and Z are local to the chunk, Y is the Y in the world.
+type ScheduledTick struct {
+	X, Y, Z int
+	// BlockId is the ID of the block the tick was scheduled for.
+	// Ticks for a block that was replaced in the meantime should usually be ignored.
+	BlockId byte
+	// Tick is the tick of the level the update should happen at.
+	Tick int64
+	// Priority orders updates happening at the same tick. Updates with a lower priority happen first.
+	Priority int32
+}
+
+// ScheduleTick schedules a block update in the chunk.
+func (chunk *Chunk) ScheduleTick(tick ScheduledTick) {
+	chunk.Lock()
+	chunk.scheduledTicks = append(chunk.scheduledTicks, tick)
+	chunk.Unlock()
+	chunk.SetDirty(true)
+}
+
+// GetScheduledTicks returns all block updates scheduled in the chunk.
+func (chunk *Chunk) GetScheduledTicks() []ScheduledTick {
+	chunk.RLock()
+	defer chunk.RUnlock()
+	return append([]ScheduledTick{}, chunk.scheduledTicks...)
+}
+
+// GetRelativeScheduledTicks returns all block updates scheduled in the chunk, with ticks relative to the given current tick.
+// Ticks set using SetRelativeScheduledTicks that were not yet resolved are returned as they are.
+// Ticks that are already due get a delay of 0. This is how scheduled ticks are stored on disk.
+func (chunk *Chunk) GetRelativeScheduledTicks(currentTick int64) []ScheduledTick {
+	chunk.RLock()
+	defer chunk.RUnlock()
+	var ticks = append([]ScheduledTick{}, chunk.scheduledTicks...)
+	if chunk.relativeTicks {
+		return ticks
+	}
+	for i := range ticks {
+		ticks[i].Tick -= currentTick
+		if ticks[i].Tick < 0 {
+			ticks[i].Tick = 0
+		}
+	}
+	return ticks
+}
+
+// HasScheduledTicks checks if the chunk has any block updates scheduled.
+func (chunk *Chunk) HasScheduledTicks() bool {
+	chunk.RLock()
+	defer chunk.RUnlock()
+	return len(chunk.scheduledTicks) != 0
+}
+
+// HasScheduledTick checks if a block update is scheduled at the given position in the chunk.
+func (chunk *Chunk) HasScheduledTick(x, y, z int) bool {
+	chunk.RLock()
+	defer chunk.RUnlock()
+	for _, tick := range chunk.scheduledTicks {
+		if tick.X == x && tick.Y == y && tick.Z == z {
+			return true
+		}
+	}
+	return false
+}
+
+// SetRelativeScheduledTicks sets the block updates scheduled in the chunk, with ticks relative to the moment the chunk gets loaded.
+// This is how scheduled ticks are stored on disk. ResolveScheduledTicks must be called once the chunk is loaded.
+func (chunk *Chunk) SetRelativeScheduledTicks(ticks []ScheduledTick) {
+	chunk.Lock()
+	chunk.scheduledTicks = ticks
+	chunk.relativeTicks = true
+	chunk.Unlock()
+}
+
+// ResolveScheduledTicks makes ticks set using SetRelativeScheduledTicks relative to the given current tick.
+// It does nothing if the ticks were already resolved.
+func (chunk *Chunk) ResolveScheduledTicks(currentTick int64) {
+	chunk.Lock()
+	if chunk.relativeTicks {
+		for i := range chunk.scheduledTicks {
+			chunk.scheduledTicks[i].Tick += currentTick
+		}
+		chunk.relativeTicks = false
+	}
+	chunk.Unlock()
+}
+
+// PopDueTicks removes and returns all block updates scheduled at or before the given tick,
+// ordered by their tick and priority.
+func (chunk *Chunk) PopDueTicks(currentTick int64) []ScheduledTick {
+	chunk.Lock()
+	var due, remaining []ScheduledTick
+	for _, tick := range chunk.scheduledTicks {
+		if tick.Tick <= currentTick {
+			due = append(due, tick)
+		} else {
+			remaining = append(remaining, tick)
+		}
+	}
+	chunk.scheduledTicks = remaining
+	chunk.Unlock()
+	if len(due) == 0 {
+		return nil
+	}
+	chunk.SetDirty(true)
+	sort.SliceStable(due, func(i, j int) bool {
+		if due[i].Tick == due[j].Tick {
+			return due[i].Priority < due[j].Priority
+		}
+		return due[i].Tick < due[j].Tick
+	})
+	return due
+}
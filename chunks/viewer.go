@@ -11,3 +11,17 @@ type Viewer interface {
 	GetXUID() string
 	SendUpdateBlock(position blocks.Position, blockRuntimeId, dataLayerId uint32)
 }
+
+// ChunkViewer is a viewer that can be sent entire chunks, used to refresh chunks after large changes.
+// Viewers not implementing this interface receive every changed block instead.
+type ChunkViewer interface {
+	Viewer
+	SendChunk(chunk *Chunk)
+}
+
+// ProtocolViewer is a viewer playing on a specific protocol version.
+// Blocks are sent to it with the runtime IDs of its protocol, if the dimension translates that protocol.
+type ProtocolViewer interface {
+	Viewer
+	GetProtocol() int32
+}
@@ -0,0 +1,56 @@
+package worlds
+
+import "math"
+
+// Difficulty is the difficulty of a level.
+type Difficulty byte
+
+const (
+	DifficultyPeaceful Difficulty = iota
+	DifficultyEasy
+	DifficultyNormal
+	DifficultyHard
+)
+
+// moonPhaseFactors are the factors of every moon phase used to calculate local difficulty.
+// The full moon has the highest factor, the new moon the lowest.
+var moonPhaseFactors = [8]float64{1, 0.75, 0.5, 0.25, 0, 0.25, 0.5, 0.75}
+
+// LocalDifficulty is the difficulty of a specific chunk.
+// It increases with the world time, the time players spent in the chunk and the moon phase.
+type LocalDifficulty struct {
+	// Difficulty is the difficulty of the level the local difficulty was calculated in.
+	Difficulty Difficulty
+	// Value is the local difficulty, ranging from 0 to 6.75.
+	Value float64
+}
+
+// NewLocalDifficulty calculates a new local difficulty with the given level difficulty, world time and inhabited time of a chunk.
+func NewLocalDifficulty(difficulty Difficulty, worldTime, inhabitedTime int64) LocalDifficulty {
+	if difficulty == DifficultyPeaceful {
+		return LocalDifficulty{difficulty, 0}
+	}
+	var timeFactor = clamp((float64(worldTime)-72000)/1440000, 0, 1) * 0.25
+	var chunkFactor = clamp(float64(inhabitedTime)/3600000, 0, 1)
+	if difficulty != DifficultyHard {
+		chunkFactor *= 0.75
+	}
+	// The moon phase is wrapped into the range of 0 to 7, as the world time may be negative.
+	var moonPhase = ((worldTime/DayLength)%8 + 8) % 8
+	chunkFactor += clamp(moonPhaseFactors[moonPhase]*0.25, 0, timeFactor)
+	if difficulty == DifficultyEasy {
+		chunkFactor *= 0.5
+	}
+	return LocalDifficulty{difficulty, float64(difficulty) * (0.75 + timeFactor + chunkFactor)}
+}
+
+// Clamped returns the local difficulty scaled to a range of 0 to 1.
+// Local difficulties below 2 return 0, and local difficulties above 4 return 1.
+func (difficulty LocalDifficulty) Clamped() float64 {
+	return clamp((difficulty.Value-2)/2, 0, 1)
+}
+
+// clamp clamps the value between the minimum and maximum given.
+func clamp(value, min, max float64) float64 {
+	return math.Max(min, math.Min(max, value))
+}
@@ -1,17 +1,23 @@
 package worlds
 
 import (
+	"context"
 	"errors"
 	"github.com/golang/geo/r3"
 	"github.com/google/uuid"
+	"github.com/irmine/worlds/blockentities"
 	"github.com/irmine/worlds/blocks"
+	"github.com/irmine/worlds/blocks/shapes"
 	"github.com/irmine/worlds/chunks"
 	"github.com/irmine/worlds/generation"
+	"github.com/irmine/worlds/light"
 	"github.com/irmine/worlds/providers"
 	"github.com/irmine/worlds/utils"
 	"math"
+	"math/rand"
 	"os"
 	"sync"
+	"time"
 )
 
 const (
@@ -30,13 +36,53 @@ type Dimension struct {
 	id    DimensionId
 
 	chunkProvider providers.Provider
-	blockManager  blocks.Manager
+	blockManager  *blocks.Manager
 
 	mutex    sync.RWMutex
 	entities map[uint64]chunks.ChunkEntity
 	viewers  map[uuid.UUID]chunks.Viewer
 
-	blockUpdates map[int64]r3.Vector
+	// reservedEntities is the amount of entities room was made for under the resource limits, that are not yet added.
+	reservedEntities int
+
+	blockUpdates map[[3]int]r3.Vector
+
+	borderWalls bool
+	scheduler   *scheduler
+
+	limits ResourceLimits
+
+	lightEngine    *light.Engine
+	subChunkRange  chunks.SubChunkRange
+	subChunkFormat chunks.SubChunkFormat
+
+	blockTickChunks   map[[2]int32]bool
+	blockTickFunction BlockTickFunction
+
+	behaviors BehaviorManager
+	random    *rand.Rand
+
+	blockEntityManager blockentities.Manager
+	blockEntities      map[[3]int]blockentities.BlockEntity
+
+	itemEntityFunction ItemEntityFunction
+
+	liquidFlow bool
+
+	translator *blocks.Translator
+
+	entityRegistry EntityRegistry
+	entityHooks    EntityHooks
+
+	mobSpawner         *MobSpawner
+	simulationDistance int32
+
+	experienceFunction ExperienceFunction
+
+	weather           weather
+	lightningFunction LightningFunction
+
+	worldBorder *WorldBorder
 }
 
 // EntityRuntimeId is an ever increasing unsigned int64.
@@ -55,7 +101,8 @@ func NewDimension(name string, level *Level, id DimensionId) *Dimension {
 	var path = level.serverPath + "worlds/" + level.GetName() + "/" + name + "/region/"
 	os.MkdirAll(path, 0700)
 
-	var dimension = &Dimension{name, level, id, nil, nil, sync.RWMutex{}, make(map[uint64]chunks.ChunkEntity), make(map[uuid.UUID]chunks.Viewer), make(map[int64]r3.Vector)}
+	var dimension = &Dimension{name, level, id, nil, newBlockManager(), sync.RWMutex{}, make(map[uint64]chunks.ChunkEntity), make(map[uuid.UUID]chunks.Viewer), 0, make(map[[3]int]r3.Vector), false, &scheduler{}, ResourceLimits{}, nil, chunks.DefaultSubChunkRange, chunks.SubChunkFormatLegacy, make(map[[2]int32]bool), nil, nil, rand.New(rand.NewSource(time.Now().UnixNano())), nil, make(map[[3]int]blockentities.BlockEntity), nil, false, nil, nil, EntityHooks{}, nil, 0, nil, weather{}, nil, nil}
+	dimension.lightEngine = light.NewEngine(dimension, id == OverworldId)
 
 	return dimension
 }
@@ -78,11 +125,23 @@ func (dimension *Dimension) GetLevel() *Level {
 // Close closes the dimension and saves it.
 // If async is true, closes the dimension asynchronously.
 func (dimension *Dimension) Close(async bool) {
+	dimension.saveAllBlockEntities()
+	dimension.saveEntities()
 	dimension.chunkProvider.Close(async)
 }
 
+// CloseContext closes the dimension and saves it, waiting for pending chunk requests until the context is done.
+func (dimension *Dimension) CloseContext(ctx context.Context) error {
+	dimension.saveAllBlockEntities()
+	dimension.saveEntities()
+	return dimension.chunkProvider.CloseContext(ctx)
+}
+
 // Save saves the dimension.
+// Block entities are written back to the block NBT of their chunks first, and chunks holding entities are saved with the current NBT of their entities.
 func (dimension *Dimension) Save() {
+	dimension.saveAllBlockEntities()
+	dimension.saveEntities()
 	dimension.chunkProvider.Save()
 }
 
@@ -97,13 +156,15 @@ func (dimension *Dimension) GetViewers() map[uuid.UUID]chunks.Viewer {
 }
 
 // AddViewer adds a viewer to the dimension.
+// The chunk of the viewer is loaded regardless of the resource limits of the dimension.
 func (dimension *Dimension) AddViewer(viewer chunks.Viewer, position r3.Vector) {
 	x, z := int32(math.Floor(position.X))>>4, int32(math.Floor(position.Z))>>4
-	dimension.LoadChunk(x, z, func(chunk *chunks.Chunk) {
+	dimension.loadChunk(x, z, func(chunk *chunks.Chunk) {
 		dimension.mutex.Lock()
 		dimension.viewers[viewer.GetUUID()] = viewer
 		dimension.mutex.Unlock()
 		chunk.AddViewer(viewer)
+		dimension.sendWeather(viewer)
 	})
 }
 
@@ -124,37 +185,99 @@ func (dimension *Dimension) GetViewer(uuid uuid.UUID) (chunks.Viewer, bool) {
 }
 
 // AddEntity adds a new entity at the given position in the dimension.
+// The entity is not added if the dimension is at its entity limit and no room could be made for it.
 func (dimension *Dimension) AddEntity(entity chunks.ChunkEntity, position r3.Vector) {
 	var x, z = int32(math.Floor(position.X)) >> 4, int32(math.Floor(position.Z)) >> 4
 	dimension.LoadChunk(x, z, func(chunk *chunks.Chunk) {
-		EntityRuntimeId++
-		entity.SetRuntimeId(EntityRuntimeId)
-		entity.SetDimension(dimension)
-		entity.SetPosition(position)
-		entity.SpawnToAll()
+		dimension.addEntity(chunk, entity, position)
+	})
+}
 
-		chunk.AddEntity(entity)
-		dimension.mutex.Lock()
-		dimension.entities[EntityRuntimeId] = entity
-		dimension.mutex.Unlock()
+// TransferEntity adds an entity that was detached from another dimension to the dimension at the given position.
+// The function gets called with false if the resource limits of the dimension did not allow adding the entity,
+// so that the entity can be returned to the dimension it came from.
+// Returns UnloadedChunk if the chunk at the position cannot be loaded, in which case the function does not get called.
+func (dimension *Dimension) TransferEntity(entity chunks.ChunkEntity, position r3.Vector, function func(added bool)) error {
+	var x, z = int32(math.Floor(position.X)) >> 4, int32(math.Floor(position.Z)) >> 4
+	if !dimension.IsChunkLoaded(x, z) && !dimension.canLoadChunk() {
+		return UnloadedChunk
+	}
+	dimension.LoadChunk(x, z, func(chunk *chunks.Chunk) {
+		function(dimension.addEntity(chunk, entity, position))
 	})
+	return nil
+}
+
+// PositionInitializer is an entity that can have its position set without moving it, when it gets added to a dimension.
+// The entities of the entities package implement it. Entities not implementing this interface get moved with SetPosition,
+// which may run move hooks and border checks that prevent the entity from being added.
+type PositionInitializer interface {
+	InitPosition(position r3.Vector)
+}
+
+// addEntity adds the entity to the dimension in the given loaded chunk at the given position.
+// Returns false if the resource limits of the dimension did not allow adding the entity,
+// or if the entity could not be moved to the position.
+func (dimension *Dimension) addEntity(chunk *chunks.Chunk, entity chunks.ChunkEntity, position r3.Vector) bool {
+	if !dimension.makeEntityRoom(entity) {
+		return false
+	}
+	EntityRuntimeId++
+	entity.SetRuntimeId(EntityRuntimeId)
+	entity.SetDimension(dimension)
+	if initializer, ok := entity.(PositionInitializer); ok {
+		initializer.InitPosition(position)
+	} else if err := entity.SetPosition(position); err != nil {
+		dimension.releaseEntityRoom()
+		return false
+	}
+	entity.SpawnToAll()
+
+	chunk.AddEntity(entity)
+	dimension.mutex.Lock()
+	dimension.entities[EntityRuntimeId] = entity
+	dimension.reservedEntities--
+	var hook = dimension.entityHooks.OnEntitySpawn
+	dimension.mutex.Unlock()
+	if hook != nil {
+		hook(entity)
+	}
+	return true
 }
 
 // RemoveEntity removes an entity in the dimension with the given runtime ID.
 // The removed entity also gets closed if not yet done.
 func (dimension *Dimension) RemoveEntity(runtimeId uint64) {
+	dimension.removeEntity(runtimeId, true)
+}
+
+// DetachEntity removes an entity in the dimension with the given runtime ID without closing it, so that it can be added to another dimension.
+// Returns false if the dimension had no entity with the runtime ID.
+func (dimension *Dimension) DetachEntity(runtimeId uint64) bool {
+	return dimension.removeEntity(runtimeId, false)
+}
+
+// removeEntity removes an entity in the dimension with the given runtime ID from the dimension and its chunk, closing it if close is true.
+// Returns false if the dimension had no entity with the runtime ID.
+func (dimension *Dimension) removeEntity(runtimeId uint64, close bool) bool {
 	dimension.mutex.Lock()
-	if entity, ok := dimension.entities[runtimeId]; ok {
-		if !entity.IsClosed() {
+	var entity, ok = dimension.entities[runtimeId]
+	if ok {
+		if close && !entity.IsClosed() {
 			entity.Close()
 		}
-		var x, z = int32(math.Floor(entity.GetPosition().X)), int32(math.Floor(entity.GetPosition().Z))
+		var x, z = int32(math.Floor(entity.GetPosition().X)) >> 4, int32(math.Floor(entity.GetPosition().Z)) >> 4
 		if chunk, ok := dimension.GetChunk(x, z); ok {
 			chunk.RemoveEntity(runtimeId)
 		}
 		delete(dimension.entities, runtimeId)
 	}
+	var hook = dimension.entityHooks.OnEntityDespawn
 	dimension.mutex.Unlock()
+	if ok && hook != nil {
+		hook(entity)
+	}
+	return ok
 }
 
 // GetEntity returns an entity in the dimension by its runtime ID.
@@ -182,18 +305,63 @@ func (dimension *Dimension) IsChunkLoaded(x, z int32) bool {
 }
 
 // UnloadChunk unloads a chunk at the given chunk X and Z.
+// The block entities and entities in the chunk are written back to the chunk and removed, other than viewers.
 func (dimension *Dimension) UnloadChunk(x, z int32) {
+	dimension.removeChunkContent(x, z)
 	dimension.chunkProvider.UnloadChunk(x, z)
 }
 
+// removeChunkContent writes the block entities and entities of the chunk at the given chunk X and Z back to the chunk,
+// and removes them from the dimension, so that they no longer refer to the chunk once it gets unloaded.
+func (dimension *Dimension) removeChunkContent(x, z int32) {
+	dimension.saveBlockEntities(x, z, true)
+	if chunk, ok := dimension.GetChunk(x, z); ok {
+		dimension.unloadEntities(chunk)
+	}
+}
+
+// DeleteChunk unloads the chunk at the given chunk X and Z and deletes it from storage.
+// The block entities and entities in the chunk are removed as when unloading it, other than viewers.
+// The chunk gets regenerated the next time it is loaded.
+func (dimension *Dimension) DeleteChunk(x, z int32) error {
+	dimension.removeChunkContent(x, z)
+	return dimension.chunkProvider.DeleteChunk(x, z)
+}
+
 // LoadChunk submits a request with the given chunk X and Z to get loaded.
 // The function given gets run as soon as the chunk gets loaded.
+// Requests for chunks not yet loaded are refused if the dimension is at its chunk or request limit.
 func (dimension *Dimension) LoadChunk(x, z int32, function func(chunk *chunks.Chunk)) {
-	dimension.chunkProvider.LoadChunk(x, z, function)
+	if !dimension.IsChunkLoaded(x, z) && !dimension.canLoadChunk() {
+		return
+	}
+	dimension.loadChunk(x, z, function)
+}
+
+// loadChunk loads a chunk at the given chunk X and Z like LoadChunk, without checking the resource limits of the dimension.
+func (dimension *Dimension) loadChunk(x, z int32, function func(chunk *chunks.Chunk)) {
+	dimension.chunkProvider.LoadChunk(x, z, func(chunk *chunks.Chunk) {
+		chunk.SetSubChunkFormat(dimension.GetSubChunkFormat())
+		if chunk.SetLightPopulating() {
+			dimension.lightEngine.Populate(chunk)
+		}
+		if chunk.SetBlockEntitiesLoaded() {
+			dimension.loadBlockEntities(chunk)
+		}
+		if chunk.HasEntityNBT() {
+			dimension.loadEntities(chunk)
+		}
+		chunk.ResolveScheduledTicks(dimension.level.GetCurrentTick())
+		if chunk.HasScheduledTicks() {
+			dimension.trackBlockTicks(chunk)
+		}
+		function(chunk)
+	})
 }
 
 // SetChunk sets a new chunk at the given chunk X and Z.
 func (dimension *Dimension) SetChunk(x, z int32, chunk *chunks.Chunk) {
+	chunk.SetSubChunkFormat(dimension.GetSubChunkFormat())
 	dimension.chunkProvider.SetChunk(x, z, chunk)
 }
 
@@ -217,13 +385,83 @@ func (dimension *Dimension) GetChunkProvider() providers.Provider {
 	return dimension.chunkProvider
 }
 
+// newBlockManager returns the block manager dimensions are created with, which falls back to info update blocks for unregistered blocks.
+func newBlockManager() *blocks.Manager {
+	var manager = blocks.NewManager()
+	manager.SetFallback(blocks.InfoUpdateFallback)
+	return manager
+}
+
+// SetBlockManager sets the block manager used to get blocks in the dimension.
+func (dimension *Dimension) SetBlockManager(manager *blocks.Manager) {
+	dimension.mutex.Lock()
+	dimension.blockManager = manager
+	dimension.mutex.Unlock()
+}
+
+// GetBlockManager returns the block manager used to get blocks in the dimension.
+// The block manager of a new dimension has blocks.InfoUpdateFallback as fallback.
+func (dimension *Dimension) GetBlockManager() *blocks.Manager {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.blockManager
+}
+
+// GetLightEngine returns the light engine computing the light of the dimension.
+func (dimension *Dimension) GetLightEngine() *light.Engine {
+	return dimension.lightEngine
+}
+
 // SetChunkProvider sets the chunk provider of the dimension.
+// The provider gets the sub chunk range of the dimension, and saves scheduled ticks relative to the current tick of the level.
 func (dimension *Dimension) SetChunkProvider(provider providers.Provider) {
+	provider.SetSubChunkRange(dimension.GetSubChunkRange())
+	provider.SetTickFunction(dimension.level.GetCurrentTick)
 	dimension.chunkProvider = provider
 }
 
+// GetSubChunkRange returns the range of sub chunks of chunks in the dimension.
+func (dimension *Dimension) GetSubChunkRange() chunks.SubChunkRange {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.subChunkRange
+}
+
+// SetSubChunkRange sets the range of sub chunks of chunks in the dimension, such as ExtendedSubChunkRange for 1.18 style worlds.
+// The range should be set before any chunks are loaded, as chunks already loaded keep their range.
+func (dimension *Dimension) SetSubChunkRange(subChunkRange chunks.SubChunkRange) {
+	dimension.mutex.Lock()
+	dimension.subChunkRange = subChunkRange
+	dimension.mutex.Unlock()
+	if dimension.chunkProvider != nil {
+		dimension.chunkProvider.SetSubChunkRange(subChunkRange)
+	}
+}
+
+// GetSubChunkFormat returns the format sub chunks of chunks in the dimension get sent to viewers in.
+func (dimension *Dimension) GetSubChunkFormat() chunks.SubChunkFormat {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.subChunkFormat
+}
+
+// SetSubChunkFormat sets the format sub chunks of chunks in the dimension get sent to viewers in,
+// such as chunks.SubChunkFormatPaletted for viewers requiring runtime IDs. Chunks already loaded are updated immediately.
+func (dimension *Dimension) SetSubChunkFormat(format chunks.SubChunkFormat) {
+	dimension.mutex.Lock()
+	dimension.subChunkFormat = format
+	dimension.mutex.Unlock()
+	if dimension.chunkProvider == nil {
+		return
+	}
+	for _, chunk := range dimension.chunkProvider.GetLoadedChunks() {
+		chunk.SetSubChunkFormat(format)
+	}
+}
+
 // GetBlockAt returns a block in the dimension at the given vector.
-// GetBlockAt returns an error when the chunk of the block was not loaded, and an error if a block with the given ID wasn't registered.
+// GetBlockAt returns an error when the chunk of the block was not loaded, and an error if a block with the given ID wasn't registered
+// and the block manager of the dimension has no fallback.
 func (dimension *Dimension) GetBlockAt(vector r3.Vector) (blocks.Block, error) {
 	var x, y, z = int(math.Floor(vector.X)), int(math.Floor(vector.Y)), int(math.Floor(vector.Z))
 	var chunk, ok = dimension.GetChunk(int32(x>>4), int32(z>>4))
@@ -231,8 +469,8 @@ func (dimension *Dimension) GetBlockAt(vector r3.Vector) (blocks.Block, error) {
 		return nil, UnloadedChunk
 	}
 	var id, meta = chunk.GetBlockId(x&15, y, z&15), chunk.GetBlockData(x&15, y, z&15)
-	var block, err = dimension.blockManager.Get(id, meta)
-	if err != nil {
+	var block, err = dimension.GetBlockManager().Get(id, meta)
+	if err == nil {
 		if nbt, ok := chunk.GetBlockNBTAt(x&15, y, z&15); ok {
 			block.SetNBT(nbt)
 		}
@@ -240,15 +478,128 @@ func (dimension *Dimension) GetBlockAt(vector r3.Vector) (blocks.Block, error) {
 	return block, err
 }
 
-// SetBlockAt sets a block at the given vector.
-// If the chunk at that position was not yet loaded, it loads it and places the block.
+// GetBlocksInAABB returns all blocks intersecting the given bounding box, including air.
+// Blocks in chunks that are not loaded are left out, in which case UnloadedChunk is returned with the other blocks.
+func (dimension *Dimension) GetBlocksInAABB(box utils.AABB) ([]chunks.BoxBlock, error) {
+	var minX, _, minZ, maxX, _, maxZ = box.GetBlockBounds()
+	var result []chunks.BoxBlock
+	var err error
+	for chunkX := minX >> 4; chunkX <= maxX>>4; chunkX++ {
+		for chunkZ := minZ >> 4; chunkZ <= maxZ>>4; chunkZ++ {
+			var chunk, ok = dimension.GetChunk(int32(chunkX), int32(chunkZ))
+			if !ok {
+				err = UnloadedChunk
+				continue
+			}
+			result = append(result, chunk.GetBlocksInBox(box)...)
+		}
+	}
+	return result, err
+}
+
+// GetCollisionBoxes returns the world collision boxes of all blocks intersecting the given bounding box.
+// Only boxes intersecting the bounding box are returned. Blocks in chunks that are not loaded are left out,
+// in which case UnloadedChunk is returned with the other boxes.
+func (dimension *Dimension) GetCollisionBoxes(box utils.AABB) ([]utils.AABB, error) {
+	// Blocks one below the bounding box are included, as fences and walls reach into the block above them.
+	var query = box
+	query.Min.Y--
+	var boxBlocks, err = dimension.GetBlocksInAABB(query)
+	var boxes []utils.AABB
+	for _, block := range boxBlocks {
+		for _, blockBox := range shapes.GetWorldBoxes(block.Id, block.Data, block.X, block.Y, block.Z) {
+			if blockBox.Intersects(box) {
+				boxes = append(boxes, blockBox)
+			}
+		}
+	}
+	return boxes, err
+}
+
+// GetBlockLightAt returns the block light at the given vector.
+// GetBlockLightAt returns UnloadedChunk when the chunk of the position was not loaded.
+func (dimension *Dimension) GetBlockLightAt(vector r3.Vector) (byte, error) {
+	var x, y, z = int(math.Floor(vector.X)), int(math.Floor(vector.Y)), int(math.Floor(vector.Z))
+	var chunk, ok = dimension.GetChunk(int32(x>>4), int32(z>>4))
+	if !ok {
+		return 0, UnloadedChunk
+	}
+	return light.GetChunkLight(chunk, x&15, y, z&15, false), nil
+}
+
+// GetSkyLightAt returns the sky light at the given vector, adjusted for the time of day in the overworld.
+// GetSkyLightAt returns UnloadedChunk when the chunk of the position was not loaded.
+func (dimension *Dimension) GetSkyLightAt(vector r3.Vector) (byte, error) {
+	var x, y, z = int(math.Floor(vector.X)), int(math.Floor(vector.Y)), int(math.Floor(vector.Z))
+	var chunk, ok = dimension.GetChunk(int32(x>>4), int32(z>>4))
+	if !ok {
+		return 0, UnloadedChunk
+	}
+	var skyLight = light.GetChunkLight(chunk, x&15, y, z&15, true)
+	if dimension.id == OverworldId {
+		var subtracted = dimension.level.GetSkyLightSubtracted()
+		if subtracted >= skyLight {
+			return 0, nil
+		}
+		skyLight -= subtracted
+	}
+	return skyLight, nil
+}
+
+// GetLightAt returns the highest of the block light and the adjusted sky light at the given vector.
+// GetLightAt returns UnloadedChunk when the chunk of the position was not loaded.
+func (dimension *Dimension) GetLightAt(vector r3.Vector) (byte, error) {
+	var blockLight, err = dimension.GetBlockLightAt(vector)
+	if err != nil {
+		return 0, err
+	}
+	skyLight, err := dimension.GetSkyLightAt(vector)
+	if err != nil {
+		return 0, err
+	}
+	if skyLight > blockLight {
+		return skyLight, nil
+	}
+	return blockLight, nil
+}
+
+// GetLocalDifficulty returns the local difficulty of the chunk at the given chunk X and Z.
+// GetLocalDifficulty returns UnloadedChunk when the chunk was not loaded.
+func (dimension *Dimension) GetLocalDifficulty(chunkX, chunkZ int32) (LocalDifficulty, error) {
+	var chunk, ok = dimension.GetChunk(chunkX, chunkZ)
+	if !ok {
+		return LocalDifficulty{}, UnloadedChunk
+	}
+	return NewLocalDifficulty(dimension.level.GetDifficulty(), dimension.level.GetTime(), chunk.InhabitedTime), nil
+}
+
+// SetBlockAt sets a block at the given vector, executing all side effects.
+// If the chunk at that position was not yet loaded, it loads it and places the block. Blocks outside of the world border are not set.
 func (dimension *Dimension) SetBlockAt(vector r3.Vector, block blocks.Block) {
+	dimension.SetBlockAtWithFlags(vector, block, UpdateAll)
+}
+
+// SetBlockAtWithFlags sets a block at the given vector, skipping the side effects opted out of by the flags.
+// If the chunk at that position was not yet loaded, it loads it and places the block. Blocks outside of the world border are not set.
+func (dimension *Dimension) SetBlockAtWithFlags(vector r3.Vector, block blocks.Block, flags BlockUpdateFlags) {
 	var x, y, z = int(math.Floor(vector.X)), int(math.Floor(vector.Y)), int(math.Floor(vector.Z))
+	if !dimension.isBlockWithinBorder(x, y, z) {
+		return
+	}
 	dimension.LoadChunk(int32(x>>4), int32(z>>4), func(chunk *chunks.Chunk) {
+		var previousId, previousData = chunk.GetBlockId(x&15, y, z&15), chunk.GetBlockData(x&15, y, z&15)
 		chunk.SetBlockId(x&15, y, z&15, block.GetId())
 		chunk.SetBlockData(x&15, y, z&15, block.GetData())
 		chunk.SetBlockNBTAt(x&15, y, z&15, block.GetNBT())
-		dimension.SetBlockForUpdate(vector)
+		dimension.setBlockEntity(x, y, z, block.GetNBT())
+		if !flags.Has(NoLightUpdate) {
+			chunk.SetHeightMapAt(x&15, z&15, chunk.GetHighestBlockY(x&15, z&15)+1)
+			dimension.lightEngine.UpdateBlock(x, y, z)
+		}
+		if !flags.Has(NoBroadcast) {
+			dimension.SetBlockForUpdate(vector)
+		}
+		dimension.callBlockChange(x, y, z, block, previousId, previousData, flags)
 	})
 }
 
@@ -259,24 +610,34 @@ func (dimension *Dimension) HasBlockUpdates() bool {
 
 // SetBlockUpdate sets a block at a certain position to be updated on the next tick
 func (dimension *Dimension) SetBlockForUpdate(vector r3.Vector) {
-	var x, y, z = int64(math.Floor(vector.X)), int64(math.Floor(vector.Y)), int64(math.Floor(vector.Z))
-	dimension.blockUpdates[(x << 12) | (z << 8) | y] = vector
+	dimension.blockUpdates[getBlockUpdateKey(vector)] = vector
+}
+
+// SetBlocksForUpdate sets the blocks at all given positions to be updated on the next tick at once.
+func (dimension *Dimension) SetBlocksForUpdate(vectors []r3.Vector) {
+	for _, vector := range vectors {
+		dimension.blockUpdates[getBlockUpdateKey(vector)] = vector
+	}
 }
 
 // SetBlockUpdate removes a block at a certain position from being updated
 func (dimension *Dimension) UnsetBlockFromUpdate(vector r3.Vector) {
-	var x, y, z = int64(math.Floor(vector.X)), int64(math.Floor(vector.Y)), int64(math.Floor(vector.Z))
-	var index = (x << 12) | (z << 8) | y
+	var index = getBlockUpdateKey(vector)
 	if _, ok := dimension.blockUpdates[index]; ok {
 		delete(dimension.blockUpdates, index)
 	}
 }
 
-// getUpdatedBlocks returns all the blocks and runtime ids that need to be updated
-// the first return value is all the positions of the blocks that need to be changed
-// the second return value is all the runtime ids for the blocks that need to be changed
-func (dimension *Dimension) getUpdatedBlocks() ([]blocks.Position, []uint32) {
-	var runtimeIds []uint32
+// getBlockUpdateKey returns the key of the block at the given position in the block updates of a dimension.
+// Keys hold the full block coordinates, so that every position, including negative Y, has its own key.
+func getBlockUpdateKey(vector r3.Vector) [3]int {
+	return [3]int{int(math.Floor(vector.X)), int(math.Floor(vector.Y)), int(math.Floor(vector.Z))}
+}
+
+// getUpdatedBlocks returns the positions of all blocks that need to be updated, and the legacy IDs of the blocks at those positions.
+// The legacy ID is the block ID shifted left by 4, OR'd with the block data.
+func (dimension *Dimension) getUpdatedBlocks() ([]blocks.Position, []int) {
+	var legacyIds []int
 	var position []blocks.Position
 	for index, vector := range dimension.blockUpdates {
 		var x, y, z = int(math.Floor(vector.X)), int(math.Floor(vector.Y)), int(math.Floor(vector.Z))
@@ -284,36 +645,64 @@ func (dimension *Dimension) getUpdatedBlocks() ([]blocks.Position, []uint32) {
 			blockId := int(chunk.GetBlockId(x&15, y, z&15))
 			blockData := int(chunk.GetBlockData(x&15, y, z&15))
 
-			if runtimeId, ok := blocks.GetRuntimeId(blockId, blockData); ok {
-				runtimeIds = append(runtimeIds, runtimeId)
-				position = append(position, utils.VectorToPosition(vector))
-			}
+			legacyIds = append(legacyIds, blockId<<4|blockData)
+			position = append(position, utils.VectorToPosition(vector))
 			delete(dimension.blockUpdates, index)
 		})
 	}
-	return position, runtimeIds
+	return position, legacyIds
 }
 
 // ProcessBlockUpdates processes all the block update requests
 func (dimension *Dimension) ProcessBlockUpdates() {
-	var positions, runtimeIds = dimension.getUpdatedBlocks()
+	var positions, legacyIds = dimension.getUpdatedBlocks()
 	for index := range positions {
 		for _, viewer := range dimension.GetViewers() {
-			viewer.SendUpdateBlock(positions[index], runtimeIds[index], 0)
+			if runtimeId, ok := dimension.GetViewerRuntimeId(viewer, legacyIds[index]>>4, legacyIds[index]&15); ok {
+				viewer.SendUpdateBlock(positions[index], runtimeId, 0)
+			}
+		}
+	}
+}
+
+// SetTranslator sets the translator used to get the runtime IDs of blocks sent to viewers playing on other protocol versions.
+// All blocks get sent with the runtime IDs of the selected runtime IDs table if nil.
+func (dimension *Dimension) SetTranslator(translator *blocks.Translator) {
+	dimension.mutex.Lock()
+	dimension.translator = translator
+	dimension.mutex.Unlock()
+}
+
+// GetTranslator returns the translator of the dimension, or nil if it has none.
+func (dimension *Dimension) GetTranslator() *blocks.Translator {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.translator
+}
+
+// GetViewerRuntimeId returns the runtime ID of the given block ID and block data to send to the viewer, and a bool indicating if it was found.
+// Viewers implementing chunks.ProtocolViewer get the runtime ID of their protocol if the translator of the dimension has it,
+// all other viewers get the runtime ID of the selected runtime IDs table.
+func (dimension *Dimension) GetViewerRuntimeId(viewer chunks.Viewer, blockId, blockData int) (uint32, bool) {
+	if protocolViewer, ok := viewer.(chunks.ProtocolViewer); ok {
+		if translator := dimension.GetTranslator(); translator != nil && translator.HasProtocol(protocolViewer.GetProtocol()) {
+			return translator.GetRuntimeId(protocolViewer.GetProtocol(), blockId, blockData)
 		}
 	}
+	return blocks.GetRuntimeId(blockId, blockData)
 }
 
-// Tick ticks the entire dimension, such as entities.
+// Tick ticks the entire dimension, such as entities, block entities, scheduled tasks, scheduled block updates and random ticks.
 func (dimension *Dimension) Tick() {
+	dimension.scheduler.run()
+	dimension.processBlockTicks()
+	dimension.randomTick()
+	dimension.tickBlockEntities()
+	dimension.spawnMobs()
+	dimension.tickWeather()
+	dimension.tickWorldBorder()
 	if dimension.HasBlockUpdates() {
 		dimension.ProcessBlockUpdates()
 	}
-	for runtimeId, entity := range dimension.entities {
-		if entity.IsClosed() {
-			dimension.RemoveEntity(runtimeId)
-		} else {
-			entity.Tick()
-		}
-	}
+	dimension.tickEntities()
 }
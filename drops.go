@@ -0,0 +1,33 @@
+package worlds
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/chunks"
+	"math/rand"
+)
+
+// DroppedItem is an entity that can be dropped in a dimension, usually an item entity.
+type DroppedItem interface {
+	chunks.ChunkEntity
+	SetMotion(r3.Vector)
+}
+
+// DropItemNaturally adds the given item entity to the dimension the way Minecraft drops items naturally.
+// The position gets jittered up to 0.25 blocks in every direction,
+// and the item gets a small random horizontal motion and a small upward motion.
+func (dimension *Dimension) DropItemNaturally(item DroppedItem, position r3.Vector) {
+	position = position.Add(r3.Vector{
+		X: rand.Float64()*0.5 - 0.25,
+		Y: rand.Float64()*0.5 - 0.25,
+		Z: rand.Float64()*0.5 - 0.25,
+	})
+	item.SetMotion(r3.Vector{X: rand.Float64()*0.2 - 0.1, Y: 0.2, Z: rand.Float64()*0.2 - 0.1})
+	dimension.AddEntity(item, position)
+}
+
+// DropItemsNaturally drops all the given item entities naturally at the given position.
+func (dimension *Dimension) DropItemsNaturally(items []DroppedItem, position r3.Vector) {
+	for _, item := range items {
+		dimension.DropItemNaturally(item, position)
+	}
+}
@@ -0,0 +1,192 @@
+package entities
+
+import (
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/entities/data"
+)
+
+const (
+	// BabyTicks is the amount of ticks it takes a baby to grow up.
+	BabyTicks = 24000
+	// BreedingCooldown is the amount of ticks an entity can not breed after breeding.
+	BreedingCooldown = 6000
+	// LoveTicks is the amount of ticks an entity stays in love mode.
+	LoveTicks = 600
+	// BabyScale is the scale of babies.
+	BabyScale = 0.5
+)
+
+// GrowFunction gets called when a baby entity grows up.
+type GrowFunction func(entity *Entity)
+
+// ageState is the age and breeding state of an entity.
+// Like Minecraft, the age is negative for babies, counting up to 0 at which they grow up,
+// and positive after breeding, counting down to 0 at which they can breed again.
+type ageState struct {
+	age       int
+	loveTicks int
+
+	growFunction GrowFunction
+}
+
+// SetGrowFunction sets the function called when the entity grows up from a baby.
+func (entity *Entity) SetGrowFunction(function GrowFunction) {
+	entity.mutex.Lock()
+	entity.age.growFunction = function
+	entity.mutex.Unlock()
+}
+
+// GetAge returns the age of the entity: the negative amount of ticks until a baby grows up,
+// or the amount of ticks until the entity can breed again.
+func (entity *Entity) GetAge() int {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.age.age
+}
+
+// SetAge sets the age of the entity. A negative age makes the entity a baby.
+func (entity *Entity) SetAge(age int) {
+	entity.mutex.Lock()
+	var wasBaby = entity.age.age < 0
+	entity.age.age = age
+	entity.mutex.Unlock()
+	if wasBaby != (age < 0) {
+		entity.updateBaby(age < 0)
+	}
+}
+
+// IsBaby checks if the entity is a baby.
+func (entity *Entity) IsBaby() bool {
+	return entity.GetAge() < 0
+}
+
+// SetBaby makes the entity a baby that grows up after the baby ticks, or makes it an adult instantly.
+// The grow function is not called when the entity is made an adult this way.
+func (entity *Entity) SetBaby(value bool) {
+	if value {
+		entity.SetAge(-BabyTicks)
+	} else if entity.IsBaby() {
+		entity.SetAge(0)
+	}
+}
+
+// updateBaby sets the baby flag and scale of the entity, so that viewers see it as a baby or adult.
+func (entity *Entity) updateBaby(baby bool) {
+	entity.SetEntityProperty(data.EntityDataBaby, baby)
+	if baby {
+		entity.SetScale(BabyScale)
+	} else {
+		entity.SetScale(1)
+	}
+}
+
+// IsInLove checks if the entity is in love mode, looking for a partner to breed with.
+func (entity *Entity) IsInLove() bool {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.age.loveTicks > 0
+}
+
+// CanBreed checks if the entity is an adult without breeding cooldown.
+func (entity *Entity) CanBreed() bool {
+	return entity.GetAge() == 0
+}
+
+// SetInLove puts the entity in love mode for the love ticks, usually after being fed.
+// Returns false if the entity can not breed, or if it is already in love.
+func (entity *Entity) SetInLove() bool {
+	if !entity.CanBreed() || entity.IsInLove() {
+		return false
+	}
+	entity.mutex.Lock()
+	entity.age.loveTicks = LoveTicks
+	entity.mutex.Unlock()
+	entity.SetEntityProperty(data.EntityDataInlove, true)
+	return true
+}
+
+// ResetLove takes the entity out of love mode.
+func (entity *Entity) ResetLove() {
+	entity.mutex.Lock()
+	var inLove = entity.age.loveTicks > 0
+	entity.age.loveTicks = 0
+	entity.mutex.Unlock()
+	if inLove {
+		entity.SetEntityProperty(data.EntityDataInlove, false)
+	}
+}
+
+// CanBreedWith checks if the entity and the partner are both in love, and of the same entity type.
+func (entity *Entity) CanBreedWith(partner *Entity) bool {
+	return partner != entity && partner.entityType == entity.entityType && entity.IsInLove() && partner.IsInLove()
+}
+
+// Breed makes the entity breed with the partner, taking both out of love mode and giving them the breeding cooldown.
+// Returns false if the entity can not breed with the partner. Spawning the baby is left to the caller.
+func (entity *Entity) Breed(partner *Entity) bool {
+	if !entity.CanBreedWith(partner) {
+		return false
+	}
+	for _, parent := range [2]*Entity{entity, partner} {
+		parent.ResetLove()
+		parent.SetAge(BreedingCooldown)
+	}
+	return true
+}
+
+// tickAge counts the age of the entity towards 0, growing up babies and counting down love mode.
+func (entity *Entity) tickAge() {
+	entity.mutex.Lock()
+	var grown = false
+	if entity.age.age < 0 {
+		entity.age.age++
+		grown = entity.age.age == 0
+	} else if entity.age.age > 0 {
+		entity.age.age--
+	}
+	var loveEnded = false
+	if entity.age.loveTicks > 0 {
+		entity.age.loveTicks--
+		loveEnded = entity.age.loveTicks == 0
+	}
+	var growFunction = entity.age.growFunction
+	entity.mutex.Unlock()
+
+	if loveEnded {
+		entity.SetEntityProperty(data.EntityDataInlove, false)
+	}
+	if grown {
+		entity.updateBaby(false)
+		if growFunction != nil {
+			growFunction(entity)
+		}
+	}
+}
+
+// ageToNBT writes the age and love ticks of the entity into the NBT, if the entity has them.
+func (entity *Entity) ageToNBT(nbt *gonbt.Compound) {
+	entity.mutex.RLock()
+	var age, loveTicks = entity.age.age, entity.age.loveTicks
+	entity.mutex.RUnlock()
+	if age != 0 {
+		nbt.SetTag(gonbt.NewInt("Age", int32(age)))
+	} else {
+		nbt.RemoveTag("Age")
+	}
+	if loveTicks > 0 {
+		nbt.SetTag(gonbt.NewInt("InLove", int32(loveTicks)))
+	} else {
+		nbt.RemoveTag("InLove")
+	}
+}
+
+// ageFromNBT loads the age and love ticks of the entity from the NBT.
+func (entity *Entity) ageFromNBT(nbt *gonbt.Compound) {
+	entity.SetAge(int(nbt.GetInt("Age", int32(entity.GetAge()))))
+	if loveTicks := nbt.GetInt("InLove", 0); loveTicks > 0 {
+		entity.mutex.Lock()
+		entity.age.loveTicks = int(loveTicks)
+		entity.mutex.Unlock()
+		entity.SetEntityProperty(data.EntityDataInlove, true)
+	}
+}
@@ -0,0 +1,325 @@
+package ai
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds"
+	"github.com/irmine/worlds/chunks"
+	"github.com/irmine/worlds/entities"
+	"github.com/irmine/worlds/entities/data"
+	"math/rand"
+)
+
+// Wander is a goal making the entity walk to random positions around it every now and then.
+type Wander struct {
+	// Speed is the distance the entity walks per tick.
+	Speed float64
+	// Chance is the chance per tick of the entity starting to wander, as one in Chance.
+	Chance int
+	// Range is the maximum horizontal distance to the positions wandered to.
+	Range int
+
+	target r3.Vector
+	ticks  int
+}
+
+// WanderTimeout is the maximum amount of ticks an entity wanders to the same position.
+const WanderTimeout = 120
+
+// NewWander returns a new wander goal with the given speed, chance and range.
+func NewWander(speed float64, chance, wanderRange int) *Wander {
+	return &Wander{speed, chance, wanderRange, r3.Vector{}, 0}
+}
+
+// CanStart checks if the entity happens to start wandering.
+func (wander *Wander) CanStart(entity *entities.Entity) bool {
+	return wander.Chance > 0 && wander.Range > 0 && rand.Intn(wander.Chance) == 0
+}
+
+// CanContinue checks if the entity has not yet reached the position it wanders to.
+func (wander *Wander) CanContinue(entity *entities.Entity) bool {
+	return wander.ticks < WanderTimeout && horizontalDistance(entity.GetPosition(), wander.target) > 0.5
+}
+
+// Start picks a random position around the entity to wander to.
+func (wander *Wander) Start(entity *entities.Entity) {
+	wander.target = entity.GetPosition().Add(r3.Vector{
+		X: float64(rand.Intn(wander.Range*2+1) - wander.Range),
+		Z: float64(rand.Intn(wander.Range*2+1) - wander.Range),
+	})
+	wander.ticks = 0
+}
+
+// Stop stops the entity from walking.
+func (wander *Wander) Stop(entity *entities.Entity) {
+	stopMoving(entity)
+}
+
+// Tick walks the entity towards the position it wanders to.
+func (wander *Wander) Tick(entity *entities.Entity) {
+	moveTowards(entity, wander.target, wander.Speed)
+	wander.ticks++
+}
+
+// GetFlags returns FlagMove.
+func (wander *Wander) GetFlags() Flag {
+	return FlagMove
+}
+
+// LookAtViewer is a goal making the entity look at the nearest viewer every now and then.
+type LookAtViewer struct {
+	// Range is the maximum distance to viewers looked at.
+	Range float64
+	// Chance is the chance per tick of the entity starting to look at a viewer, as one in Chance.
+	Chance int
+
+	target chunks.ChunkEntity
+	ticks  int
+}
+
+// LookDuration is the amount of ticks an entity looks at a viewer.
+const LookDuration = 80
+
+// NewLookAtViewer returns a new look at viewer goal with the given range and chance.
+func NewLookAtViewer(lookRange float64, chance int) *LookAtViewer {
+	return &LookAtViewer{lookRange, chance, nil, 0}
+}
+
+// CanStart checks if the entity happens to start looking, and a viewer is within range.
+func (look *LookAtViewer) CanStart(entity *entities.Entity) bool {
+	if look.Chance <= 0 || rand.Intn(look.Chance) != 0 {
+		return false
+	}
+	var target, ok = nearestViewer(entity, look.Range)
+	look.target = target
+	return ok
+}
+
+// CanContinue checks if the viewer is still within range, and the entity did not look at it for too long.
+func (look *LookAtViewer) CanContinue(entity *entities.Entity) bool {
+	return look.ticks < LookDuration && isValidTarget(entity, look.target, look.Range)
+}
+
+// Start starts looking at the viewer.
+func (look *LookAtViewer) Start(entity *entities.Entity) {
+	look.ticks = 0
+}
+
+// Stop stops looking at the viewer.
+func (look *LookAtViewer) Stop(entity *entities.Entity) {
+	look.target = nil
+}
+
+// Tick rotates the entity to face the eyes of the viewer.
+func (look *LookAtViewer) Tick(entity *entities.Entity) {
+	lookAt(entity, look.target.GetPosition().Add(r3.Vector{Y: ViewerEyeHeight}))
+	look.ticks++
+}
+
+// GetFlags returns FlagLook.
+func (look *LookAtViewer) GetFlags() Flag {
+	return FlagLook
+}
+
+// Panic is a goal making the entity run around in random directions after it got hurt.
+type Panic struct {
+	// Speed is the distance the entity runs per tick.
+	Speed float64
+	// Duration is the amount of ticks the entity keeps running.
+	Duration int
+
+	lastHealth float32
+	target     r3.Vector
+	ticks      int
+}
+
+// NewPanic returns a new panic goal with the given speed and duration.
+func NewPanic(speed float64, duration int) *Panic {
+	return &Panic{speed, duration, 0, r3.Vector{}, 0}
+}
+
+// CanStart checks if the entity lost health since it was last checked.
+func (panicGoal *Panic) CanStart(entity *entities.Entity) bool {
+	var health = entity.GetHealth()
+	var hurt = health < panicGoal.lastHealth
+	panicGoal.lastHealth = health
+	return hurt
+}
+
+// CanContinue checks if the entity has not yet run for the duration of the panic.
+func (panicGoal *Panic) CanContinue(entity *entities.Entity) bool {
+	return panicGoal.ticks < panicGoal.Duration
+}
+
+// Start picks the first position to run to.
+func (panicGoal *Panic) Start(entity *entities.Entity) {
+	panicGoal.ticks = 0
+	panicGoal.pickTarget(entity)
+}
+
+// Stop stops the entity from running.
+func (panicGoal *Panic) Stop(entity *entities.Entity) {
+	panicGoal.lastHealth = entity.GetHealth()
+	stopMoving(entity)
+}
+
+// Tick runs the entity towards its target, picking a new target once reached.
+func (panicGoal *Panic) Tick(entity *entities.Entity) {
+	if horizontalDistance(entity.GetPosition(), panicGoal.target) < 1 {
+		panicGoal.pickTarget(entity)
+	}
+	moveTowards(entity, panicGoal.target, panicGoal.Speed)
+	panicGoal.ticks++
+}
+
+// pickTarget picks a random position up to 5 blocks away from the entity to run to.
+func (panicGoal *Panic) pickTarget(entity *entities.Entity) {
+	panicGoal.target = entity.GetPosition().Add(r3.Vector{X: float64(rand.Intn(11) - 5), Z: float64(rand.Intn(11) - 5)})
+}
+
+// GetFlags returns FlagMove.
+func (panicGoal *Panic) GetFlags() Flag {
+	return FlagMove
+}
+
+// MeleeAttack is a goal making the entity chase a target and attack it once close enough.
+type MeleeAttack struct {
+	// Speed is the distance the entity runs per tick while chasing its target.
+	Speed float64
+	// Range is the maximum distance the entity chases targets from.
+	Range float64
+	// Reach is the maximum distance the entity attacks its target from.
+	Reach float64
+	// Cooldown is the amount of ticks between attacks.
+	Cooldown int
+	// FindTarget returns the target to attack, and a bool indicating if one was found. The nearest viewer in range is attacked if nil.
+	FindTarget func(entity *entities.Entity) (chunks.ChunkEntity, bool)
+	// AttackFunction gets called every time the entity attacks its target, and should deal the damage of the attack.
+	// Targets that are entities of the entities package get damaged by the attack damage of the entity and knocked back if nil.
+	AttackFunction func(attacker *entities.Entity, target chunks.ChunkEntity)
+
+	target   chunks.ChunkEntity
+	cooldown int
+}
+
+// NewMeleeAttack returns a new melee attack goal attacking the nearest viewer in range, calling the attack function for every attack.
+// The attack function may be nil to damage targets by the attack damage of the entity.
+func NewMeleeAttack(speed, attackRange, reach float64, cooldown int, attackFunction func(attacker *entities.Entity, target chunks.ChunkEntity)) *MeleeAttack {
+	return &MeleeAttack{speed, attackRange, reach, cooldown, nil, attackFunction, nil, 0}
+}
+
+// CanStart checks if the entity has a target to attack.
+func (attack *MeleeAttack) CanStart(entity *entities.Entity) bool {
+	var target, ok = attack.findTarget(entity)
+	attack.target = target
+	return ok
+}
+
+// findTarget returns the target of the entity using FindTarget, or the nearest viewer in range if nil.
+func (attack *MeleeAttack) findTarget(entity *entities.Entity) (chunks.ChunkEntity, bool) {
+	if attack.FindTarget != nil {
+		return attack.FindTarget(entity)
+	}
+	return nearestViewer(entity, attack.Range)
+}
+
+// CanContinue checks if the target is still in range of the entity.
+func (attack *MeleeAttack) CanContinue(entity *entities.Entity) bool {
+	return isValidTarget(entity, attack.target, attack.Range)
+}
+
+// Start resets the attack cooldown, so the entity attacks as soon as it reaches its target.
+func (attack *MeleeAttack) Start(entity *entities.Entity) {
+	attack.cooldown = 0
+}
+
+// Stop stops the entity from chasing its target.
+func (attack *MeleeAttack) Stop(entity *entities.Entity) {
+	attack.target = nil
+	stopMoving(entity)
+}
+
+// Tick chases the target, and attacks it if within reach and the cooldown has passed.
+func (attack *MeleeAttack) Tick(entity *entities.Entity) {
+	var position = attack.target.GetPosition()
+	if attack.cooldown > 0 {
+		attack.cooldown--
+	}
+	if position.Distance(entity.GetPosition()) > attack.Reach {
+		moveTowards(entity, position, attack.Speed)
+		return
+	}
+	stopMoving(entity)
+	lookAt(entity, position.Add(r3.Vector{Y: ViewerEyeHeight}))
+	if attack.cooldown == 0 {
+		if attack.AttackFunction != nil {
+			attack.AttackFunction(entity, attack.target)
+		} else if target, ok := attack.target.(*entities.Entity); ok && entity.GetAttributeMap().Exists(data.AttributeAttackDamage) {
+			var damage = entity.GetAttributeMap().GetAttribute(data.AttributeAttackDamage).Value
+			if target.Damage(worlds.DamageSource{Cause: worlds.DamageAttack, Attacker: entity}, damage) {
+				target.Knockback(position.Sub(entity.GetPosition()), entities.DefaultKnockback)
+			}
+		}
+		attack.cooldown = attack.Cooldown
+	}
+}
+
+// GetFlags returns FlagMove and FlagLook.
+func (attack *MeleeAttack) GetFlags() Flag {
+	return FlagMove | FlagLook
+}
+
+// FollowOwner is a goal making the entity follow its owner once it is too far away, such as a tamed wolf.
+type FollowOwner struct {
+	// Speed is the distance the entity walks per tick while following its owner.
+	Speed float64
+	// StartDistance is the distance to the owner at which the entity starts following.
+	StartDistance float64
+	// StopDistance is the distance to the owner at which the entity stops following.
+	StopDistance float64
+	// Owner returns the owner of the entity, and a bool indicating if the entity has one that is in the world.
+	Owner func(entity *entities.Entity) (chunks.ChunkEntity, bool)
+
+	owner chunks.ChunkEntity
+}
+
+// NewFollowOwner returns a new follow owner goal with the given speed and distances, getting the owner from the function.
+func NewFollowOwner(speed, startDistance, stopDistance float64, owner func(entity *entities.Entity) (chunks.ChunkEntity, bool)) *FollowOwner {
+	return &FollowOwner{speed, startDistance, stopDistance, owner, nil}
+}
+
+// CanStart checks if the entity has an owner further away than the start distance.
+func (follow *FollowOwner) CanStart(entity *entities.Entity) bool {
+	if follow.Owner == nil {
+		return false
+	}
+	var owner, ok = follow.Owner(entity)
+	if !ok || owner.IsClosed() || owner.GetPosition().Distance(entity.GetPosition()) <= follow.StartDistance {
+		return false
+	}
+	follow.owner = owner
+	return true
+}
+
+// CanContinue checks if the owner is still in the world and further away than the stop distance.
+func (follow *FollowOwner) CanContinue(entity *entities.Entity) bool {
+	return !follow.owner.IsClosed() && follow.owner.GetPosition().Distance(entity.GetPosition()) > follow.StopDistance
+}
+
+// Start does nothing, as the entity starts following in its first tick.
+func (follow *FollowOwner) Start(entity *entities.Entity) {}
+
+// Stop stops the entity from following its owner.
+func (follow *FollowOwner) Stop(entity *entities.Entity) {
+	follow.owner = nil
+	stopMoving(entity)
+}
+
+// Tick walks the entity towards its owner.
+func (follow *FollowOwner) Tick(entity *entities.Entity) {
+	moveTowards(entity, follow.owner.GetPosition(), follow.Speed)
+}
+
+// GetFlags returns FlagMove and FlagLook.
+func (follow *FollowOwner) GetFlags() Flag {
+	return FlagMove | FlagLook
+}
@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/chunks"
+	"github.com/irmine/worlds/entities"
+	"github.com/irmine/worlds/entities/data"
+	"github.com/irmine/worlds/pathfinding"
+	"math"
+)
+
+// ViewerEyeHeight is the height of the eyes of viewers above their position, which entities look at.
+const ViewerEyeHeight = 1.62
+
+// JumpVelocity is the upwards motion entities jump with to climb onto blocks while following a path.
+const JumpVelocity = 0.42
+
+// FollowPath moves the entity along the path with the given speed, jumping onto blocks on the way.
+// It returns false once the end of the path was reached.
+func FollowPath(entity *entities.Entity, path *pathfinding.Path, speed float64) bool {
+	var point, ok = path.GetCurrent()
+	var position = entity.GetPosition()
+	if ok && horizontalDistance(position, point) < 0.5 && math.Abs(position.Y-point.Y) < 1 {
+		path.Advance()
+		point, ok = path.GetCurrent()
+	}
+	if !ok {
+		stopMoving(entity)
+		return false
+	}
+	moveTowards(entity, point, speed)
+	if point.Y-position.Y > 0.6 && entity.IsOnGround() {
+		entity.UpdateMotion(func(motion r3.Vector) r3.Vector {
+			motion.Y = JumpVelocity
+			return motion
+		})
+	}
+	return true
+}
+
+// moveTowards sets the horizontal motion of the entity towards the target with the given speed, and turns the entity to face it.
+func moveTowards(entity *entities.Entity, target r3.Vector, speed float64) {
+	var difference = target.Sub(entity.GetPosition())
+	difference.Y = 0
+	if difference.Norm2() == 0 {
+		return
+	}
+	var push = difference.Normalize().Mul(speed)
+	entity.UpdateMotion(func(motion r3.Vector) r3.Vector {
+		motion.X, motion.Z = push.X, push.Z
+		return motion
+	})
+	lookAt(entity, r3.Vector{X: target.X, Y: entity.GetPosition().Y, Z: target.Z})
+}
+
+// stopMoving removes the horizontal motion of the entity.
+func stopMoving(entity *entities.Entity) {
+	entity.UpdateMotion(func(motion r3.Vector) r3.Vector {
+		motion.X, motion.Z = 0, 0
+		return motion
+	})
+}
+
+// lookAt rotates the entity and its head to face the target.
+func lookAt(entity *entities.Entity, target r3.Vector) {
+	var difference = target.Sub(entity.GetPosition())
+	var yaw = math.Atan2(-difference.X, difference.Z) * 180 / math.Pi
+	var pitch = -math.Atan2(difference.Y, math.Hypot(difference.X, difference.Z)) * 180 / math.Pi
+	entity.SetRotation(data.Rotation{Yaw: yaw, HeadYaw: yaw, Pitch: pitch})
+}
+
+// horizontalDistance returns the distance between the two positions, ignoring their height.
+func horizontalDistance(a, b r3.Vector) float64 {
+	return math.Hypot(a.X-b.X, a.Z-b.Z)
+}
+
+// nearestViewer returns the nearest viewer entity within the given range of the entity, and a bool indicating if one was found.
+func nearestViewer(entity *entities.Entity, maxRange float64) (chunks.ChunkEntity, bool) {
+	var dimension = entity.GetDimension()
+	if dimension == nil {
+		return nil, false
+	}
+	var nearest chunks.ChunkEntity
+	var nearestDistance = math.Inf(1)
+	for _, candidate := range dimension.GetNearbyEntities(entity.GetPosition(), maxRange) {
+		if _, ok := candidate.(chunks.Viewer); !ok || candidate.GetRuntimeId() == entity.GetRuntimeId() {
+			continue
+		}
+		if distance := candidate.GetPosition().Distance(entity.GetPosition()); distance < nearestDistance {
+			nearest, nearestDistance = candidate, distance
+		}
+	}
+	return nearest, nearest != nil
+}
+
+// isValidTarget checks if the target is still in the world and within the given range of the entity.
+func isValidTarget(entity *entities.Entity, target chunks.ChunkEntity, maxRange float64) bool {
+	return target != nil && !target.IsClosed() && target.GetPosition().Distance(entity.GetPosition()) <= maxRange
+}
@@ -0,0 +1,129 @@
+package ai
+
+import (
+	"github.com/irmine/worlds/entities"
+	"sort"
+	"sync"
+)
+
+// Flag is a resource of an entity a goal uses, such as moving or looking around.
+// Goals using the same flags never run at the same time. Flags can be combined using bitwise OR.
+type Flag byte
+
+const (
+	// FlagMove is used by goals moving the entity.
+	FlagMove Flag = 1 << iota
+	// FlagLook is used by goals rotating the entity.
+	FlagLook
+)
+
+// Goal is something an entity can do, such as wandering around or attacking a target.
+type Goal interface {
+	// CanStart checks if the goal can start running.
+	CanStart(entity *entities.Entity) bool
+	// CanContinue checks if the goal should keep running after it was started.
+	CanContinue(entity *entities.Entity) bool
+	// Start gets called when the goal starts running.
+	Start(entity *entities.Entity)
+	// Stop gets called when the goal stops running, either because it can no longer continue or because it got interrupted.
+	Stop(entity *entities.Entity)
+	// Tick gets called every tick while the goal is running.
+	Tick(entity *entities.Entity)
+	// GetFlags returns the flags the goal uses.
+	GetFlags() Flag
+}
+
+// entry is a goal added to a selector, with its priority and whether it is running.
+type entry struct {
+	priority int
+	goal     Goal
+	running  bool
+}
+
+// Selector runs the goals of an entity, picking the goals with the lowest priority able to run.
+// A goal interrupts running goals with a higher priority using any of the same flags.
+// Selectors implement entities.Controller, and should be set as controller of the entity.
+type Selector struct {
+	mutex   sync.Mutex
+	entries []*entry
+}
+
+// NewSelector returns a new selector without goals.
+func NewSelector() *Selector {
+	return &Selector{sync.Mutex{}, nil}
+}
+
+// AddGoal adds a goal with the given priority to the selector. Goals with a lower priority take precedence.
+func (selector *Selector) AddGoal(priority int, goal Goal) {
+	selector.mutex.Lock()
+	selector.entries = append(selector.entries, &entry{priority, goal, false})
+	sort.SliceStable(selector.entries, func(i, j int) bool {
+		return selector.entries[i].priority < selector.entries[j].priority
+	})
+	selector.mutex.Unlock()
+}
+
+// RemoveGoal removes the goal from the selector. The goal is not stopped if it was running.
+func (selector *Selector) RemoveGoal(goal Goal) {
+	selector.mutex.Lock()
+	for i, current := range selector.entries {
+		if current.goal == goal {
+			selector.entries = append(selector.entries[:i], selector.entries[i+1:]...)
+			break
+		}
+	}
+	selector.mutex.Unlock()
+}
+
+// GetRunningGoals returns all goals currently running, ordered by priority.
+func (selector *Selector) GetRunningGoals() []Goal {
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+	var goals []Goal
+	for _, current := range selector.entries {
+		if current.running {
+			goals = append(goals, current.goal)
+		}
+	}
+	return goals
+}
+
+// Tick stops running goals that can no longer continue, starts goals able to run, and ticks all running goals.
+func (selector *Selector) Tick(entity *entities.Entity) {
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+	for _, current := range selector.entries {
+		if current.running && !current.goal.CanContinue(entity) {
+			current.running = false
+			current.goal.Stop(entity)
+		}
+	}
+	for _, current := range selector.entries {
+		if current.running || !selector.canInterrupt(current) || !current.goal.CanStart(entity) {
+			continue
+		}
+		for _, other := range selector.entries {
+			if other.running && other.goal.GetFlags()&current.goal.GetFlags() != 0 {
+				other.running = false
+				other.goal.Stop(entity)
+			}
+		}
+		current.running = true
+		current.goal.Start(entity)
+	}
+	for _, current := range selector.entries {
+		if current.running {
+			current.goal.Tick(entity)
+		}
+	}
+}
+
+// canInterrupt checks if the goal of the entry may interrupt all running goals using any of the same flags.
+func (selector *Selector) canInterrupt(goalEntry *entry) bool {
+	for _, other := range selector.entries {
+		if other.running && other.goal.GetFlags()&goalEntry.goal.GetFlags() != 0 && other.priority <= goalEntry.priority {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,27 @@
+package entities
+
+// SendAttributes sends all attributes of the entity to a certain viewer.
+func (entity *Entity) SendAttributes(viewer Viewer) {
+	viewer.SendUpdateAttributes(entity.GetRuntimeId(), entity.attributeMap.GetAttributes())
+}
+
+// BroadcastUpdatedAttributes sends the attributes of the entity that changed since they were last sent to all viewers.
+func (entity *Entity) BroadcastUpdatedAttributes() {
+	var attributes = entity.attributeMap.GetDirty()
+	if len(attributes) == 0 {
+		return
+	}
+	for _, attribute := range attributes {
+		attribute.MarkClean()
+	}
+	for _, viewer := range entity.GetViewers() {
+		viewer.SendUpdateAttributes(entity.GetRuntimeId(), attributes)
+	}
+}
+
+// ResendAttributes marks all attributes of the entity as changed, so that they all get sent to viewers on the next tick.
+// This should be used when a viewer resets the attributes of the entity.
+// Respawn and Teleport to another dimension resend the attributes automatically.
+func (entity *Entity) ResendAttributes() {
+	entity.attributeMap.MarkDirty()
+}
@@ -0,0 +1,21 @@
+package entities
+
+// Controller decides what an entity does every tick, such as the AI of a mob.
+type Controller interface {
+	// Tick gets called every tick of the entity, before it gets moved by its motion.
+	Tick(entity *Entity)
+}
+
+// SetController sets the controller of the entity. The entity is not controlled if nil.
+func (entity *Entity) SetController(controller Controller) {
+	entity.mutex.Lock()
+	entity.controller = controller
+	entity.mutex.Unlock()
+}
+
+// GetController returns the controller of the entity, or nil if it has none.
+func (entity *Entity) GetController() Controller {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.controller
+}
@@ -0,0 +1,162 @@
+package entities
+
+import (
+	"errors"
+	"github.com/irmine/gonbt"
+	"strings"
+)
+
+// CustomDataTag is the name of the compound in the entity NBT custom data is saved in.
+const CustomDataTag = "CustomData"
+
+// InvalidCustomDataKey gets returned if custom data is set with a key that is not namespaced, such as "plugin:key".
+var InvalidCustomDataKey = errors.New("custom data key must be namespaced")
+
+// IsNamespacedKey checks if the key consists of a namespace and a name separated by a colon, such as "plugin:key".
+func IsNamespacedKey(key string) bool {
+	var parts = strings.Split(key, ":")
+	return len(parts) == 2 && parts[0] != "" && parts[1] != ""
+}
+
+// getCustomData returns the compound holding the custom data of the entity, creating it if needed.
+// The entity must be locked while calling it.
+func (entity *Entity) getCustomData() *gonbt.Compound {
+	if entity.customData == nil {
+		entity.customData = gonbt.NewCompound(CustomDataTag, make(map[string]gonbt.INamedTag))
+	}
+	return entity.customData
+}
+
+// setCustomTag sets the tag as custom data of the entity under the namespaced key.
+func (entity *Entity) setCustomTag(key string, tag gonbt.INamedTag) error {
+	if !IsNamespacedKey(key) {
+		return InvalidCustomDataKey
+	}
+	entity.mutex.Lock()
+	entity.getCustomData().SetTag(tag)
+	entity.mutex.Unlock()
+	return nil
+}
+
+// hasCustomTag checks if the entity has custom data under the key with the given tag type.
+// The entity must be locked while calling it.
+func (entity *Entity) hasCustomTag(key string, tagType byte) bool {
+	return entity.customData != nil && entity.customData.HasTagWithType(key, tagType)
+}
+
+// SetCustomString sets a string under the namespaced key in the custom data of the entity.
+func (entity *Entity) SetCustomString(key, value string) error {
+	return entity.setCustomTag(key, gonbt.NewString(key, value))
+}
+
+// GetCustomString returns the string under the key in the custom data of the entity, and a bool indicating if it was found.
+func (entity *Entity) GetCustomString(key string) (string, bool) {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	if !entity.hasCustomTag(key, gonbt.TAG_String) {
+		return "", false
+	}
+	return entity.customData.GetString(key, ""), true
+}
+
+// SetCustomInt sets an integer under the namespaced key in the custom data of the entity.
+func (entity *Entity) SetCustomInt(key string, value int64) error {
+	return entity.setCustomTag(key, gonbt.NewLong(key, value))
+}
+
+// GetCustomInt returns the integer under the key in the custom data of the entity, and a bool indicating if it was found.
+func (entity *Entity) GetCustomInt(key string) (int64, bool) {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	if !entity.hasCustomTag(key, gonbt.TAG_Long) {
+		return 0, false
+	}
+	return entity.customData.GetLong(key, 0), true
+}
+
+// SetCustomFloat sets a floating point number under the namespaced key in the custom data of the entity.
+func (entity *Entity) SetCustomFloat(key string, value float64) error {
+	return entity.setCustomTag(key, gonbt.NewDouble(key, value))
+}
+
+// GetCustomFloat returns the floating point number under the key in the custom data of the entity, and a bool indicating if it was found.
+func (entity *Entity) GetCustomFloat(key string) (float64, bool) {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	if !entity.hasCustomTag(key, gonbt.TAG_Double) {
+		return 0, false
+	}
+	return entity.customData.GetDouble(key, 0), true
+}
+
+// SetCustomNBT sets a compound under the namespaced key in the custom data of the entity.
+// The compound gets renamed to the key.
+func (entity *Entity) SetCustomNBT(key string, compound *gonbt.Compound) error {
+	if compound == nil {
+		compound = gonbt.NewCompound(key, make(map[string]gonbt.INamedTag))
+	}
+	compound.SetName(key)
+	return entity.setCustomTag(key, compound)
+}
+
+// GetCustomNBT returns the compound under the key in the custom data of the entity, and a bool indicating if it was found.
+// Changes made to the compound are saved along with the entity.
+func (entity *Entity) GetCustomNBT(key string) (*gonbt.Compound, bool) {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	if !entity.hasCustomTag(key, gonbt.TAG_Compound) {
+		return nil, false
+	}
+	var compound, ok = entity.customData.GetTag(key).(*gonbt.Compound)
+	return compound, ok
+}
+
+// HasCustomData checks if the entity has custom data under the key.
+func (entity *Entity) HasCustomData(key string) bool {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.customData != nil && entity.customData.HasTag(key)
+}
+
+// RemoveCustomData removes the custom data under the key from the entity.
+func (entity *Entity) RemoveCustomData(key string) {
+	entity.mutex.Lock()
+	if entity.customData != nil {
+		entity.customData.RemoveTag(key)
+	}
+	entity.mutex.Unlock()
+}
+
+// GetCustomDataKeys returns the keys of all custom data of the entity.
+// Keys of a namespace can be found by checking for the "namespace:" prefix.
+func (entity *Entity) GetCustomDataKeys() []string {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	if entity.customData == nil {
+		return nil
+	}
+	var keys = make([]string, 0, len(entity.customData.GetTags()))
+	for key := range entity.customData.GetTags() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// customDataToNBT writes the custom data of the entity into the NBT, if the entity has any.
+func (entity *Entity) customDataToNBT(nbt *gonbt.Compound) {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	if entity.customData == nil || len(entity.customData.GetTags()) == 0 {
+		nbt.RemoveTag(CustomDataTag)
+		return
+	}
+	nbt.SetTag(entity.customData)
+}
+
+// customDataFromNBT loads the custom data of the entity from the NBT.
+func (entity *Entity) customDataFromNBT(nbt *gonbt.Compound) {
+	var customData = nbt.GetCompound(CustomDataTag)
+	entity.mutex.Lock()
+	entity.customData = customData
+	entity.mutex.Unlock()
+}
@@ -0,0 +1,310 @@
+package entities
+
+import (
+	"errors"
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds"
+	"github.com/irmine/worlds/entities/data"
+	"math"
+)
+
+// DamageCause is the cause of damage an entity takes.
+// It is defined in the worlds package, so that the entity hooks of dimensions can use it.
+type DamageCause = worlds.DamageCause
+
+const (
+	// DamageCustom is damage dealt by plugins.
+	DamageCustom = worlds.DamageCustom
+	// DamageAttack is damage dealt by another entity attacking.
+	DamageAttack = worlds.DamageAttack
+	// DamageFall is damage taken by landing after falling further than the safe fall distance.
+	DamageFall = worlds.DamageFall
+	// DamageFire is damage taken by standing in or burning from fire.
+	DamageFire = worlds.DamageFire
+	// DamageVoid is damage taken by falling out of the world. It ignores armor and invulnerability.
+	DamageVoid = worlds.DamageVoid
+	// DamageLightning is damage taken by getting struck by lightning.
+	DamageLightning = worlds.DamageLightning
+)
+
+// DamageSource is the source of damage an entity takes.
+// It is defined in the worlds package, so that the entity hooks of dimensions can use it.
+type DamageSource = worlds.DamageSource
+
+// EntityEvent is an event of an entity played by viewers, such as the animation of getting hurt.
+type EntityEvent byte
+
+const (
+	EventHurt  EntityEvent = 2
+	EventDeath EntityEvent = 3
+)
+
+// EventViewer is a viewer that can be sent entity events.
+// Viewers not implementing this interface do not see entities getting hurt or dying.
+type EventViewer interface {
+	Viewer
+	SendEntityEvent(runtimeId uint64, event EntityEvent)
+}
+
+const (
+	// InvulnerabilityTicks is the amount of ticks an entity only takes the part of new damage exceeding the damage it last took.
+	InvulnerabilityTicks = 10
+	// DeathTicks is the amount of ticks a dead entity stays in the world playing its death animation before it gets despawned.
+	DeathTicks = 20
+	// SafeFallDistance is the distance an entity can fall without taking fall damage.
+	SafeFallDistance = 3
+	// VoidDepth is the depth below the sub chunk range of the dimension at which entities take void damage.
+	VoidDepth = 64
+	// VoidDamage is the damage entities take every tick in the void.
+	VoidDamage = 4
+)
+
+// ArmorFunction returns the damage left after the armor of the entity reduced the damage.
+// It is not called for fall damage and void damage, which ignore armor.
+type ArmorFunction func(entity *Entity, source worlds.DamageSource, amount float32) float32
+
+// DamageFunction gets called before the entity takes damage, with the damage left after armor.
+// The entity does not take the damage if the function returns false.
+// It gets called before the damage hook of the dimension of the entity.
+type DamageFunction func(entity *Entity, source worlds.DamageSource, amount float32) bool
+
+// DeathFunction gets called when the entity dies, with the source of the damage that killed it.
+type DeathFunction func(entity *Entity, source worlds.DamageSource)
+
+// damageState is the state of an entity taking damage.
+type damageState struct {
+	invulnerableTicks int
+	lastDamage        float32
+	fallDistance      float64
+	fireTicks         int
+
+	dead       bool
+	deathTicks int
+
+	armorFunction  ArmorFunction
+	damageFunction DamageFunction
+	deathFunction  DeathFunction
+	lootFunction   LootFunction
+}
+
+// SetArmorFunction sets the function reducing damage taken by the entity by its armor.
+func (entity *Entity) SetArmorFunction(function ArmorFunction) {
+	entity.mutex.Lock()
+	entity.damage.armorFunction = function
+	entity.mutex.Unlock()
+}
+
+// SetDamageFunction sets the function called every time before the entity takes damage, which can cancel the damage.
+func (entity *Entity) SetDamageFunction(function DamageFunction) {
+	entity.mutex.Lock()
+	entity.damage.damageFunction = function
+	entity.mutex.Unlock()
+}
+
+// SetDeathFunction sets the function called when the entity dies.
+func (entity *Entity) SetDeathFunction(function DeathFunction) {
+	entity.mutex.Lock()
+	entity.damage.deathFunction = function
+	entity.mutex.Unlock()
+}
+
+// getDamageFunctions returns the armor, damage and death functions of the entity.
+func (entity *Entity) getDamageFunctions() (ArmorFunction, DamageFunction, DeathFunction) {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.damage.armorFunction, entity.damage.damageFunction, entity.damage.deathFunction
+}
+
+// IsDead checks if the entity died, and is playing its death animation before getting despawned.
+func (entity *Entity) IsDead() bool {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.damage.dead
+}
+
+// IsInvulnerable checks if the entity recently took damage, and only takes the part of new damage exceeding it.
+func (entity *Entity) IsInvulnerable() bool {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.damage.invulnerableTicks > 0
+}
+
+// getDamageDealt returns the damage dealt by the given amount of damage before armor, and false if the entity takes no damage.
+// The entity must be locked when calling getDamageDealt.
+func (entity *Entity) getDamageDealt(source worlds.DamageSource, amount float32) (float32, bool) {
+	if entity.closed || entity.damage.dead || amount <= 0 {
+		return 0, false
+	}
+	if entity.damage.invulnerableTicks > 0 && source.Cause != worlds.DamageVoid {
+		if amount <= entity.damage.lastDamage {
+			return 0, false
+		}
+		return amount - entity.damage.lastDamage, true
+	}
+	return amount, true
+}
+
+// markDead marks the entity as dead, and returns false if it already was dead.
+// The entity must be locked when calling markDead.
+func (entity *Entity) markDead() bool {
+	if entity.damage.dead {
+		return false
+	}
+	entity.damage.dead = true
+	entity.damage.deathTicks = 0
+	return true
+}
+
+// Damage damages the entity by the given amount, and returns true if the entity took damage.
+// While invulnerable, only the part of the damage exceeding the damage last taken is dealt, unless the damage is void damage.
+// The damage is reduced by the armor function and absorbed by the absorption of the entity first, after which the health of the entity is lowered.
+// The entity dies if its health reaches 0. Damage is safe to call from other goroutines than the one ticking the entity.
+func (entity *Entity) Damage(source worlds.DamageSource, amount float32) bool {
+	entity.mutex.RLock()
+	var dealt, ok = entity.getDamageDealt(source, amount)
+	entity.mutex.RUnlock()
+	if !ok {
+		return false
+	}
+
+	var armorFunction, damageFunction, deathFunction = entity.getDamageFunctions()
+	if armorFunction != nil && source.Cause != worlds.DamageFall && source.Cause != worlds.DamageVoid {
+		dealt = armorFunction(entity, source, dealt)
+	}
+	if damageFunction != nil && !damageFunction(entity, source, dealt) {
+		return false
+	}
+	if hook := entity.getHooks().OnEntityDamage; hook != nil && !hook(entity.GetOuterEntity(), source, dealt) {
+		return false
+	}
+
+	entity.mutex.Lock()
+	// The entity may have taken damage from another goroutine while the functions were called.
+	if _, ok := entity.getDamageDealt(source, amount); !ok {
+		entity.mutex.Unlock()
+		return false
+	}
+	entity.damage.lastDamage = amount
+	if entity.damage.invulnerableTicks <= 0 || source.Cause == worlds.DamageVoid {
+		entity.damage.invulnerableTicks = InvulnerabilityTicks
+	}
+	if absorption := entity.attributeMap.GetAttribute(data.AttributeAbsorption); absorption != nil && dealt > 0 {
+		var absorbed = float32(math.Min(float64(absorption.Value), float64(dealt)))
+		absorption.Value -= absorbed
+		dealt -= absorbed
+	}
+	var health = entity.attributeMap.GetAttribute(data.AttributeHealth)
+	if dealt > 0 {
+		health.Value = float32(math.Max(float64(health.Value-dealt), 0))
+	}
+	var died = health.Value <= 0 && entity.markDead()
+	entity.mutex.Unlock()
+
+	entity.BroadcastEvent(EventHurt)
+	if died {
+		entity.die(source, deathFunction)
+	}
+	return true
+}
+
+// die plays the death animation of the entity after it was marked as dead, calling the death function.
+func (entity *Entity) die(source worlds.DamageSource, deathFunction DeathFunction) {
+	entity.mutex.Lock()
+	entity.Motion.X, entity.Motion.Z = 0, 0
+	entity.mutex.Unlock()
+	entity.BroadcastEvent(EventDeath)
+	if deathFunction != nil {
+		deathFunction(entity, source)
+	}
+	if hook := entity.getHooks().OnEntityDeath; hook != nil {
+		hook(entity.GetOuterEntity(), source)
+	}
+	entity.dropLoot(source)
+}
+
+// NotDead gets returned when an entity that is not dead is attempted to be respawned.
+var NotDead = errors.New("tried to respawn entity that is not dead")
+
+// Respawn brings the dead entity back to life with full health at the position in the dimension, moving it there with Teleport.
+// All attributes of the entity are resent, as viewers reset them when an entity respawns.
+// Respawn must be called before the death animation of the entity finished, as the entity gets closed after that.
+func (entity *Entity) Respawn(dimension *worlds.Dimension, position r3.Vector) error {
+	entity.mutex.Lock()
+	if !entity.damage.dead {
+		entity.mutex.Unlock()
+		return NotDead
+	}
+	entity.damage.dead = false
+	entity.damage.deathTicks = 0
+	entity.damage.fallDistance = 0
+	var health = entity.attributeMap.GetAttribute(data.AttributeHealth)
+	health.Value = health.MaxValue
+	entity.mutex.Unlock()
+	entity.Extinguish()
+	entity.ResendAttributes()
+	return entity.Teleport(dimension, position)
+}
+
+// getHooks returns the entity hooks of the dimension of the entity, if it is in a dimension.
+func (entity *Entity) getHooks() worlds.EntityHooks {
+	var dimension = entity.GetDimension()
+	if dimension == nil {
+		return worlds.EntityHooks{}
+	}
+	return dimension.GetEntityHooks()
+}
+
+// BroadcastEvent sends the entity event to all viewers supporting entity events.
+func (entity *Entity) BroadcastEvent(event EntityEvent) {
+	for _, viewer := range entity.GetViewers() {
+		if eventViewer, ok := viewer.(EventViewer); ok {
+			eventViewer.SendEntityEvent(entity.GetRuntimeId(), event)
+		}
+	}
+}
+
+// tickDamage counts down the invulnerability of the entity, deals void damage, and despawns the entity once it has been dead long enough.
+// It returns false if the entity got despawned.
+func (entity *Entity) tickDamage() bool {
+	entity.mutex.Lock()
+	if entity.damage.invulnerableTicks > 0 {
+		entity.damage.invulnerableTicks--
+	}
+	var dead = entity.damage.dead
+	if dead {
+		entity.damage.deathTicks++
+	}
+	var despawn = dead && entity.damage.deathTicks >= DeathTicks
+	entity.mutex.Unlock()
+	if despawn {
+		entity.Close()
+		return false
+	}
+	if dead {
+		return true
+	}
+	var dimension = entity.GetDimension()
+	if dimension != nil && entity.entityType != Player && entity.GetPosition().Y < float64(dimension.GetSubChunkRange().GetMinY()-VoidDepth) {
+		entity.Damage(worlds.DamageSource{Cause: worlds.DamageVoid}, VoidDamage)
+	}
+	entity.tickFire()
+	return true
+}
+
+// updateFall tracks the distance the entity fell with the vertical movement, and deals fall damage once the entity lands.
+func (entity *Entity) updateFall(movementY float64) {
+	entity.mutex.Lock()
+	if movementY < 0 {
+		entity.damage.fallDistance -= movementY
+	}
+	if !entity.OnGround {
+		entity.mutex.Unlock()
+		return
+	}
+	var damage = math.Ceil(entity.damage.fallDistance - SafeFallDistance)
+	entity.damage.fallDistance = 0
+	entity.mutex.Unlock()
+	if damage > 0 {
+		entity.Damage(worlds.DamageSource{Cause: worlds.DamageFall}, float32(damage))
+	}
+}
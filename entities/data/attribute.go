@@ -27,6 +27,10 @@ type Attribute struct {
 	MaxValue     float32
 	Value        float32
 	DefaultValue float32
+
+	// sent holds the min, max, current and default value of the attribute when it was last marked clean.
+	sent   [4]float32
+	synced bool
 }
 
 // AttributeMap is a struct containing an unlimited amount of attributes.
@@ -52,7 +56,7 @@ func NewAttributeMap() AttributeMap {
 
 // NewAttribute returns a new Attribute with the given name.
 func NewAttribute(name AttributeName, value, maxValue float32) *Attribute {
-	return &Attribute{name, 0, maxValue, value, value}
+	return &Attribute{name, 0, maxValue, value, value, [4]float32{}, false}
 }
 
 // GetName returns the name of the attribute.
@@ -60,6 +64,27 @@ func (attribute *Attribute) GetName() AttributeName {
 	return attribute.name
 }
 
+// values returns the min, max, current and default value of the attribute.
+func (attribute *Attribute) values() [4]float32 {
+	return [4]float32{attribute.MinValue, attribute.MaxValue, attribute.Value, attribute.DefaultValue}
+}
+
+// IsDirty checks if the attribute changed since it was last marked clean.
+// Attributes that were never marked clean are always dirty.
+func (attribute *Attribute) IsDirty() bool {
+	return !attribute.synced || attribute.sent != attribute.values()
+}
+
+// MarkClean marks the attribute as clean, usually after it has been sent to viewers.
+func (attribute *Attribute) MarkClean() {
+	attribute.sent, attribute.synced = attribute.values(), true
+}
+
+// MarkDirty marks the attribute as dirty, regardless of whether it changed.
+func (attribute *Attribute) MarkDirty() {
+	attribute.synced = false
+}
+
 // Exists checks if an attribute with the given name exists.
 func (attMap AttributeMap) Exists(name AttributeName) bool {
 	var _, ok = attMap[name]
@@ -75,3 +100,37 @@ func (attMap AttributeMap) SetAttribute(attribute *Attribute) {
 func (attMap AttributeMap) GetAttribute(name AttributeName) *Attribute {
 	return attMap[name]
 }
+
+// GetAttributes returns all attributes in this attribute map.
+func (attMap AttributeMap) GetAttributes() []*Attribute {
+	var attributes = make([]*Attribute, 0, len(attMap))
+	for _, attribute := range attMap {
+		attributes = append(attributes, attribute)
+	}
+	return attributes
+}
+
+// GetDirty returns all attributes in this attribute map that changed since they were last marked clean.
+func (attMap AttributeMap) GetDirty() []*Attribute {
+	var attributes []*Attribute
+	for _, attribute := range attMap {
+		if attribute.IsDirty() {
+			attributes = append(attributes, attribute)
+		}
+	}
+	return attributes
+}
+
+// MarkClean marks all attributes in this attribute map as clean.
+func (attMap AttributeMap) MarkClean() {
+	for _, attribute := range attMap {
+		attribute.MarkClean()
+	}
+}
+
+// MarkDirty marks all attributes in this attribute map as dirty.
+func (attMap AttributeMap) MarkDirty() {
+	for _, attribute := range attMap {
+		attribute.MarkDirty()
+	}
+}
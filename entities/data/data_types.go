@@ -8,8 +8,19 @@ const (
 //temp values
 //TODO
 const (
+	EntityDataNameTag = 4 // string
 	EntityDataAir = 7 // short
+	EntityDataExperienceValue = 15 // int
 	EntityDataMaxAir = 42 // short
+	EntityDataScale = 38 // float
+	EntityDataBoundingBoxWidth = 53 // float
+	EntityDataBoundingBoxHeight = 54 // float
+	EntityDataRiderSeatPosition = 57 // vector
+	EntityDataRiderRotationLocked = 58 // byte
+	EntityDataRiderMaxRotation = 59 // float
+	EntityDataRiderMinRotation = 60 // float
+	EntityDataAlwaysShowNameTag = 81 // byte
+	EntityDataScoreTag = 84 // string
 )
 
 const (
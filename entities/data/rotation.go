@@ -1,6 +1,32 @@
 package data
 
 // Rotation contains a yaw and pitch and is used to define entity rotation.
+// The body and head of an entity rotate separately, so that mobs can look around without turning their body.
 type Rotation struct {
-	Yaw, HeadYaw, Pitch float64
+	// Yaw is the yaw of the body of the entity.
+	Yaw float64
+	// HeadYaw is the yaw of the head of the entity.
+	HeadYaw float64
+	// Pitch is the pitch of the head of the entity. The body of an entity never pitches.
+	Pitch float64
+}
+
+// NewRotation returns a new rotation with the given body yaw, head yaw and head pitch.
+func NewRotation(yaw, headYaw, pitch float64) Rotation {
+	return Rotation{yaw, headYaw, pitch}
+}
+
+// GetBodyYaw returns the yaw of the body.
+func (rotation Rotation) GetBodyYaw() float64 {
+	return rotation.Yaw
+}
+
+// GetHeadYaw returns the yaw of the head.
+func (rotation Rotation) GetHeadYaw() float64 {
+	return rotation.HeadYaw
+}
+
+// GetHeadPitch returns the pitch of the head.
+func (rotation Rotation) GetHeadPitch() float64 {
+	return rotation.Pitch
 }
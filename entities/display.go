@@ -0,0 +1,97 @@
+package entities
+
+// DisplayKey is a key of display data of an entity.
+// Display data is used by plugins to show information around an entity,
+// and is kept separate from the entity data sent with Bedrock metadata IDs.
+type DisplayKey string
+
+const (
+	// DisplayBelowName is the text shown below the name tag of an entity.
+	DisplayBelowName DisplayKey = "belowname"
+	// DisplayBossBar is the ID of the boss bar the entity is associated with.
+	DisplayBossBar DisplayKey = "bossbar"
+	// DisplayNameTagSeeThrough is a bool specifying if the name tag of an entity is rendered through blocks.
+	// Bedrock has no metadata for it, so it is left to display viewers to show.
+	DisplayNameTagSeeThrough DisplayKey = "nametagseethrough"
+)
+
+// DisplayViewer is a viewer that can be notified of display data changes.
+// Viewers not implementing this interface do not receive display data.
+type DisplayViewer interface {
+	Viewer
+	SendDisplayData(runtimeId uint64, displayData map[DisplayKey]interface{})
+}
+
+// SetDisplayData sets the display data at the given key and marks it for sending.
+func (entity *Entity) SetDisplayData(key DisplayKey, value interface{}) {
+	entity.mutex.Lock()
+	entity.displayData[key], entity.updatedDisplayData[key] = value, value
+	entity.hasDisplayDataUpdate = true
+	entity.mutex.Unlock()
+}
+
+// RemoveDisplayData removes the display data at the given key.
+// Viewers get notified of the removal by a nil value.
+func (entity *Entity) RemoveDisplayData(key DisplayKey) {
+	entity.mutex.Lock()
+	delete(entity.displayData, key)
+	entity.updatedDisplayData[key] = nil
+	entity.hasDisplayDataUpdate = true
+	entity.mutex.Unlock()
+}
+
+// GetDisplayData returns the display data at the given key, and a bool indicating if it was found.
+func (entity *Entity) GetDisplayData(key DisplayKey) (interface{}, bool) {
+	entity.mutex.RLock()
+	var value, ok = entity.displayData[key]
+	entity.mutex.RUnlock()
+	return value, ok
+}
+
+// GetAllDisplayData returns a copy of all display data of the entity.
+func (entity *Entity) GetAllDisplayData() map[DisplayKey]interface{} {
+	entity.mutex.RLock()
+	var displayData = make(map[DisplayKey]interface{}, len(entity.displayData))
+	for key, value := range entity.displayData {
+		displayData[key] = value
+	}
+	entity.mutex.RUnlock()
+	return displayData
+}
+
+// HasDisplayDataUpdate checks if display data of the entity changed since the updated display data was last shifted.
+func (entity *Entity) HasDisplayDataUpdate() bool {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.hasDisplayDataUpdate
+}
+
+// GetUpdatedDisplayData shifts and returns the updated display data for sending.
+func (entity *Entity) GetUpdatedDisplayData() map[DisplayKey]interface{} {
+	entity.mutex.Lock()
+	var displayData = entity.updatedDisplayData
+	entity.updatedDisplayData = make(map[DisplayKey]interface{})
+	entity.hasDisplayDataUpdate = false
+	entity.mutex.Unlock()
+	return displayData
+}
+
+// SendDisplayData sends all display data to the given viewer, if it supports display data.
+func (entity *Entity) SendDisplayData(viewer Viewer) {
+	if displayViewer, ok := viewer.(DisplayViewer); ok {
+		displayViewer.SendDisplayData(entity.GetRuntimeId(), entity.GetAllDisplayData())
+	}
+}
+
+// BroadcastUpdatedDisplayData sends the updated display data to all viewers supporting display data.
+func (entity *Entity) BroadcastUpdatedDisplayData() {
+	var displayData = entity.GetUpdatedDisplayData()
+	if len(displayData) == 0 {
+		return
+	}
+	for _, viewer := range entity.GetViewers() {
+		if displayViewer, ok := viewer.(DisplayViewer); ok {
+			displayViewer.SendDisplayData(entity.GetRuntimeId(), displayData)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/irmine/gomine/net/protocol"
 	"github.com/irmine/gonbt"
 	"github.com/irmine/worlds"
+	"github.com/irmine/worlds/blocks"
 	"github.com/irmine/worlds/chunks"
 	"github.com/irmine/worlds/entities/data"
 	"math"
@@ -25,6 +26,10 @@ type Viewer interface {
 	SendMoveEntity(uint64, r3.Vector, data.Rotation, byte, bool)
 	SendMovePlayer(uint64, r3.Vector, data.Rotation, byte, bool, uint64)
 	SendSetEntityData(uint64, map[uint32][]interface{})
+	SendSetEntityMotion(uint64, r3.Vector)
+	SendMobEquipment(uint64, blocks.ItemStack, EquipmentSlot)
+	SendMobArmorEquipment(uint64, [4]blocks.ItemStack)
+	SendUpdateAttributes(uint64, []*data.Attribute)
 }
 
 // Entity is a movable object in a dimension.
@@ -32,12 +37,16 @@ type Entity struct {
 	entityType   EntityType
 	attributeMap data.AttributeMap
 
+	// Position, Rotation, Motion and OnGround are only written with the entity locked, and by the goroutine ticking the entity.
+	// Other goroutines must read them using their getters.
 	Position r3.Vector
 	Rotation data.Rotation
 	Motion   r3.Vector
 	OnGround bool
 
+	// Dimension is only written with the entity locked, and must be read using GetDimension.
 	Dimension *worlds.Dimension
+	// NameTag is only written with the entity locked, and must be set using SetNameTag.
 	NameTag string
 
 	ridingId  uint64
@@ -52,12 +61,51 @@ type Entity struct {
 	SpawnedTo  map[uuid.UUID]Viewer
 
 	HasEntityDataUpdate bool
+	// HasMovementUpdate forces the movement of the entity to be broadcast next tick, even if it did not change beyond the epsilons.
 	HasMovementUpdate bool
+
+	displayData          map[DisplayKey]interface{}
+	updatedDisplayData   map[DisplayKey]interface{}
+	hasDisplayDataUpdate bool
+
+	movementHistory movementHistory
+
+	controller   Controller
+	moveFunction MoveFunction
+
+	damage damageState
+
+	equipment          equipment
+	HasEquipmentUpdate bool
+
+	viewRange  float64
+	teleported bool
+
+	scale float32
+
+	age ageState
+
+	customData *gonbt.Compound
+
+	addFunction AddFunction
+
+	lastBroadcast MovementSample
+	broadcasted   bool
+
+	riding ridingState
+
+	outer chunks.ChunkEntity
 }
 
 // UnloadedChunkMove gets returned when the location passed in SetPosition is in an unloaded chunk.
 var UnloadedChunkMove = errors.New("tried to move entity in unloaded chunk")
 
+// CancelledMove gets returned when the movement passed in SetPosition was cancelled by a move function or hook.
+var CancelledMove = errors.New("entity movement was cancelled")
+
+// OutsideBorderMove gets returned when the movement passed in SetPosition would move the entity outside of the world border.
+var OutsideBorderMove = errors.New("tried to move entity outside of the world border")
+
 // New returns a new entity by the given entity type.
 func New(entityType EntityType) *Entity {
 	ent := Entity{
@@ -79,24 +127,53 @@ func New(entityType EntityType) *Entity {
 		make(map[uuid.UUID]Viewer),
 		true,
 		false,
+		make(map[DisplayKey]interface{}),
+		make(map[DisplayKey]interface{}),
+		false,
+		movementHistory{},
+		nil,
+		nil,
+		damageState{},
+		equipment{},
+		false,
+		DefaultViewRange,
+		false,
+		1,
+		ageState{},
+		nil,
+		nil,
+		MovementSample{},
+		false,
+		ridingState{},
+		nil,
 	}
 
 	//ent.SetEntityDataFlag(data.EntityDataIdFlags, data.EntityDataLong, 0)
 
 	ent.SetEntityProperty(data.EntityDataAffectedByGravity, true)
 	ent.SetEntityDataFlag(data.EntityDataMaxAir, data.EntityDataShort, 400)
+	ent.updateSize()
 
 	return &ent
 }
 
-// GetNameTag returns the name tag of this entity.
-func (entity *Entity) GetNameTag() string {
-	return entity.NameTag
+// SetOuterEntity sets the entity embedding this entity, such as a human or experience orb.
+// The outer entity is added to chunks and passed to the hooks of the dimension in place of this entity,
+// so that chunk queries and saving see the outer entity. Entities embedding an entity must set it when created.
+func (entity *Entity) SetOuterEntity(outer chunks.ChunkEntity) {
+	entity.mutex.Lock()
+	entity.outer = outer
+	entity.mutex.Unlock()
 }
 
-// SetNameTag sets the name tag of this entity.
-func (entity *Entity) SetNameTag(nameTag string) {
-	entity.NameTag = nameTag
+// GetOuterEntity returns the entity embedding this entity, or the entity itself if it is not embedded.
+func (entity *Entity) GetOuterEntity() chunks.ChunkEntity {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	if entity.outer == nil {
+		return entity
+	}
+	return entity.outer
 }
 
 // GetAttributeMap returns the attribute map of this entity.
@@ -109,111 +186,212 @@ func (entity *Entity) SetAttributeMap(attMap data.AttributeMap) {
 	entity.attributeMap = attMap
 }
 
+// UpdateMotion sets the motion of the entity to the motion returned by the function, which is passed the current motion of the entity.
+// The motion is read and written in a single critical section, so that motion changed by other goroutines, such as by knockback, is not lost.
+// The function must not call methods of the entity.
+func (entity *Entity) UpdateMotion(function func(motion r3.Vector) r3.Vector) {
+	entity.mutex.Lock()
+	entity.Motion = function(entity.Motion)
+	entity.mutex.Unlock()
+}
+
 // UpdateEntityData tells the entity that there is a data updated that needs to be send
 func (entity *Entity) UpdateEntityData() {
+	entity.mutex.Lock()
 	entity.HasEntityDataUpdate = true
+	entity.mutex.Unlock()
 }
 
 // EntityDataFlagExists returns whether a flag exists or not
 func (entity *Entity) EntityDataFlagExists(flagId uint32) bool {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
 	_, ok := entity.entityData[flagId]
 	return ok
 }
 
 // SetEntityDataFlag sets a property id, flag id, and a value to the entity's data
 func (entity *Entity) SetEntityDataFlag(propId, flagId uint32, value interface{}) {
+	entity.mutex.Lock()
+	entity.setEntityDataFlag(propId, flagId, value)
+	entity.mutex.Unlock()
+}
+
+// setEntityDataFlag sets a property id, flag id, and a value to the entity's data.
+// The entity must be locked while calling it.
+func (entity *Entity) setEntityDataFlag(propId, flagId uint32, value interface{}) {
 	entity.entityData[propId], entity.updatedEntityData[propId] = []interface{}{flagId, value}, []interface{}{flagId, value}
-	entity.UpdateEntityData()
+	entity.HasEntityDataUpdate = true
 }
 
 // RemoveEntityDataFlag removes the entity data from a given property id
 func (entity *Entity) RemoveEntityDataFlag(propId uint32) {
+	entity.mutex.Lock()
 	delete(entity.entityData, propId)
 	delete(entity.updatedEntityData, propId)
-	entity.UpdateEntityData()
+	entity.HasEntityDataUpdate = true
+	entity.mutex.Unlock()
 }
 
 // GetDataFlag returns the values of the entity's data from a given property id
 // if there is no data found it will return negative integers with a -1 value
 func (entity *Entity) GetDataFlag(propId uint32) (v []interface{}) {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.getDataFlag(propId)
+}
+
+// getDataFlag returns the values of the entity's data from a given property id.
+// The entity must be locked while calling it.
+func (entity *Entity) getDataFlag(propId uint32) []interface{} {
 	if v, ok := entity.entityData[propId]; ok {
 		return v
 	}
 	return []interface{}{-1, -1}
 }
 
-// GetEntityData returns the entity data map.
+// GetEntityData returns a copy of the entity data map.
 func (entity *Entity) GetEntityData() map[uint32][]interface{} {
-	return entity.entityData
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	var entityData = make(map[uint32][]interface{}, len(entity.entityData))
+	for propId, value := range entity.entityData {
+		entityData[propId] = value
+	}
+	return entityData
 }
 
 // GetEntityData shifts and returns updated entity data for sending
 func (entity *Entity) GetUpdatedEntityData() map[uint32][]interface{} {
+	entity.mutex.Lock()
+	defer entity.mutex.Unlock()
 	var entityData = entity.updatedEntityData
 	entity.updatedEntityData = make(map[uint32][]interface{})
+	entity.HasEntityDataUpdate = false
 	return entityData
 }
 
 // GetPosition returns the current position of this entity.
 func (entity *Entity) GetPosition() r3.Vector {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
 	return entity.Position
 }
 
 // SetPosition sets the position of this entity
+// Entities not yet in a dimension only get their position set.
+// Entities in a dimension first pass the new position to their move function and the move hook of the dimension,
+// which may change it or cancel the movement, in which case CancelledMove gets returned.
+// Movement out of the world border of the dimension is passed to its cross function, and OutsideBorderMove gets returned if it is refused.
 func (entity *Entity) SetPosition(v r3.Vector) error {
+	// The dimension is read once, as the entity may get closed by another goroutine while moving.
+	var dimension = entity.GetDimension()
+	if dimension == nil {
+		entity.mutex.Lock()
+		entity.Position = v
+		entity.mutex.Unlock()
+		return nil
+	}
+	var allowed bool
+	if v, allowed = entity.interceptMove(dimension, v); !allowed {
+		return CancelledMove
+	}
+	if v, allowed = dimension.CrossBorder(entity.GetOuterEntity(), entity.GetPosition(), v); !allowed {
+		return OutsideBorderMove
+	}
 	var newChunkX = int32(math.Floor(float64(v.X))) >> 4
 	var newChunkZ = int32(math.Floor(float64(v.Z))) >> 4
 
 	var oldChunk = entity.GetChunk()
-	var newChunk, ok = entity.Dimension.GetChunk(newChunkX, newChunkZ)
+	var newChunk, ok = dimension.GetChunk(newChunkX, newChunkZ)
 	if !ok {
 		return UnloadedChunkMove
 	}
 
+	entity.mutex.Lock()
 	entity.Position = v
+	entity.mutex.Unlock()
 
 	if oldChunk != newChunk {
-		newChunk.AddEntity(entity)
+		newChunk.AddEntity(entity.GetOuterEntity())
+		entity.despawnFromChunkLeavers(newChunk)
 		entity.SpawnToAll()
-		oldChunk.RemoveEntity(entity.runtimeId)
+		// The old chunk is nil if it got unloaded, or if the entity is only just getting added to the dimension.
+		if oldChunk != nil {
+			oldChunk.RemoveEntity(entity.runtimeId)
+		}
 	}
 	return nil
 }
 
+// InitPosition sets the position of the entity without moving it, so no move hooks run, the world border is not checked
+// and the entity does not get moved between chunks. It is used by dimensions to place entities being added.
+func (entity *Entity) InitPosition(v r3.Vector) {
+	entity.mutex.Lock()
+	entity.Position = v
+	entity.mutex.Unlock()
+}
+
 // IsOnGround checks if the entity is on the ground.
 func (entity *Entity) IsOnGround() bool {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
 	return entity.OnGround
 }
 
 // GetChunk returns the chunk this entity is currently in.
+// Returns nil if the entity is not in a dimension, or if its chunk is not loaded.
 func (entity *Entity) GetChunk() *chunks.Chunk {
-	var x = int32(math.Floor(float64(entity.Position.X))) >> 4
-	var z = int32(math.Floor(float64(entity.Position.Z))) >> 4
-	var chunk, _ = entity.Dimension.GetChunk(x, z)
+	var dimension, position = entity.GetDimension(), entity.GetPosition()
+	if dimension == nil {
+		return nil
+	}
+	var chunk, _ = dimension.GetChunk(int32(math.Floor(position.X))>>4, int32(math.Floor(position.Z))>>4)
 	return chunk
 }
 
 // GetViewers returns all players that have the chunk loaded in which this entity is.
+// The viewers are copied on write, so the map returned can be iterated safely while viewers get added or removed.
+// The map returned must not be modified.
 func (entity *Entity) GetViewers() map[uuid.UUID]Viewer {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
 	return entity.SpawnedTo
 }
 
 // AddViewer adds a viewer to this entity.
 func (entity *Entity) AddViewer(viewer Viewer) {
 	entity.mutex.Lock()
-	entity.SpawnedTo[viewer.GetUUID()] = viewer
+	var viewers = copyViewers(entity.SpawnedTo, 1)
+	viewers[viewer.GetUUID()] = viewer
+	entity.SpawnedTo = viewers
 	entity.mutex.Unlock()
 }
 
 // RemoveViewer removes a viewer from this entity.
 func (entity *Entity) RemoveViewer(viewer Viewer) {
 	entity.mutex.Lock()
-	delete(entity.SpawnedTo, viewer.GetUUID())
+	if _, ok := entity.SpawnedTo[viewer.GetUUID()]; ok {
+		var viewers = copyViewers(entity.SpawnedTo, 0)
+		delete(viewers, viewer.GetUUID())
+		entity.SpawnedTo = viewers
+	}
 	entity.mutex.Unlock()
 }
 
+// copyViewers returns a copy of the viewers, with room for the given amount of extra viewers.
+func copyViewers(viewers map[uuid.UUID]Viewer, extra int) map[uuid.UUID]Viewer {
+	var c = make(map[uuid.UUID]Viewer, len(viewers)+extra)
+	for id, viewer := range viewers {
+		c[id] = viewer
+	}
+	return c
+}
+
 // GetDimension returns the dimension of this entity.
 func (entity *Entity) GetDimension() *worlds.Dimension {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
 	return entity.Dimension
 }
 
@@ -221,38 +399,52 @@ func (entity *Entity) GetDimension() *worlds.Dimension {
 func (entity *Entity) SetDimension(v interface {
 	GetChunk(int32, int32) (*chunks.Chunk, bool)
 }) {
+	entity.mutex.Lock()
 	entity.Dimension = v.(*worlds.Dimension)
+	entity.mutex.Unlock()
 }
 
 // GetRotation returns the current rotation of this entity.
 func (entity *Entity) GetRotation() data.Rotation {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
 	return entity.Rotation
 }
 
 // SetRotation sets the rotation of this entity.
 func (entity *Entity) SetRotation(v data.Rotation) {
+	entity.mutex.Lock()
 	entity.Rotation = v
+	entity.mutex.Unlock()
 }
 
 // GetMotion returns the motion of this entity.
 func (entity *Entity) GetMotion() r3.Vector {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
 	return entity.Motion
 }
 
 // SetMotion sets the motion of this entity.
 func (entity *Entity) SetMotion(v r3.Vector) {
+	entity.mutex.Lock()
 	entity.Motion = v
+	entity.mutex.Unlock()
 }
 
-// GetRidingId returns the runtime ID of the entity riding.
+// GetRidingId returns the runtime ID of the vehicle the entity is riding, or 0 if it is not riding.
 func (entity *Entity) GetRidingId() uint64 {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
 	return entity.ridingId
 }
 
-// SetRidingId sets the runtime ID of the entity riding.
-// SetRidingId should not be used by plugins.
+// SetRidingId sets the runtime ID of the vehicle the entity is riding.
+// SetRidingId should not be used by plugins, which should use Ride instead.
 func (entity *Entity) SetRidingId(id uint64) {
+	entity.mutex.Lock()
 	entity.ridingId = id
+	entity.mutex.Unlock()
 }
 
 // GetRuntimeId returns the runtime ID of the entity.
@@ -279,40 +471,70 @@ func (entity *Entity) GetEntityType() uint32 {
 
 // IsClosed checks if the entity is closed and not to be used anymore.
 func (entity *Entity) IsClosed() bool {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
 	return entity.closed
 }
 
 // Close closes the entity making it unable to be used.
 func (entity *Entity) Close() {
+	entity.Dismount()
+	entity.DismountRiders()
+	entity.mutex.Lock()
 	entity.closed = true
+	entity.mutex.Unlock()
 	entity.DespawnFromAll()
 
+	entity.mutex.Lock()
 	entity.Dimension = nil
 	entity.SpawnedTo = nil
+	entity.mutex.Unlock()
 }
 
 // GetHealth returns the health points of this entity.
 func (entity *Entity) GetHealth() float32 {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
 	return entity.attributeMap.GetAttribute(data.AttributeHealth).Value
 }
 
 // SetHealth sets the health points of this entity.
 func (entity *Entity) SetHealth(health float32) {
+	entity.mutex.Lock()
 	entity.attributeMap.GetAttribute(data.AttributeHealth).Value = health
+	entity.mutex.Unlock()
 }
 
-// Kill kills the entity.
+// Kill kills the entity, regardless of its health and invulnerability.
 func (entity *Entity) Kill() {
-	entity.SetHealth(0)
+	entity.mutex.Lock()
+	entity.attributeMap.GetAttribute(data.AttributeHealth).Value = 0
+	var died = entity.markDead()
+	entity.mutex.Unlock()
+	if died {
+		var _, _, deathFunction = entity.getDamageFunctions()
+		entity.die(worlds.DamageSource{Cause: worlds.DamageCustom}, deathFunction)
+	}
 }
 
 // SpawnTo spawns this entity to the given player.
+// Entities spawned while moving get spawned at their previous position and moved to their current one,
+// so that the movement gets interpolated by the viewer.
 func (entity *Entity) SpawnTo(viewer Viewer) {
 	if entity.IsClosed() {
 		return
 	}
 	entity.AddViewer(viewer)
-	viewer.SendAddEntity(entity)
+	if entity.IsMoving() {
+		entity.spawnInterpolated(viewer)
+	} else {
+		entity.sendAdd(viewer, entity)
+	}
+	entity.SendDisplayData(viewer)
+	entity.SendEquipment(viewer)
+	entity.SendAttributes(viewer)
+	entity.sendLinks(viewer)
+	entity.invalidateBroadcast()
 }
 
 // DespawnFrom despawns this entity from the given player.
@@ -323,13 +545,14 @@ func (entity *Entity) DespawnFrom(viewer Viewer) {
 
 // DespawnFromAll despawns this entity from all viewers.
 func (entity *Entity) DespawnFromAll() {
-	for _, viewer := range entity.SpawnedTo {
+	for _, viewer := range entity.GetViewers() {
 		entity.DespawnFrom(viewer)
 	}
 }
 
-// SpawnToAll spawns this entity to all players.
+// SpawnToAll spawns this entity to all viewers of its chunk within its view range.
 func (entity *Entity) SpawnToAll() {
+	var spawnedTo = entity.GetViewers()
 	for _, v := range entity.GetChunk().GetViewers() {
 		var (
 			viewer Viewer
@@ -338,7 +561,7 @@ func (entity *Entity) SpawnToAll() {
 		if viewer, ok = v.(Viewer); !ok {
 			continue
 		}
-		if _, ok := entity.SpawnedTo[viewer.GetUUID()]; !ok {
+		if _, ok := spawnedTo[viewer.GetUUID()]; !ok && entity.IsInViewRange(viewer) {
 			entity.SpawnTo(viewer)
 		}
 	}
@@ -347,11 +570,21 @@ func (entity *Entity) SpawnToAll() {
 // Sets a generic data flag by it's flag id, if value is true
 // it will set the flag, otherwise it will remove the flag
 func (entity *Entity) SetEntityProperty(flagId uint32, value bool) {
-	if entity.EntityDataFlagExists(data.EntityDataIdFlags) && !value {
-		entity.SetEntityDataFlag(data.EntityDataIdFlags, data.EntityDataLong, int64(1 << flagId) ^ int64(1 << flagId))
-	}else{
-		entity.SetEntityDataFlag(data.EntityDataIdFlags, data.EntityDataLong, int64(1 << flagId))
+	entity.mutex.Lock()
+	defer entity.mutex.Unlock()
+	var flags, _ = entity.getDataFlag(data.EntityDataIdFlags)[1].(int64)
+	if value {
+		flags |= 1 << flagId
+	} else {
+		flags &^= 1 << flagId
 	}
+	entity.setEntityDataFlag(data.EntityDataIdFlags, data.EntityDataLong, flags)
+}
+
+// GetEntityProperty checks if the generic data flag with the given flag id is set
+func (entity *Entity) GetEntityProperty(flagId uint32) bool {
+	var flags, _ = entity.GetDataFlag(data.EntityDataIdFlags)[1].(int64)
+	return flags&(1<<flagId) != 0
 }
 
 // Sends base entity data to a certain viewer
@@ -373,41 +606,92 @@ func (entity *Entity) SendUpdatedEntityData(viewer Viewer) {
 
 // Sends updated entity data to all viewers
 func (entity *Entity) BroadcastUpdatedEntityData() {
+	var entityData = entity.GetUpdatedEntityData()
+	if len(entityData) == 0 {
+		return
+	}
 	for _, viewer := range entity.GetViewers() {
-		viewer.SendSetEntityData(entity.GetRuntimeId(), entity.GetUpdatedEntityData())
+		viewer.SendSetEntityData(entity.GetRuntimeId(), entityData)
 	}
 }
 
-// Sends updated entity position and rotation to a certain viewer
-func (entity *Entity) SendMovement(viewer Viewer) {
-	viewer.SendMoveEntity(entity.runtimeId, entity.Position, entity.Rotation, 0, entity.OnGround)
+// Sends updated entity position and rotation to a certain viewer, in the given movement mode
+// The viewer no longer knows the last broadcast movement afterwards, so the next movement gets broadcast in full to all viewers.
+func (entity *Entity) SendMovement(viewer Viewer, mode MovementMode) {
+	var position, rotation, onGround = entity.getMovement()
+	viewer.SendMoveEntity(entity.runtimeId, position, rotation, byte(mode), onGround)
+	entity.invalidateBroadcast()
+}
+
+// invalidateBroadcast makes the next movement of the entity get broadcast in full to all viewers,
+// because a viewer was sent a movement other than the last broadcast movement.
+func (entity *Entity) invalidateBroadcast() {
+	entity.mutex.Lock()
+	entity.broadcasted = false
+	entity.mutex.Unlock()
 }
 
-// Sends updated entity position and rotation to all viewers
-func (entity *Entity) BroadcastMovement() {
+// Sends updated entity position and rotation to all viewers, in the given movement mode
+// Normal movements are sent as a delta from the last broadcast position to viewers supporting it.
+func (entity *Entity) BroadcastMovement(mode MovementMode) {
+	var position, rotation, onGround = entity.getMovement()
+	entity.mutex.Lock()
+	var last, broadcasted = entity.lastBroadcast, entity.broadcasted
+	entity.lastBroadcast, entity.broadcasted = MovementSample{position, rotation}, true
+	entity.mutex.Unlock()
 	for _, viewer := range entity.GetViewers() {
-		viewer.SendMoveEntity(entity.runtimeId, entity.Position, entity.Rotation, 0, entity.OnGround)
+		if deltaViewer, ok := viewer.(DeltaMoveViewer); ok && broadcasted && mode == MovementNormal {
+			deltaViewer.SendMoveEntityDelta(entity.runtimeId, last.Position, position, rotation, onGround)
+			continue
+		}
+		viewer.SendMoveEntity(entity.runtimeId, position, rotation, byte(mode), onGround)
 	}
 }
 
-// GetNBT returns the NBT data of the entity.
-func (entity *Entity) GetNBT() *gonbt.Compound {
-	return entity.nbt
+// getMovement returns the position, rotation and on ground state of the entity at once.
+func (entity *Entity) getMovement() (r3.Vector, data.Rotation, bool) {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.Position, entity.Rotation, entity.OnGround
 }
 
-// SetNBT sets the NBT data of the entity.
-func (entity *Entity) SetNBT(nbt *gonbt.Compound) {
-	entity.nbt = nbt
+// Tick ticks the entity, running its controller and moving it by its motion before broadcasting its movement.
+// Dead entities no longer run their controller, and get despawned once their death animation finished.
+func (entity *Entity) Tick() {
+	if !entity.tickDamage() {
+		return
+	}
+	if !entity.IsDead() {
+		entity.tickAge()
+	}
+	if controller := entity.GetController(); controller != nil && !entity.IsDead() {
+		controller.Tick(entity)
+	}
+	entity.doPhysics()
+	entity.syncRiders()
+	entity.BroadcastUpdatedEntityData()
+	if entity.HasDisplayDataUpdate() {
+		entity.BroadcastUpdatedDisplayData()
+	}
+	if entity.HasEquipmentUpdate {
+		entity.BroadcastUpdatedEquipment()
+	}
+	entity.BroadcastUpdatedAttributes()
+	var forced = entity.HasMovementUpdate
+	entity.HasMovementUpdate = false
+	if mode := entity.getMovementMode(); forced || mode == MovementTeleport || entity.hasMovementChanged() {
+		entity.BroadcastMovement(mode)
+	}
+	entity.recordMovement()
 }
 
-// Tick ticks the entity.
-func (entity *Entity) Tick() {
-	if entity.HasEntityDataUpdate {
-		entity.BroadcastUpdatedEntityData()
-		entity.HasEntityDataUpdate = false
+// IdleTick ticks the entity while it is not simulated, because no viewer is near.
+// Only damage, death and age timers are counted. The entity is not moved, controlled or broadcast.
+func (entity *Entity) IdleTick() {
+	if !entity.tickDamage() {
+		return
 	}
-	if entity.HasMovementUpdate {
-		entity.HasMovementUpdate = false
+	if !entity.IsDead() {
+		entity.tickAge()
 	}
-	entity.BroadcastMovement()
 }
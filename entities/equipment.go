@@ -0,0 +1,157 @@
+package entities
+
+import (
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/blocks"
+)
+
+// EquipmentSlot is a slot of an entity holding an item, such as its main hand or helmet.
+type EquipmentSlot byte
+
+const (
+	SlotMainHand EquipmentSlot = iota
+	SlotOffHand
+	SlotHelmet
+	SlotChestplate
+	SlotLeggings
+	SlotBoots
+)
+
+// equipmentSlotCount is the amount of equipment slots of an entity.
+const equipmentSlotCount = int(SlotBoots) + 1
+
+// IsArmor checks if the slot is an armor slot.
+func (slot EquipmentSlot) IsArmor() bool {
+	return slot >= SlotHelmet && slot <= SlotBoots
+}
+
+// equipment holds the items in the equipment slots of an entity, and which slots changed since they were last sent.
+type equipment struct {
+	items   [equipmentSlotCount]blocks.ItemStack
+	updated [equipmentSlotCount]bool
+}
+
+// SetEquipment sets the item in the given equipment slot and marks it for sending.
+// Items with a count of 0 empty the slot.
+func (entity *Entity) SetEquipment(slot EquipmentSlot, item blocks.ItemStack) {
+	if int(slot) >= equipmentSlotCount {
+		return
+	}
+	if item.Count <= 0 {
+		item = blocks.ItemStack{}
+	}
+	entity.mutex.Lock()
+	entity.equipment.items[slot] = item
+	entity.equipment.updated[slot] = true
+	entity.HasEquipmentUpdate = true
+	entity.mutex.Unlock()
+}
+
+// GetEquipment returns the item in the given equipment slot, and a bool indicating if the slot holds an item.
+func (entity *Entity) GetEquipment(slot EquipmentSlot) (blocks.ItemStack, bool) {
+	if int(slot) >= equipmentSlotCount {
+		return blocks.ItemStack{}, false
+	}
+	entity.mutex.RLock()
+	var item = entity.equipment.items[slot]
+	entity.mutex.RUnlock()
+	return item, item.Count > 0
+}
+
+// GetArmor returns the items in the helmet, chestplate, leggings and boots slots of the entity.
+func (entity *Entity) GetArmor() [4]blocks.ItemStack {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	var armor [4]blocks.ItemStack
+	copy(armor[:], entity.equipment.items[SlotHelmet:])
+	return armor
+}
+
+// SetArmor sets the items in the helmet, chestplate, leggings and boots slots of the entity.
+func (entity *Entity) SetArmor(armor [4]blocks.ItemStack) {
+	for i, item := range armor {
+		entity.SetEquipment(SlotHelmet+EquipmentSlot(i), item)
+	}
+}
+
+// SendEquipment sends the items in all equipment slots of the entity to the viewer.
+func (entity *Entity) SendEquipment(viewer Viewer) {
+	entity.mutex.RLock()
+	var items = entity.equipment.items
+	entity.mutex.RUnlock()
+	viewer.SendMobEquipment(entity.GetRuntimeId(), items[SlotMainHand], SlotMainHand)
+	viewer.SendMobEquipment(entity.GetRuntimeId(), items[SlotOffHand], SlotOffHand)
+	var armor [4]blocks.ItemStack
+	copy(armor[:], items[SlotHelmet:])
+	viewer.SendMobArmorEquipment(entity.GetRuntimeId(), armor)
+}
+
+// BroadcastUpdatedEquipment sends the items in the equipment slots that changed to all viewers.
+// Armor is always sent as a whole if any armor slot changed.
+func (entity *Entity) BroadcastUpdatedEquipment() {
+	entity.mutex.Lock()
+	var items, updated = entity.equipment.items, entity.equipment.updated
+	entity.equipment.updated = [equipmentSlotCount]bool{}
+	entity.HasEquipmentUpdate = false
+	entity.mutex.Unlock()
+
+	var armorUpdated = false
+	for slot := SlotHelmet; slot <= SlotBoots; slot++ {
+		armorUpdated = armorUpdated || updated[slot]
+	}
+	var armor [4]blocks.ItemStack
+	copy(armor[:], items[SlotHelmet:])
+	for _, viewer := range entity.GetViewers() {
+		for _, slot := range [2]EquipmentSlot{SlotMainHand, SlotOffHand} {
+			if updated[slot] {
+				viewer.SendMobEquipment(entity.GetRuntimeId(), items[slot], slot)
+			}
+		}
+		if armorUpdated {
+			viewer.SendMobArmorEquipment(entity.GetRuntimeId(), armor)
+		}
+	}
+}
+
+// equipmentToNBT writes the equipment of the entity to its Mainhand, Offhand and Armor lists.
+func (entity *Entity) equipmentToNBT(nbt *gonbt.Compound) {
+	entity.mutex.RLock()
+	var items = entity.equipment.items
+	entity.mutex.RUnlock()
+	nbt.SetTag(gonbt.NewList("Mainhand", gonbt.TAG_Compound, []gonbt.INamedTag{writeItemStack(items[SlotMainHand])}))
+	nbt.SetTag(gonbt.NewList("Offhand", gonbt.TAG_Compound, []gonbt.INamedTag{writeItemStack(items[SlotOffHand])}))
+	var armor = make([]gonbt.INamedTag, 0, 4)
+	for _, item := range items[SlotHelmet:] {
+		armor = append(armor, writeItemStack(item))
+	}
+	nbt.SetTag(gonbt.NewList("Armor", gonbt.TAG_Compound, armor))
+}
+
+// equipmentFromNBT reads the equipment of the entity from its Mainhand, Offhand and Armor lists.
+func (entity *Entity) equipmentFromNBT(nbt *gonbt.Compound) {
+	for name, slot := range map[string]EquipmentSlot{"Mainhand": SlotMainHand, "Offhand": SlotOffHand, "Armor": SlotHelmet} {
+		var list = nbt.GetList(name, gonbt.TAG_Compound)
+		if list == nil {
+			continue
+		}
+		for i, tag := range list.GetTags() {
+			if compound, ok := tag.(*gonbt.Compound); ok && int(slot)+i < equipmentSlotCount {
+				entity.SetEquipment(slot+EquipmentSlot(i), readItemStack(compound))
+			}
+		}
+	}
+}
+
+// writeItemStack returns the NBT of the item stack.
+func writeItemStack(item blocks.ItemStack) *gonbt.Compound {
+	return gonbt.NewCompound("", map[string]gonbt.INamedTag{
+		"id":     gonbt.NewShort("id", item.ItemId),
+		"Damage": gonbt.NewShort("Damage", item.ItemData),
+		"Count":  gonbt.NewByte("Count", byte(item.Count)),
+	})
+}
+
+// readItemStack reads an item stack from its NBT.
+func readItemStack(compound *gonbt.Compound) blocks.ItemStack {
+	return blocks.ItemStack{ItemId: compound.GetShort("id", 0), ItemData: compound.GetShort("Damage", 0), Count: int(compound.GetByte("Count", 0))}
+}
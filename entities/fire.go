@@ -0,0 +1,107 @@
+package entities
+
+import (
+	"github.com/irmine/worlds"
+	"github.com/irmine/worlds/entities/data"
+)
+
+const (
+	// FireDamageInterval is the interval in ticks at which burning entities take fire damage.
+	FireDamageInterval = 20
+	// FireDamage is the damage burning entities take every fire damage interval.
+	FireDamage = 1
+)
+
+// IsOnFire checks if the entity is burning.
+func (entity *Entity) IsOnFire() bool {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.damage.fireTicks > 0
+}
+
+// GetFireTicks returns the amount of ticks the entity keeps burning.
+func (entity *Entity) GetFireTicks() int {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.damage.fireTicks
+}
+
+// SetOnFire sets the entity on fire for the given duration in ticks.
+// Entities already burning longer than the duration keep burning for their remaining ticks.
+func (entity *Entity) SetOnFire(duration int) {
+	if duration <= 0 {
+		return
+	}
+	entity.mutex.Lock()
+	var ignited = entity.damage.fireTicks == 0
+	if duration > entity.damage.fireTicks {
+		entity.damage.fireTicks = duration
+	}
+	entity.mutex.Unlock()
+	if ignited {
+		entity.SetEntityProperty(data.EntityDataOnFire, true)
+	}
+}
+
+// Extinguish stops the entity from burning.
+func (entity *Entity) Extinguish() {
+	entity.mutex.Lock()
+	var burning = entity.damage.fireTicks > 0
+	entity.damage.fireTicks = 0
+	entity.mutex.Unlock()
+	if burning {
+		entity.SetEntityProperty(data.EntityDataOnFire, false)
+	}
+}
+
+// tickFire burns the entity for a tick, dealing fire damage every fire damage interval.
+// Burning entities in water or rain get extinguished.
+func (entity *Entity) tickFire() {
+	if !entity.IsOnFire() {
+		return
+	}
+	if entity.IsInWater() || entity.IsInRain() {
+		entity.Extinguish()
+		return
+	}
+	entity.mutex.Lock()
+	entity.damage.fireTicks--
+	var ticks = entity.damage.fireTicks
+	entity.mutex.Unlock()
+	if ticks%FireDamageInterval == 0 && entity.doFireDamage() {
+		entity.Damage(worlds.DamageSource{Cause: worlds.DamageFire}, FireDamage)
+	}
+	if ticks == 0 {
+		entity.SetEntityProperty(data.EntityDataOnFire, false)
+	}
+}
+
+// doFireDamage checks if the fireDamage game rule is enabled, which it is if the entity has no level or the level does not have it.
+func (entity *Entity) doFireDamage() bool {
+	var dimension = entity.GetDimension()
+	if dimension == nil || dimension.GetLevel() == nil {
+		return true
+	}
+	return dimension.GetLevel().GetBoolGameRule(worlds.GameRuleFireDamage, true)
+}
+
+// IsInWater checks if the bounding box of the entity intersects water.
+func (entity *Entity) IsInWater() bool {
+	var dimension = entity.GetDimension()
+	if dimension == nil {
+		return false
+	}
+	var blocks, _ = dimension.GetBlocksInAABB(entity.GetBoundingBox())
+	for _, block := range blocks {
+		if block.Id == worlds.WaterBehavior.FlowingId || block.Id == worlds.WaterBehavior.StillId {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInRain checks if it rains on the entity, which is the case if it rains in its dimension and no blocks are above the entity.
+func (entity *Entity) IsInRain() bool {
+	var dimension = entity.GetDimension()
+	return dimension != nil && dimension.IsRainingAt(entity.GetPosition())
+}
@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds"
+)
+
+// MoveFunction gets called before the entity moves, and returns the position the entity moves to instead.
+// The entity does not move if the function returns false. It gets called before the move hook of the dimension of the entity.
+type MoveFunction func(entity *Entity, from, to r3.Vector) (r3.Vector, bool)
+
+// SetMoveFunction sets the function called every time before the entity moves, which can change or cancel the movement.
+func (entity *Entity) SetMoveFunction(function MoveFunction) {
+	entity.mutex.Lock()
+	entity.moveFunction = function
+	entity.mutex.Unlock()
+}
+
+// interceptMove passes the new position of the entity to its move function and the move hook of its dimension.
+// It returns the position the entity moves to, and false if the movement was cancelled.
+// Entities not yet added to their dimension, such as while spawning, are not intercepted.
+func (entity *Entity) interceptMove(dimension *worlds.Dimension, to r3.Vector) (r3.Vector, bool) {
+	if !dimension.HasEntity(entity.runtimeId) {
+		return to, true
+	}
+	entity.mutex.RLock()
+	var function, from = entity.moveFunction, entity.Position
+	entity.mutex.RUnlock()
+	var ok = true
+	if function != nil {
+		if to, ok = function(entity, from, to); !ok {
+			return to, false
+		}
+	}
+	if hook := dimension.GetEntityHooks().OnEntityMove; hook != nil {
+		to, ok = hook(entity.GetOuterEntity(), from, to)
+	}
+	return to, ok
+}
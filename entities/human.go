@@ -0,0 +1,159 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"github.com/irmine/gomine/net/protocol"
+)
+
+// AddFunction sends the entry of the entity to the viewer to add the entity, such as the position it gets spawned at.
+// Entities without add function get added using SendAddEntity.
+type AddFunction func(viewer Viewer, entry protocol.AddEntityEntry)
+
+// SetAddFunction sets the function used to add the entity to viewers.
+func (entity *Entity) SetAddFunction(function AddFunction) {
+	entity.mutex.Lock()
+	entity.addFunction = function
+	entity.mutex.Unlock()
+}
+
+// sendAdd adds the entity to the viewer with the given entry, using the add function of the entity if it has one.
+func (entity *Entity) sendAdd(viewer Viewer, entry protocol.AddEntityEntry) {
+	entity.mutex.RLock()
+	var function = entity.addFunction
+	entity.mutex.RUnlock()
+	if function != nil {
+		function(viewer, entry)
+		return
+	}
+	viewer.SendAddEntity(entry)
+}
+
+// Skin is the skin of a human, with its geometry and cape.
+type Skin struct {
+	Id           string
+	Data         []byte
+	CapeData     []byte
+	GeometryName string
+	GeometryData []byte
+}
+
+// SkinViewer is a viewer that can be sent the skins of humans, usually by adding them to the player list.
+// Viewers not implementing this interface see humans with the default skin.
+type SkinViewer interface {
+	Viewer
+	SendSkin(uuid uuid.UUID, xuid string, name string, skin Skin)
+}
+
+// Human is an entity looking like a player, such as an NPC or the ghost of a player.
+// Humans get added to viewers as players, with a UUID, XUID, name and skin.
+// Like players, humans are not moved by physics.
+type Human struct {
+	*Entity
+	uuid     uuid.UUID
+	xuid     string
+	name     string
+	skin     Skin
+	platform int32
+}
+
+// humanEntry is the entry of a human sent to viewers to add the human as a player.
+type humanEntry struct {
+	protocol.AddEntityEntry
+	human *Human
+}
+
+// GetName returns the name of the human.
+func (entry humanEntry) GetName() string {
+	return entry.human.GetName()
+}
+
+// GetDisplayName returns the name displayed above the head of the human.
+func (entry humanEntry) GetDisplayName() string {
+	return entry.human.GetDisplayName()
+}
+
+// GetPlatform returns the platform of the human.
+func (entry humanEntry) GetPlatform() int32 {
+	return entry.human.GetPlatform()
+}
+
+// NewHuman returns a new human with the given name, UUID, XUID and skin.
+func NewHuman(name string, uuid uuid.UUID, xuid string, skin Skin) *Human {
+	var human = &Human{New(Player), uuid, xuid, name, skin, 0}
+	human.SetAddFunction(human.add)
+	human.SetOuterEntity(human)
+	return human
+}
+
+// add sends the skin of the human to the viewer if possible, and adds the human as a player.
+func (human *Human) add(viewer Viewer, entry protocol.AddEntityEntry) {
+	if skinViewer, ok := viewer.(SkinViewer); ok {
+		skinViewer.SendSkin(human.uuid, human.xuid, human.GetName(), human.GetSkin())
+	}
+	viewer.SendAddPlayer(human.uuid, humanEntry{entry, human})
+}
+
+// GetUUID returns the UUID of the human.
+func (human *Human) GetUUID() uuid.UUID {
+	return human.uuid
+}
+
+// GetXUID returns the XUID of the human, which is empty for humans without Xbox account.
+func (human *Human) GetXUID() string {
+	return human.xuid
+}
+
+// GetName returns the name of the human.
+func (human *Human) GetName() string {
+	human.mutex.RLock()
+	defer human.mutex.RUnlock()
+	return human.name
+}
+
+// SetName sets the name of the human.
+func (human *Human) SetName(name string) {
+	human.mutex.Lock()
+	human.name = name
+	human.mutex.Unlock()
+}
+
+// GetDisplayName returns the name displayed above the head of the human, which is its name tag if set, or its name.
+func (human *Human) GetDisplayName() string {
+	if nameTag := human.GetNameTag(); nameTag != "" {
+		return nameTag
+	}
+	return human.GetName()
+}
+
+// GetPlatform returns the platform of the human.
+func (human *Human) GetPlatform() int32 {
+	human.mutex.RLock()
+	defer human.mutex.RUnlock()
+	return human.platform
+}
+
+// SetPlatform sets the platform of the human, shown to viewers when it gets spawned.
+func (human *Human) SetPlatform(platform int32) {
+	human.mutex.Lock()
+	human.platform = platform
+	human.mutex.Unlock()
+}
+
+// GetSkin returns the skin of the human.
+func (human *Human) GetSkin() Skin {
+	human.mutex.RLock()
+	defer human.mutex.RUnlock()
+	return human.skin
+}
+
+// SetSkin sets the skin of the human, and sends it to all viewers able to see skins.
+func (human *Human) SetSkin(skin Skin) {
+	human.mutex.Lock()
+	human.skin = skin
+	human.mutex.Unlock()
+	for _, viewer := range human.GetViewers() {
+		if skinViewer, ok := viewer.(SkinViewer); ok {
+			skinViewer.SendSkin(human.uuid, human.xuid, human.GetName(), skin)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package entities
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/entities/data"
+)
+
+// MovementHistorySize is the amount of recent movements kept by every entity.
+const MovementHistorySize = 8
+
+// MovementSample is a position and rotation an entity had at the end of a tick.
+type MovementSample struct {
+	Position r3.Vector
+	Rotation data.Rotation
+}
+
+// movementHistory is a ring buffer of the most recent movement samples of an entity.
+type movementHistory struct {
+	samples [MovementHistorySize]MovementSample
+	start   int
+	size    int
+}
+
+// add adds a movement sample to the history, overwriting the oldest sample if full.
+func (history *movementHistory) add(sample MovementSample) {
+	if history.size < MovementHistorySize {
+		history.samples[(history.start+history.size)%MovementHistorySize] = sample
+		history.size++
+		return
+	}
+	history.samples[history.start] = sample
+	history.start = (history.start + 1) % MovementHistorySize
+}
+
+// get returns the sample at the given index, where 0 is the oldest sample.
+func (history *movementHistory) get(index int) MovementSample {
+	return history.samples[(history.start+index)%MovementHistorySize]
+}
+
+// interpolatedSpawn is an entity spawned at an earlier position than its current one.
+type interpolatedSpawn struct {
+	*Entity
+	position r3.Vector
+}
+
+// GetPosition returns the position the entity gets spawned at.
+func (spawn interpolatedSpawn) GetPosition() r3.Vector {
+	return spawn.position
+}
+
+// GetMovementHistory returns the recent movement samples of the entity, oldest first.
+func (entity *Entity) GetMovementHistory() []MovementSample {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	var samples = make([]MovementSample, entity.movementHistory.size)
+	for i := range samples {
+		samples[i] = entity.movementHistory.get(i)
+	}
+	return samples
+}
+
+// recordMovement records the current position and rotation of the entity if it moved since the last sample.
+func (entity *Entity) recordMovement() {
+	entity.mutex.Lock()
+	var sample = MovementSample{entity.Position, entity.Rotation}
+	var history = &entity.movementHistory
+	if history.size == 0 || history.get(history.size-1) != sample {
+		history.add(sample)
+	}
+	entity.mutex.Unlock()
+}
+
+// IsMoving checks if the entity has motion, or moved during the last tick.
+func (entity *Entity) IsMoving() bool {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	if entity.Motion != (r3.Vector{}) {
+		return true
+	}
+	var history = &entity.movementHistory
+	return history.size >= 2 && history.get(history.size-1) != history.get(history.size-2)
+}
+
+// spawnInterpolated spawns the entity to the viewer at its previous position, and then moves it to its current position.
+// The viewer interpolates the movement, so the entity does not appear to jump when spawned mid-movement.
+func (entity *Entity) spawnInterpolated(viewer Viewer) {
+	var samples = entity.GetMovementHistory()
+	if len(samples) < 2 {
+		entity.sendAdd(viewer, entity)
+		return
+	}
+	entity.sendAdd(viewer, interpolatedSpawn{entity, samples[len(samples)-2].Position})
+	entity.SendMovement(viewer, MovementNormal)
+}
@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/entities/data"
+	"math"
+)
+
+const (
+	// DefaultKnockback is the strength of the knockback of a melee attack.
+	DefaultKnockback = 0.4
+	// MaxKnockbackLift is the maximum upwards motion knockback gives an entity on the ground.
+	MaxKnockbackLift = 0.4
+)
+
+// Knockback pushes the entity in the horizontal direction with the given strength, and sends its new motion to all viewers.
+// Half of the horizontal motion of the entity is kept, and entities on the ground get lifted up by the strength.
+// The strength is reduced by the knockback resistance of the entity.
+func (entity *Entity) Knockback(direction r3.Vector, strength float64) {
+	if resistance := entity.attributeMap.GetAttribute(data.AttributeKnockBackResistance); resistance != nil {
+		strength *= 1 - math.Min(math.Max(float64(resistance.Value), 0), 1)
+	}
+	if strength <= 0 {
+		return
+	}
+	direction.Y = 0
+	entity.mutex.Lock()
+	entity.Motion.X /= 2
+	entity.Motion.Z /= 2
+	if direction.Norm2() != 0 {
+		var push = direction.Normalize().Mul(strength)
+		entity.Motion.X += push.X
+		entity.Motion.Z += push.Z
+	}
+	if entity.OnGround {
+		entity.Motion.Y = math.Min(entity.Motion.Y/2+strength, MaxKnockbackLift)
+	}
+	entity.mutex.Unlock()
+	entity.BroadcastMotion()
+}
+
+// SendMotion sends the motion of the entity to the viewer.
+func (entity *Entity) SendMotion(viewer Viewer) {
+	viewer.SendSetEntityMotion(entity.runtimeId, entity.GetMotion())
+}
+
+// BroadcastMotion sends the motion of the entity to all viewers.
+// The motion of entities gets simulated by viewers, so it only needs to be sent when it changes suddenly, such as by knockback.
+func (entity *Entity) BroadcastMotion() {
+	var motion = entity.GetMotion()
+	for _, viewer := range entity.GetViewers() {
+		viewer.SendSetEntityMotion(entity.runtimeId, motion)
+	}
+}
@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds"
+	"github.com/irmine/worlds/chunks"
+)
+
+const (
+	// LightningLifetime is the amount of ticks a lightning bolt exists before it despawns.
+	LightningLifetime = 20
+	// LightningRange is the distance within which entities get struck by a lightning bolt.
+	LightningRange = 3
+	// LightningDamage is the damage entities struck by a lightning bolt take.
+	LightningDamage = 5
+	// LightningFireTicks is the amount of ticks entities struck by a lightning bolt burn.
+	LightningFireTicks = 160
+)
+
+// struckEntity is an entity that can be struck by lightning.
+type struckEntity interface {
+	Damage(source worlds.DamageSource, amount float32) bool
+	SetOnFire(duration int)
+}
+
+// Lightning is a lightning bolt entity striking the entities around it on its first tick, after which it despawns once its lifetime is over.
+type Lightning struct {
+	*Entity
+	lifetime int
+}
+
+// NewLightning returns a new lightning bolt.
+func NewLightning() *Lightning {
+	var bolt = &Lightning{New(LightningBolt), 0}
+	bolt.SetOuterEntity(bolt)
+	return bolt
+}
+
+// LightningBoltFunction returns a new lightning bolt.
+// It can be used as the lightning function of a dimension, after which lightning striking in the dimension adds lightning bolts.
+func LightningBoltFunction(position r3.Vector) chunks.ChunkEntity {
+	return NewLightning()
+}
+
+// Tick ticks the lightning bolt, striking the entities around it on its first tick.
+func (bolt *Lightning) Tick() {
+	bolt.Entity.Tick()
+	bolt.IdleTick()
+}
+
+// IdleTick counts the lifetime of the lightning bolt, closing it once its lifetime is over.
+func (bolt *Lightning) IdleTick() {
+	if bolt.IsClosed() {
+		return
+	}
+	bolt.mutex.Lock()
+	bolt.lifetime++
+	var lifetime = bolt.lifetime
+	bolt.mutex.Unlock()
+	if lifetime == 1 {
+		bolt.strike()
+	}
+	if lifetime >= LightningLifetime {
+		bolt.Close()
+	}
+}
+
+// strike damages and ignites all entities within the lightning range of the lightning bolt.
+func (bolt *Lightning) strike() {
+	var dimension = bolt.GetDimension()
+	if dimension == nil {
+		return
+	}
+	for _, entity := range dimension.GetNearbyEntities(bolt.GetPosition(), LightningRange) {
+		if entity.GetRuntimeId() == bolt.GetRuntimeId() {
+			continue
+		}
+		if struck, ok := entity.(struckEntity); ok {
+			struck.SetOnFire(LightningFireTicks)
+			struck.Damage(worlds.DamageSource{Cause: worlds.DamageLightning, Attacker: bolt}, LightningDamage)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package entities
+
+import (
+	"github.com/irmine/worlds"
+	"github.com/irmine/worlds/blocks"
+	"math/rand"
+	"sync"
+)
+
+// LootTable holds the items and experience dropped by an entity type when an entity of it dies.
+type LootTable struct {
+	// Drops are the items dropped on death.
+	Drops []blocks.Drop
+	// MinExperience and MaxExperience are the bounds of the experience dropped on death.
+	MinExperience int
+	MaxExperience int
+}
+
+// LootFunction gets called when the entity dies, with the items and experience it is about to drop.
+// It returns the items and experience actually dropped, which allows overriding the loot per kill.
+// It gets called before the loot hook of the dimension of the entity, and not at all if the game rules prevent the entity from dropping loot.
+type LootFunction func(entity *Entity, source worlds.DamageSource, drops []blocks.ItemStack, experience int) ([]blocks.ItemStack, int)
+
+// drop returns a drop of the item with the given ID.
+func drop(itemId int16, min, max int) blocks.Drop {
+	return blocks.Drop{ItemId: itemId, MinCount: min, MaxCount: max}
+}
+
+var lootMutex sync.RWMutex
+
+// lootTables are the loot tables of entity types that drop something on death, keyed by entity type.
+var lootTables = map[EntityType]LootTable{
+	Chicken:  {[]blocks.Drop{drop(288, 0, 2), drop(365, 1, 1)}, 1, 3},
+	Pig:      {[]blocks.Drop{drop(319, 1, 3)}, 1, 3},
+	Sheep:    {[]blocks.Drop{drop(35, 1, 1), drop(423, 1, 2)}, 1, 3},
+	Zombie:   {[]blocks.Drop{drop(367, 0, 2)}, 5, 5},
+	Creeper:  {[]blocks.Drop{drop(289, 0, 2)}, 5, 5},
+	Skeleton: {[]blocks.Drop{drop(262, 0, 2), drop(352, 0, 2)}, 5, 5},
+	Spider:   {[]blocks.Drop{drop(287, 0, 2)}, 5, 5},
+}
+
+// GetLootTable returns the loot table of the entity type, and a bool indicating if it had one.
+func GetLootTable(entityType EntityType) (LootTable, bool) {
+	lootMutex.RLock()
+	defer lootMutex.RUnlock()
+	var table, ok = lootTables[entityType]
+	return table, ok
+}
+
+// SetLootTable sets the loot table of the entity type.
+// Custom entity types must be registered this way in order to drop anything on death.
+func SetLootTable(entityType EntityType, table LootTable) {
+	lootMutex.Lock()
+	lootTables[entityType] = table
+	lootMutex.Unlock()
+}
+
+// GetLoot returns the items and experience dropped by the table, with counts picked randomly between their bounds.
+func (table LootTable) GetLoot() ([]blocks.ItemStack, int) {
+	var stacks []blocks.ItemStack
+	for _, drop := range table.Drops {
+		if count := randomBetween(drop.MinCount, drop.MaxCount); count > 0 {
+			stacks = append(stacks, blocks.ItemStack{ItemId: drop.ItemId, ItemData: drop.ItemData, Count: count})
+		}
+	}
+	return stacks, randomBetween(table.MinExperience, table.MaxExperience)
+}
+
+// randomBetween returns a random number between min and max, both inclusive.
+func randomBetween(min, max int) int {
+	if max > min {
+		return min + rand.Intn(max-min+1)
+	}
+	return min
+}
+
+// SetLootFunction sets the function called when the entity dies, overriding the loot it drops.
+func (entity *Entity) SetLootFunction(function LootFunction) {
+	entity.mutex.Lock()
+	entity.damage.lootFunction = function
+	entity.mutex.Unlock()
+}
+
+// getLootFunction returns the loot function of the entity.
+func (entity *Entity) getLootFunction() LootFunction {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.damage.lootFunction
+}
+
+// dropLoot drops the loot of the entity at its position after it died from the source.
+// Loot is only dropped if the doMobLoot game rule allows it for mobs, or the doEntityDrops game rule for other entities.
+// The loot function of the entity and the loot hook of its dimension may override the loot of the loot table.
+func (entity *Entity) dropLoot(source worlds.DamageSource) {
+	var dimension = entity.GetDimension()
+	if dimension == nil {
+		return
+	}
+	var entityType = EntityType(entity.GetEntityType())
+	if !dimension.CanDropEntityLoot(entityType.IsMob()) {
+		return
+	}
+	var stacks []blocks.ItemStack
+	var experience int
+	// Babies never drop loot of their own.
+	if table, ok := GetLootTable(entityType); ok && !entity.IsBaby() {
+		stacks, experience = table.GetLoot()
+	}
+	if function := entity.getLootFunction(); function != nil {
+		stacks, experience = function(entity, source, stacks, experience)
+	}
+	if hook := dimension.GetEntityHooks().OnEntityLoot; hook != nil {
+		stacks, experience = hook(entity.GetOuterEntity(), source, stacks, experience)
+	}
+	var position = entity.GetPosition()
+	dimension.DropItemStacks(stacks, position)
+	dimension.SpawnXp(position, experience)
+}
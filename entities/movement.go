@@ -0,0 +1,139 @@
+package entities
+
+import (
+	"errors"
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds"
+	"github.com/irmine/worlds/entities/data"
+	"math"
+)
+
+// MovementMode is the mode viewers apply a movement of an entity in.
+type MovementMode byte
+
+const (
+	// MovementNormal movements get interpolated by viewers, so the entity glides to its new position.
+	MovementNormal MovementMode = iota
+	// MovementReset movements reset the position of the entity at the viewer.
+	MovementReset
+	// MovementTeleport movements make the entity snap to its new position.
+	MovementTeleport
+	// MovementPitch movements only change the rotation of the entity.
+	MovementPitch
+)
+
+const (
+	// TeleportDistance is the distance an entity must move in a single tick for the movement to be sent as a teleport.
+	TeleportDistance = 8
+	// PositionEpsilon is the distance an entity must move from its last broadcast position for its movement to be broadcast.
+	PositionEpsilon = 0.01
+	// RotationEpsilon is the amount of degrees an entity must rotate from its last broadcast rotation for its movement to be broadcast.
+	RotationEpsilon = 1
+)
+
+// DeltaMoveViewer is a viewer that can be sent entity movements as a delta from the previous position,
+// saving bandwidth by leaving out unchanged values.
+// Viewers not implementing this interface get sent the full position every movement.
+type DeltaMoveViewer interface {
+	Viewer
+	SendMoveEntityDelta(runtimeId uint64, from, to r3.Vector, rotation data.Rotation, onGround bool)
+}
+
+// ClosedTeleport gets returned when a closed entity is attempted to be teleported.
+var ClosedTeleport = errors.New("tried to teleport closed entity")
+
+// Teleport moves the entity to the position in the dimension, making viewers snap it there instead of gliding.
+// If the dimension is nil or the dimension of the entity, the entity is moved using SetPosition.
+// Otherwise the entity is despawned from its viewers and removed from its old dimension, and added to the new dimension
+// once the chunk at the position is loaded, getting spawned to the viewers there with a new runtime ID.
+// If the new dimension cannot accept the entity, it is returned to its old position in its old dimension,
+// and worlds.UnloadedChunk is returned if that is already known when teleporting.
+// Teleported entities stop riding their vehicle, and riders of entities teleported to another dimension get dismounted.
+// All attributes of entities teleported to another dimension are resent, as viewers reset them on dimension changes.
+func (entity *Entity) Teleport(dimension *worlds.Dimension, position r3.Vector) error {
+	if entity.IsClosed() {
+		return ClosedTeleport
+	}
+	entity.Dismount()
+	var old = entity.GetDimension()
+	if dimension == nil || dimension == old {
+		if err := entity.SetPosition(position); err != nil {
+			return err
+		}
+		entity.mutex.Lock()
+		entity.teleported = true
+		entity.mutex.Unlock()
+		entity.HasMovementUpdate = true
+		return nil
+	}
+
+	entity.DismountRiders()
+	entity.DespawnFromAll()
+	entity.ResendAttributes()
+	var oldPosition = entity.GetPosition()
+	if old != nil {
+		old.DetachEntity(entity.GetRuntimeId())
+	}
+	entity.resetMovement(position)
+	// The entity is returned to its old dimension if the new dimension does not accept it, rather than getting lost.
+	var rollback = func() {
+		entity.resetMovement(oldPosition)
+		if old != nil {
+			old.AddEntity(entity.GetOuterEntity(), oldPosition)
+		}
+	}
+	var err = dimension.TransferEntity(entity.GetOuterEntity(), position, func(added bool) {
+		if !added {
+			rollback()
+		}
+	})
+	if err != nil {
+		rollback()
+	}
+	return err
+}
+
+// resetMovement clears the dimension and movement state of the entity and moves it to the given position.
+// The entity is added to a dimension as if it was new after this, so it must not glide from its old position.
+func (entity *Entity) resetMovement(position r3.Vector) {
+	entity.mutex.Lock()
+	entity.Dimension = nil
+	entity.Position = position
+	entity.movementHistory = movementHistory{}
+	entity.broadcasted = false
+	entity.teleported = false
+	entity.mutex.Unlock()
+}
+
+// getMovementMode returns the mode the movement of the entity during the last tick should be sent in.
+// Movements are sent as teleports if the entity got teleported, or moved further than the teleport distance.
+func (entity *Entity) getMovementMode() MovementMode {
+	entity.mutex.Lock()
+	var teleported = entity.teleported
+	entity.teleported = false
+	var history = &entity.movementHistory
+	if history.size != 0 && history.get(history.size-1).Position.Distance(entity.Position) > TeleportDistance {
+		teleported = true
+	}
+	entity.mutex.Unlock()
+	if teleported {
+		return MovementTeleport
+	}
+	return MovementNormal
+}
+
+// hasMovementChanged checks if the position or rotation of the entity changed beyond the epsilons since its movement was last broadcast.
+func (entity *Entity) hasMovementChanged() bool {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	if !entity.broadcasted {
+		return true
+	}
+	var last = entity.lastBroadcast
+	if last.Position.Distance(entity.Position) > PositionEpsilon {
+		return true
+	}
+	return math.Abs(last.Rotation.Yaw-entity.Rotation.Yaw) > RotationEpsilon ||
+		math.Abs(last.Rotation.HeadYaw-entity.Rotation.HeadYaw) > RotationEpsilon ||
+		math.Abs(last.Rotation.Pitch-entity.Rotation.Pitch) > RotationEpsilon
+}
@@ -0,0 +1,126 @@
+package entities
+
+import (
+	"encoding/json"
+	"github.com/irmine/worlds/entities/data"
+)
+
+// TextComponent is a component of raw text, being either literal text or a translation localized by viewers.
+type TextComponent struct {
+	// Text is literal text, shown as it is.
+	Text string `json:"text,omitempty"`
+	// Translate is the key of a translation, shown in the language of the viewer.
+	Translate string `json:"translate,omitempty"`
+	// With are the parameters filled into the translation.
+	With []string `json:"with,omitempty"`
+}
+
+// RawText is text made up out of text components, sent to viewers as raw text JSON.
+type RawText []TextComponent
+
+// Text returns a text component of literal text.
+func Text(text string) TextComponent {
+	return TextComponent{Text: text}
+}
+
+// Translation returns a text component of the translation with the given key, filled with the parameters.
+func Translation(key string, parameters ...string) TextComponent {
+	return TextComponent{Translate: key, With: parameters}
+}
+
+// String returns the raw text JSON of the raw text, such as {"rawtext":[{"text":"Steve"}]}.
+func (text RawText) String() string {
+	if text == nil {
+		text = RawText{}
+	}
+	var encoded, _ = json.Marshal(struct {
+		RawText []TextComponent `json:"rawtext"`
+	}{text})
+	return string(encoded)
+}
+
+// GetNameTag returns the name tag of this entity.
+// Name tags set as raw text are returned as raw text JSON.
+func (entity *Entity) GetNameTag() string {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.NameTag
+}
+
+// SetNameTag sets the name tag of this entity, which gets sent to viewers.
+// Name tags are only shown if the name tag is set to be visible.
+func (entity *Entity) SetNameTag(nameTag string) {
+	entity.mutex.Lock()
+	entity.NameTag = nameTag
+	entity.setEntityDataFlag(data.EntityDataNameTag, data.EntityDataString, nameTag)
+	entity.mutex.Unlock()
+}
+
+// SetRawNameTag sets the name tag of this entity to raw text, allowing it to contain translations localized by viewers.
+func (entity *Entity) SetRawNameTag(text RawText) {
+	entity.SetNameTag(text.String())
+}
+
+// SetTranslatedNameTag sets the name tag of this entity to the translation with the given key, filled with the parameters.
+func (entity *Entity) SetTranslatedNameTag(key string, parameters ...string) {
+	entity.SetRawNameTag(RawText{Translation(key, parameters...)})
+}
+
+// GetScoreTag returns the score tag of this entity, which is shown below its name tag.
+func (entity *Entity) GetScoreTag() string {
+	var scoreTag, _ = entity.GetDataFlag(data.EntityDataScoreTag)[1].(string)
+	return scoreTag
+}
+
+// SetScoreTag sets the score tag of this entity, which is shown below its name tag.
+// The score tag is removed if empty.
+func (entity *Entity) SetScoreTag(scoreTag string) {
+	if scoreTag == "" {
+		entity.RemoveEntityDataFlag(data.EntityDataScoreTag)
+		return
+	}
+	entity.SetEntityDataFlag(data.EntityDataScoreTag, data.EntityDataString, scoreTag)
+}
+
+// SetRawScoreTag sets the score tag of this entity to raw text, allowing it to contain translations localized by viewers.
+func (entity *Entity) SetRawScoreTag(text RawText) {
+	entity.SetScoreTag(text.String())
+}
+
+// IsNameTagVisible checks if the name tag of this entity is shown when viewers look at the entity.
+func (entity *Entity) IsNameTagVisible() bool {
+	return entity.GetEntityProperty(data.EntityDataCanShowNametag)
+}
+
+// SetNameTagVisible sets if the name tag of this entity is shown when viewers look at the entity.
+func (entity *Entity) SetNameTagVisible(value bool) {
+	entity.SetEntityProperty(data.EntityDataCanShowNametag, value)
+}
+
+// IsNameTagAlwaysVisible checks if the name tag of this entity is shown even when viewers are not looking at the entity.
+func (entity *Entity) IsNameTagAlwaysVisible() bool {
+	return entity.GetEntityProperty(data.EntityDataAlwaysShowNametag)
+}
+
+// SetNameTagAlwaysVisible sets if the name tag of this entity is shown even when viewers are not looking at the entity.
+// Name tags always visible are visible when looking at the entity too.
+func (entity *Entity) SetNameTagAlwaysVisible(value bool) {
+	if value {
+		entity.SetNameTagVisible(true)
+	}
+	entity.SetEntityProperty(data.EntityDataAlwaysShowNametag, value)
+	entity.SetEntityDataFlag(data.EntityDataAlwaysShowNameTag, data.EntityDataByte, boolByte(value))
+}
+
+// IsNameTagSeeThrough checks if the name tag of this entity is rendered through blocks.
+func (entity *Entity) IsNameTagSeeThrough() bool {
+	var value, _ = entity.GetDisplayData(DisplayNameTagSeeThrough)
+	var seeThrough, _ = value.(bool)
+	return seeThrough
+}
+
+// SetNameTagSeeThrough sets if the name tag of this entity is rendered through blocks.
+// It is sent as display data, as Bedrock has no metadata for it.
+func (entity *Entity) SetNameTagSeeThrough(value bool) {
+	entity.SetDisplayData(DisplayNameTagSeeThrough, value)
+}
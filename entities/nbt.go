@@ -0,0 +1,221 @@
+package entities
+
+import (
+	"errors"
+	"github.com/golang/geo/r3"
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/chunks"
+	"github.com/irmine/worlds/entities/data"
+)
+
+// GetNBT returns the NBT of the entity, with its entity type, position, motion, rotation, health, attributes, equipment, age, custom data and name tag written into it.
+// Tags of the entity NBT not handled by the entity are kept, so they get saved again.
+func (entity *Entity) GetNBT() *gonbt.Compound {
+	var nbt = entity.nbt
+	var position, rotation, onGround = entity.getMovement()
+	nbt.SetTag(gonbt.NewInt("id", int32(entity.entityType)))
+	nbt.SetTag(newVectorList("Pos", position))
+	nbt.SetTag(newVectorList("Motion", entity.GetMotion()))
+	nbt.SetTag(gonbt.NewList("Rotation", gonbt.TAG_Float, []gonbt.INamedTag{
+		gonbt.NewFloat("", float32(rotation.Yaw)), gonbt.NewFloat("", float32(rotation.Pitch)),
+	}))
+	nbt.SetTag(gonbt.NewByte("OnGround", boolByte(onGround)))
+	if entity.attributeMap.Exists(data.AttributeHealth) {
+		nbt.SetTag(gonbt.NewFloat("Health", entity.GetHealth()))
+	}
+
+	var attributes = make([]gonbt.INamedTag, 0, len(entity.attributeMap))
+	for _, attribute := range entity.attributeMap {
+		attributes = append(attributes, gonbt.NewCompound("", map[string]gonbt.INamedTag{
+			"Name":    gonbt.NewString("Name", string(attribute.GetName())),
+			"Base":    gonbt.NewFloat("Base", attribute.DefaultValue),
+			"Current": gonbt.NewFloat("Current", attribute.Value),
+			"Min":     gonbt.NewFloat("Min", attribute.MinValue),
+			"Max":     gonbt.NewFloat("Max", attribute.MaxValue),
+		}))
+	}
+	nbt.SetTag(gonbt.NewList("Attributes", gonbt.TAG_Compound, attributes))
+
+	entity.equipmentToNBT(nbt)
+	entity.ageToNBT(nbt)
+	entity.customDataToNBT(nbt)
+
+	if nameTag := entity.GetNameTag(); nameTag != "" {
+		nbt.SetTag(gonbt.NewString("CustomName", nameTag))
+	} else {
+		nbt.RemoveTag("CustomName")
+	}
+	nbt.SetTag(gonbt.NewByte("CustomNameVisible", boolByte(entity.IsNameTagAlwaysVisible())))
+	return nbt
+}
+
+// SetNBT sets the NBT of the entity, and loads its motion, rotation, health, attributes, equipment, age, custom data and name tag from it.
+// The position in the NBT is only loaded if the entity is not yet in a dimension, as entities in a dimension must be moved using SetPosition.
+func (entity *Entity) SetNBT(nbt *gonbt.Compound) {
+	if nbt == nil {
+		nbt = gonbt.NewCompound("", make(map[string]gonbt.INamedTag))
+	}
+	entity.mutex.Lock()
+	entity.nbt = nbt
+	if position, ok := readVectorList(nbt, "Pos"); ok && entity.Dimension == nil {
+		entity.Position = position
+	}
+	if motion, ok := readVectorList(nbt, "Motion"); ok {
+		entity.Motion = motion
+	}
+	if rotation := readFloatList(nbt, "Rotation"); len(rotation) == 2 {
+		entity.Rotation = data.Rotation{Yaw: rotation[0], HeadYaw: rotation[0], Pitch: rotation[1]}
+	}
+	entity.OnGround = nbt.GetByte("OnGround", boolByte(entity.OnGround)) != 0
+	entity.mutex.Unlock()
+
+	if attributes := nbt.GetList("Attributes", gonbt.TAG_Compound); attributes != nil {
+		for _, tag := range attributes.GetTags() {
+			if compound, ok := tag.(*gonbt.Compound); ok {
+				entity.loadAttribute(compound)
+			}
+		}
+	}
+	if nbt.HasTag("Health") && entity.attributeMap.Exists(data.AttributeHealth) {
+		entity.SetHealth(nbt.GetFloat("Health", entity.GetHealth()))
+	}
+	entity.equipmentFromNBT(nbt)
+	entity.ageFromNBT(nbt)
+	entity.customDataFromNBT(nbt)
+	entity.SetNameTag(nbt.GetString("CustomName", entity.GetNameTag()))
+	entity.SetNameTagAlwaysVisible(nbt.GetByte("CustomNameVisible", boolByte(entity.IsNameTagAlwaysVisible())) != 0)
+}
+
+// loadAttribute loads an attribute of the entity from its compound, adding the attribute if the entity did not have it yet.
+func (entity *Entity) loadAttribute(compound *gonbt.Compound) {
+	var name = data.AttributeName(compound.GetString("Name", ""))
+	if name == "" {
+		return
+	}
+	if !entity.attributeMap.Exists(name) {
+		entity.attributeMap.SetAttribute(data.NewAttribute(name, 0, 0))
+	}
+	var attribute = entity.attributeMap.GetAttribute(name)
+	attribute.MinValue = compound.GetFloat("Min", attribute.MinValue)
+	attribute.MaxValue = compound.GetFloat("Max", attribute.MaxValue)
+	attribute.DefaultValue = compound.GetFloat("Base", attribute.DefaultValue)
+	attribute.Value = compound.GetFloat("Current", attribute.Value)
+}
+
+// newVectorList returns a new list of doubles holding the X, Y and Z of the vector.
+func newVectorList(name string, vector r3.Vector) *gonbt.List {
+	return gonbt.NewList(name, gonbt.TAG_Double, []gonbt.INamedTag{
+		gonbt.NewDouble("", vector.X), gonbt.NewDouble("", vector.Y), gonbt.NewDouble("", vector.Z),
+	})
+}
+
+// readVectorList reads a vector from a list of three doubles or floats, and returns a bool indicating if the list was valid.
+func readVectorList(nbt *gonbt.Compound, name string) (r3.Vector, bool) {
+	var values = readFloatList(nbt, name)
+	if len(values) != 3 {
+		return r3.Vector{}, false
+	}
+	return r3.Vector{X: values[0], Y: values[1], Z: values[2]}, true
+}
+
+// readFloatList reads all values of a list of doubles or floats. Values of other types are skipped.
+func readFloatList(nbt *gonbt.Compound, name string) []float64 {
+	var list = nbt.GetList(name, gonbt.TAG_Double)
+	if list == nil {
+		list = nbt.GetList(name, gonbt.TAG_Float)
+	}
+	if list == nil {
+		return nil
+	}
+	var values = make([]float64, 0, len(list.GetTags()))
+	for _, tag := range list.GetTags() {
+		switch value := tag.Interface().(type) {
+		case float64:
+			values = append(values, value)
+		case float32:
+			values = append(values, float64(value))
+		}
+	}
+	return values
+}
+
+// boolByte returns 1 if the bool is true, and 0 otherwise.
+func boolByte(value bool) byte {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// Manager manages entity types and has utility functions for registering those.
+type Manager map[EntityType]func() chunks.ChunkEntity
+
+// UnregisteredEntity gets returned if entity NBT has an entity type that is not registered.
+var UnregisteredEntity = errors.New("entity type is not registered")
+
+// NewManager returns a new entity manager.
+func NewManager() Manager {
+	return Manager{}
+}
+
+// Register registers a new entity function for the given entity type.
+// Register overwrites any entity that might have been previously registered on the entity type.
+func (manager Manager) Register(entityType EntityType, entityFunc func() chunks.ChunkEntity) {
+	manager[entityType] = entityFunc
+}
+
+// Deregister deregisters the entity function of the given entity type.
+func (manager Manager) Deregister(entityType EntityType) {
+	delete(manager, entityType)
+}
+
+// IsRegistered checks if an entity function with the given entity type is registered.
+func (manager Manager) IsRegistered(entityType EntityType) bool {
+	var _, ok = manager[entityType]
+	return ok
+}
+
+// FromNBT creates an entity from its NBT, using the entity type in the id tag of the NBT.
+// Returns UnregisteredEntity if no entity with the entity type was registered.
+func (manager Manager) FromNBT(nbt *gonbt.Compound) (chunks.ChunkEntity, error) {
+	return manager.Create(uint32(nbt.GetInt("id", 0)), nbt)
+}
+
+// Create creates an entity of the given entity type, and loads it from the NBT.
+// Only the lowest byte of the entity type is used, as the other bytes hold flags of the entity type.
+// Create can be used as the entity factory of chunks. Returns UnregisteredEntity if no entity with the entity type was registered.
+func (manager Manager) Create(entityType uint32, nbt *gonbt.Compound) (chunks.ChunkEntity, error) {
+	var entityFunc, ok = manager[EntityType(entityType&0xff)]
+	if !ok {
+		return nil, UnregisteredEntity
+	}
+	var entity = entityFunc()
+	entity.SetNBT(nbt)
+	return entity, nil
+}
+
+// RegisterDefaults registers base entities for all entity types of this package, other than players.
+func RegisterDefaults(manager Manager) {
+	for _, types := range [][2]EntityType{{Chicken, Parrot}, {Zombie, Vindicator}, {ArmorStand, FireworksRocket}, {ShulkerBullet, Vex}} {
+		for entityType := types[0]; entityType <= types[1]; entityType++ {
+			if entityType == Player || entityType == Unused {
+				continue
+			}
+			var t = entityType
+			manager.Register(t, func() chunks.ChunkEntity {
+				return newDefault(t)
+			})
+		}
+	}
+}
+
+// newDefault returns a new base entity of the entity type, being an experience orb or lightning bolt for those entity types.
+func newDefault(entityType EntityType) chunks.ChunkEntity {
+	switch entityType {
+	case XpOrb:
+		return NewExperienceOrb(1)
+	case LightningBolt:
+		return NewLightning()
+	}
+	return New(entityType)
+}
@@ -0,0 +1,162 @@
+package entities
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/utils"
+	"sync"
+)
+
+// Physics are the physical properties of a type of entity, used to move entities by their motion every tick.
+type Physics struct {
+	// Gravity is the downward motion added to the entity every tick.
+	Gravity float64
+	// Drag is the fraction of motion the entity loses every tick.
+	Drag float64
+	// Width and Height are the size of the bounding box of the entity.
+	Width, Height float64
+}
+
+// GroundFriction is the fraction of horizontal motion entities keep every tick while on the ground.
+const GroundFriction = 0.6
+
+var physicsMutex sync.RWMutex
+
+// defaultPhysics are the physics of entity types without physics registered, which are the physics of most mobs.
+var defaultPhysics = Physics{0.08, 0.02, 0.6, 1.8}
+
+// physics are the physics of entity types, keyed by entity type.
+var physics = map[EntityType]Physics{
+	Item:         {0.04, 0.02, 0.25, 0.25},
+	Tnt:          {0.04, 0.02, 0.98, 0.98},
+	FallingBlock: {0.04, 0.02, 0.98, 0.98},
+	XpOrb:        {0.04, 0.02, 0.25, 0.25},
+	XpBottle:     {0.07, 0.01, 0.25, 0.25},
+	Arrow:        {0.05, 0.01, 0.5, 0.5},
+	Snowball:     {0.03, 0.01, 0.25, 0.25},
+	Egg:          {0.03, 0.01, 0.25, 0.25},
+	Chicken:      {0.08, 0.02, 0.4, 0.7},
+	Pig:          {0.08, 0.02, 0.9, 0.9},
+	Sheep:        {0.08, 0.02, 0.9, 1.3},
+	Wolf:         {0.08, 0.02, 0.6, 0.85},
+	Villager:     {0.08, 0.02, 0.6, 1.95},
+	Mooshroom:    {0.08, 0.02, 0.9, 1.3},
+	Squid:        {0.08, 0.02, 0.95, 0.95},
+	Rabbit:       {0.08, 0.02, 0.4, 0.5},
+	Bat:          {0.08, 0.02, 0.5, 0.9},
+	IronGolem:    {0.08, 0.02, 1.4, 2.9},
+	SnowGolem:    {0.08, 0.02, 0.7, 1.9},
+	Ocelot:       {0.08, 0.02, 0.6, 0.7},
+	Horse:        {0.08, 0.02, 1.4, 1.6},
+	PolarBear:    {0.08, 0.02, 1.3, 1.4},
+	Llama:        {0.08, 0.02, 0.9, 1.87},
+	Parrot:       {0.08, 0.02, 0.5, 0.9},
+	Creeper:      {0.08, 0.02, 0.6, 1.7},
+	Spider:       {0.08, 0.02, 1.4, 0.9},
+	CaveSpider:   {0.08, 0.02, 0.7, 0.5},
+	Slime:        {0.08, 0.02, 2.08, 2.08},
+	MagmaCube:    {0.08, 0.02, 2.08, 2.08},
+	Enderman:     {0.08, 0.02, 0.6, 2.9},
+	SilverFish:   {0.08, 0.02, 0.4, 0.3},
+	Ghast:        {0.08, 0.02, 4, 4},
+	Witch:        {0.08, 0.02, 0.6, 1.95},
+	Minecart:     {0.04, 0.05, 0.98, 0.7},
+	Boat:         {0.04, 0.05, 1.4, 0.455},
+}
+
+// GetPhysics returns the physics of the given entity type.
+func GetPhysics(entityType EntityType) Physics {
+	physicsMutex.RLock()
+	defer physicsMutex.RUnlock()
+	if typePhysics, ok := physics[entityType]; ok {
+		return typePhysics
+	}
+	return defaultPhysics
+}
+
+// SetPhysics sets the physics of the given entity type.
+// Custom entities must be registered this way in order to have physics other than those of most mobs.
+func SetPhysics(entityType EntityType, typePhysics Physics) {
+	physicsMutex.Lock()
+	physics[entityType] = typePhysics
+	physicsMutex.Unlock()
+}
+
+// GetBoundingBox returns the bounding box of the entity at its current position.
+// The position of the entity is at the center of the bottom of its bounding box.
+func (entity *Entity) GetBoundingBox() utils.AABB {
+	var width, height = entity.GetSize()
+	var halfWidth, position = width / 2, entity.GetPosition()
+	return utils.AABB{
+		Min: r3.Vector{X: position.X - halfWidth, Y: position.Y, Z: position.Z - halfWidth},
+		Max: r3.Vector{X: position.X + halfWidth, Y: position.Y + height, Z: position.Z + halfWidth},
+	}
+}
+
+// doPhysics moves the entity by its motion, stopping it at blocks it collides with, and applies gravity and drag to its motion.
+// Players are never moved, as their movement is decided by their client. Riders are moved along with their vehicle instead.
+func (entity *Entity) doPhysics() {
+	var dimension = entity.GetDimension()
+	if entity.entityType == Player || dimension == nil || entity.IsRiding() {
+		return
+	}
+	var typePhysics = GetPhysics(entity.entityType)
+	var start = entity.GetMotion()
+	var motion = start
+	motion.Y -= typePhysics.Gravity
+	if motion.Norm2() == 0 {
+		entity.setPhysicsMotion(start, motion)
+		return
+	}
+
+	var box = entity.GetBoundingBox()
+	var query = box.Extend(motion)
+	// Blocks in unloaded chunks have no collision boxes, so entities only collide with unloaded chunks if border walls are enabled.
+	var boxes, _ = dimension.GetCollisionBoxes(query)
+	boxes = append(boxes, dimension.GetBorderWallBoxes(query)...)
+	var movement = motion
+	for _, block := range boxes {
+		movement.Y = block.ClipY(box, movement.Y)
+	}
+	box = box.Offset(r3.Vector{Y: movement.Y})
+	for _, block := range boxes {
+		movement.X = block.ClipX(box, movement.X)
+	}
+	box = box.Offset(r3.Vector{X: movement.X})
+	for _, block := range boxes {
+		movement.Z = block.ClipZ(box, movement.Z)
+	}
+
+	var onGround = movement.Y != motion.Y && motion.Y < 0
+	if movement.X != motion.X {
+		motion.X = 0
+	}
+	if movement.Y != motion.Y {
+		motion.Y = 0
+	}
+	if movement.Z != motion.Z {
+		motion.Z = 0
+	}
+	entity.mutex.Lock()
+	entity.OnGround = onGround
+	entity.mutex.Unlock()
+	if err := entity.SetPosition(entity.GetPosition().Add(movement)); err != nil {
+		entity.setPhysicsMotion(start, r3.Vector{})
+		return
+	}
+	entity.updateFall(movement.Y)
+
+	motion = motion.Mul(1 - typePhysics.Drag)
+	if onGround {
+		motion.X *= GroundFriction
+		motion.Z *= GroundFriction
+	}
+	entity.setPhysicsMotion(start, motion)
+}
+
+// setPhysicsMotion sets the motion of the entity computed by its physics from the start motion.
+// Motion changed by other goroutines since the start motion was read, such as by knockback, is added on top, so that it is not lost.
+func (entity *Entity) setPhysicsMotion(start, motion r3.Vector) {
+	entity.UpdateMotion(func(current r3.Vector) r3.Vector {
+		return motion.Add(current.Sub(start))
+	})
+}
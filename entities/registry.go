@@ -0,0 +1,167 @@
+package entities
+
+import (
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/chunks"
+	"strings"
+	"sync"
+)
+
+// identifiers are the string identifiers of all entity types of this package, without the minecraft namespace.
+var identifiers = map[EntityType]string{
+	Chicken: "chicken", Pig: "pig", Sheep: "sheep", Wolf: "wolf", Villager: "villager", Mooshroom: "mooshroom",
+	Squid: "squid", Rabbit: "rabbit", Bat: "bat", IronGolem: "iron_golem", SnowGolem: "snow_golem", Ocelot: "ocelot",
+	Horse: "horse", Donkey: "donkey", Mule: "mule", SkeletonHorse: "skeleton_horse", ZombieHorse: "zombie_horse",
+	PolarBear: "polar_bear", Llama: "llama", Parrot: "parrot",
+
+	Zombie: "zombie", Creeper: "creeper", Skeleton: "skeleton", Spider: "spider", ZombiePigman: "zombie_pigman",
+	Slime: "slime", Enderman: "enderman", SilverFish: "silverfish", CaveSpider: "cave_spider", Ghast: "ghast",
+	MagmaCube: "magma_cube", Blaze: "blaze", ZombieVillage: "zombie_villager", Witch: "witch", Stray: "stray",
+	Husk: "husk", WitherSkeleton: "wither_skeleton", Guardian: "guardian", ElderGuardian: "elder_guardian", NPC: "npc",
+	Wither: "wither", EnderDragon: "ender_dragon", Shulker: "shulker", Endermite: "endermite",
+	LearnToCodeMascot: "agent", Vindicator: "vindicator",
+
+	ArmorStand: "armor_stand", TripodCamera: "tripod_camera", Player: "player", Item: "item", Tnt: "tnt",
+	FallingBlock: "falling_block", MovingBlock: "moving_block", XpBottle: "xp_bottle", XpOrb: "xp_orb",
+	EyeOfEnderSignal: "eye_of_ender_signal", EnderCrystal: "ender_crystal", FireworksRocket: "fireworks_rocket",
+
+	ShulkerBullet: "shulker_bullet", FishingHook: "fishing_hook", ChalkBoard: "chalkboard",
+	DragonFireball: "dragon_fireball", Arrow: "arrow", Snowball: "snowball", Egg: "egg", Painting: "painting",
+	Minecart: "minecart", LargeFireball: "fireball", SplashPotion: "splash_potion", EnderPearl: "ender_pearl",
+	LeashKnot: "leash_knot", WitherSkull: "wither_skull", Boat: "boat", WitherSkullDangerous: "wither_skull_dangerous",
+	LightningBolt: "lightning_bolt", SmallFireball: "small_fireball", AreaEffectCloud: "area_effect_cloud",
+	HopperMinecart: "hopper_minecart", TntMinecart: "tnt_minecart", ChestMinecart: "chest_minecart",
+	CommandBlockMinecart: "command_block_minecart", LingeringPotion: "lingering_potion", LlamaSpit: "llama_spit",
+	EvocationFang: "evocation_fang", Evoker: "evocation_illager", Vex: "vex",
+}
+
+// GetIdentifier returns the string identifier of the given entity type of this package, such as "minecraft:zombie",
+// and a bool indicating if the entity type has one. An empty identifier is returned for entity types without one.
+func GetIdentifier(entityType EntityType) (string, bool) {
+	var identifier, ok = identifiers[entityType]
+	if !ok {
+		return "", false
+	}
+	return "minecraft:" + identifier, true
+}
+
+// Registry maps entity types and their string identifiers to the functions constructing their entities.
+// Unlike a manager, a registry is safe for concurrent use, and entities can be created by their identifier.
+type Registry struct {
+	mutex       sync.RWMutex
+	manager     Manager
+	types       map[string]EntityType
+	identifiers map[EntityType]string
+}
+
+// NewRegistry returns a new entity registry holding the entities registered in the manager,
+// with the identifiers of their entity types of this package. Passing a manager filled by RegisterDefaults
+// returns a registry of the base entities of this package, and passing NewManager() returns an empty registry.
+func NewRegistry(manager Manager) *Registry {
+	var registry = &Registry{sync.RWMutex{}, NewManager(), make(map[string]EntityType), make(map[EntityType]string)}
+	for entityType, constructor := range manager {
+		if identifier, ok := GetIdentifier(entityType); ok {
+			registry.Register(entityType, identifier, constructor)
+		} else {
+			registry.manager.Register(entityType, constructor)
+		}
+	}
+	return registry
+}
+
+// normalizeIdentifier returns the identifier in lower case, with the minecraft namespace added if it has none.
+func normalizeIdentifier(identifier string) string {
+	identifier = strings.ToLower(identifier)
+	if !strings.Contains(identifier, ":") {
+		identifier = "minecraft:" + identifier
+	}
+	return identifier
+}
+
+// Register registers the constructor for the given entity type and string identifier.
+// Identifiers without namespace get the minecraft namespace.
+// Register overwrites any constructor that might have been previously registered on the entity type or identifier.
+func (registry *Registry) Register(entityType EntityType, identifier string, constructor func() chunks.ChunkEntity) {
+	identifier = normalizeIdentifier(identifier)
+	registry.mutex.Lock()
+	if previous, ok := registry.identifiers[entityType]; ok {
+		delete(registry.types, previous)
+	}
+	if previous, ok := registry.types[identifier]; ok {
+		delete(registry.identifiers, previous)
+		registry.manager.Deregister(previous)
+	}
+	registry.manager.Register(entityType, constructor)
+	registry.types[identifier] = entityType
+	registry.identifiers[entityType] = identifier
+	registry.mutex.Unlock()
+}
+
+// Deregister deregisters the constructor and identifier of the given entity type.
+func (registry *Registry) Deregister(entityType EntityType) {
+	registry.mutex.Lock()
+	delete(registry.types, registry.identifiers[entityType])
+	delete(registry.identifiers, entityType)
+	registry.manager.Deregister(entityType)
+	registry.mutex.Unlock()
+}
+
+// IsRegistered checks if a constructor for the given entity type is registered.
+func (registry *Registry) IsRegistered(entityType EntityType) bool {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	return registry.manager.IsRegistered(entityType)
+}
+
+// GetType returns the entity type registered with the given identifier, and a bool indicating if it was registered.
+func (registry *Registry) GetType(identifier string) (EntityType, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	var entityType, ok = registry.types[normalizeIdentifier(identifier)]
+	return entityType, ok
+}
+
+// GetEntityType returns the entity type registered with the given identifier as an unsigned int,
+// and a bool indicating if it was registered. GetEntityType is used by dimensions to resolve the string ids of entity NBT.
+func (registry *Registry) GetEntityType(identifier string) (uint32, bool) {
+	var entityType, ok = registry.GetType(identifier)
+	return uint32(entityType), ok
+}
+
+// GetIdentifier returns the identifier the given entity type was registered with, and a bool indicating if it was registered.
+func (registry *Registry) GetIdentifier(entityType EntityType) (string, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	var identifier, ok = registry.identifiers[entityType]
+	return identifier, ok
+}
+
+// New constructs a new entity of the given entity type.
+// Returns UnregisteredEntity if no constructor for the entity type was registered.
+func (registry *Registry) New(entityType EntityType) (chunks.ChunkEntity, error) {
+	registry.mutex.RLock()
+	var constructor, ok = registry.manager[entityType]
+	registry.mutex.RUnlock()
+	if !ok {
+		return nil, UnregisteredEntity
+	}
+	return constructor(), nil
+}
+
+// NewEntity constructs a new entity of the entity type registered with the given identifier, such as "minecraft:zombie" or "zombie".
+// Returns UnregisteredEntity if no entity type was registered with the identifier.
+func (registry *Registry) NewEntity(identifier string) (chunks.ChunkEntity, error) {
+	var entityType, ok = registry.GetType(identifier)
+	if !ok {
+		return nil, UnregisteredEntity
+	}
+	return registry.New(entityType)
+}
+
+// Create constructs a new entity of the given entity type, and loads it from the NBT.
+// Create is used by dimensions with the registry to create the entities of the entity NBT of chunks.
+func (registry *Registry) Create(entityType uint32, nbt *gonbt.Compound) (chunks.ChunkEntity, error) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	return registry.manager.Create(entityType, nbt)
+}
@@ -0,0 +1,285 @@
+package entities
+
+import (
+	"errors"
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/entities/data"
+	"math"
+	"sync"
+)
+
+// Seat is a place on a vehicle a rider sits at.
+type Seat struct {
+	// Offset is the position of the rider relative to the vehicle when the vehicle faces south.
+	// The offset is rotated along with the yaw of the vehicle.
+	Offset r3.Vector
+	// RotationLocked specifies if the rider can only look within MaxRotation degrees of the yaw of the vehicle, such as in boats.
+	RotationLocked bool
+	MaxRotation    float32
+}
+
+// Rideable are the riding properties of a type of vehicle entity.
+type Rideable struct {
+	// Seats are the seats of the vehicle. The rider in the first seat controls the vehicle.
+	Seats []Seat
+	// Controlled specifies if the vehicle is steered by the rider in its first seat using WASD, such as horses and boats.
+	Controlled bool
+}
+
+// EntityLinkType is the way a rider is linked to its vehicle.
+type EntityLinkType byte
+
+const (
+	// LinkRemove unlinks a rider from its vehicle.
+	LinkRemove EntityLinkType = iota
+	// LinkRider links a rider controlling its vehicle.
+	LinkRider
+	// LinkPassenger links a rider not controlling its vehicle.
+	LinkPassenger
+)
+
+// LinkViewer is a viewer that can be sent links between riders and their vehicles.
+// Viewers not implementing this interface do not see entities riding.
+type LinkViewer interface {
+	Viewer
+	SendEntityLink(vehicleId, riderId uint64, linkType EntityLinkType)
+}
+
+// NotRideable gets returned when an entity tries to ride an entity that is not a vehicle.
+var NotRideable = errors.New("entity cannot be ridden")
+
+// NoFreeSeat gets returned when an entity tries to ride a vehicle of which all seats are taken.
+var NoFreeSeat = errors.New("vehicle has no free seat")
+
+// ridingState is the state of an entity riding a vehicle, or being ridden.
+type ridingState struct {
+	vehicle *Entity
+	seat    int
+	// riders are the riders of the entity, indexed by their seat. Free seats are nil.
+	riders []*Entity
+}
+
+var rideablesMutex sync.RWMutex
+
+// horseSeats are the seats of horses and horse-like mobs.
+var horseSeats = []Seat{{r3.Vector{Y: 1.1}, false, 0}}
+
+// rideables are the riding properties of entity types that can be ridden, keyed by entity type.
+var rideables = map[EntityType]Rideable{
+	Minecart:      {[]Seat{{r3.Vector{Y: 0.35}, false, 0}}, false},
+	Boat:          {[]Seat{{r3.Vector{Y: 1.02, Z: 0.2}, true, 90}, {r3.Vector{Y: 1.02, Z: -0.6}, true, 90}}, true},
+	Horse:         {horseSeats, true},
+	Donkey:        {horseSeats, true},
+	Mule:          {horseSeats, true},
+	SkeletonHorse: {horseSeats, true},
+	ZombieHorse:   {horseSeats, true},
+	Llama:         {horseSeats, false},
+	Pig:           {[]Seat{{r3.Vector{Y: 0.63}, false, 0}}, false},
+}
+
+// GetRideable returns the riding properties of the entity type, and a bool indicating if the entity type can be ridden.
+func GetRideable(entityType EntityType) (Rideable, bool) {
+	rideablesMutex.RLock()
+	defer rideablesMutex.RUnlock()
+	var rideable, ok = rideables[entityType]
+	return rideable, ok
+}
+
+// SetRideable sets the riding properties of the entity type.
+// Custom entity types must be registered this way in order to be ridden.
+func SetRideable(entityType EntityType, rideable Rideable) {
+	rideablesMutex.Lock()
+	rideables[entityType] = rideable
+	rideablesMutex.Unlock()
+}
+
+// Ride makes the entity ride the vehicle in the first free seat of the vehicle, dismounting whatever it was riding before.
+// Returns NotRideable if the vehicle cannot be ridden, or NoFreeSeat if all seats of the vehicle are taken.
+func (entity *Entity) Ride(vehicle *Entity) error {
+	var rideable, ok = GetRideable(vehicle.entityType)
+	if !ok || vehicle == entity || vehicle.IsClosed() {
+		return NotRideable
+	}
+	vehicle.mutex.Lock()
+	var seat = -1
+	for i := range rideable.Seats {
+		if i >= len(vehicle.riding.riders) {
+			vehicle.riding.riders = append(vehicle.riding.riders, nil)
+		}
+		if vehicle.riding.riders[i] == nil {
+			vehicle.riding.riders[i], seat = entity, i
+			break
+		}
+	}
+	vehicle.mutex.Unlock()
+	if seat == -1 {
+		return NoFreeSeat
+	}
+	// The entity only stops riding its current vehicle once its seat in the new vehicle is reserved.
+	entity.Dismount()
+
+	var properties = rideable.Seats[seat]
+	entity.mutex.Lock()
+	entity.riding.vehicle, entity.riding.seat = vehicle, seat
+	entity.ridingId = vehicle.GetRuntimeId()
+	entity.setEntityDataFlag(data.EntityDataRiderSeatPosition, data.EntityDataVector, properties.Offset)
+	entity.setEntityDataFlag(data.EntityDataRiderRotationLocked, data.EntityDataByte, boolByte(properties.RotationLocked))
+	entity.setEntityDataFlag(data.EntityDataRiderMaxRotation, data.EntityDataFloat, properties.MaxRotation)
+	entity.setEntityDataFlag(data.EntityDataRiderMinRotation, data.EntityDataFloat, -properties.MaxRotation)
+	entity.mutex.Unlock()
+	entity.SetEntityProperty(data.EntityDataRiding, true)
+	if seat == 0 && rideable.Controlled {
+		vehicle.SetEntityProperty(data.EntityDataWasdControlled, true)
+	}
+
+	for _, viewer := range vehicle.GetViewers() {
+		entity.sendLink(viewer)
+	}
+	vehicle.syncRiders()
+	return nil
+}
+
+// Dismount makes the entity stop riding its vehicle. Nothing happens if the entity is not riding.
+func (entity *Entity) Dismount() {
+	entity.mutex.Lock()
+	var vehicle, seat = entity.riding.vehicle, entity.riding.seat
+	if vehicle == nil {
+		entity.mutex.Unlock()
+		return
+	}
+	entity.riding.vehicle, entity.riding.seat = nil, 0
+	entity.ridingId = 0
+	entity.setEntityDataFlag(data.EntityDataRiderRotationLocked, data.EntityDataByte, byte(0))
+	for _, key := range []uint32{data.EntityDataRiderSeatPosition, data.EntityDataRiderMaxRotation, data.EntityDataRiderMinRotation} {
+		delete(entity.entityData, key)
+		delete(entity.updatedEntityData, key)
+	}
+	entity.mutex.Unlock()
+	entity.SetEntityProperty(data.EntityDataRiding, false)
+
+	vehicle.mutex.Lock()
+	if seat < len(vehicle.riding.riders) && vehicle.riding.riders[seat] == entity {
+		vehicle.riding.riders[seat] = nil
+	}
+	vehicle.mutex.Unlock()
+	if seat == 0 {
+		vehicle.SetEntityProperty(data.EntityDataWasdControlled, false)
+	}
+
+	for _, viewer := range vehicle.GetViewers() {
+		if linkViewer, ok := viewer.(LinkViewer); ok {
+			linkViewer.SendEntityLink(vehicle.GetRuntimeId(), entity.GetRuntimeId(), LinkRemove)
+		}
+	}
+}
+
+// DismountRiders makes all riders of the entity stop riding it.
+func (entity *Entity) DismountRiders() {
+	for _, rider := range entity.GetRiders() {
+		rider.Dismount()
+	}
+}
+
+// IsRiding checks if the entity is riding a vehicle.
+func (entity *Entity) IsRiding() bool {
+	return entity.GetVehicle() != nil
+}
+
+// GetVehicle returns the vehicle the entity is riding, or nil if it is not riding.
+func (entity *Entity) GetVehicle() *Entity {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.riding.vehicle
+}
+
+// GetSeat returns the index of the seat of its vehicle the entity is riding in.
+func (entity *Entity) GetSeat() int {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.riding.seat
+}
+
+// GetRiders returns all entities riding the entity, ordered by their seat.
+func (entity *Entity) GetRiders() []*Entity {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	var riders []*Entity
+	for _, rider := range entity.riding.riders {
+		if rider != nil {
+			riders = append(riders, rider)
+		}
+	}
+	return riders
+}
+
+// GetControllingRider returns the entity riding in the first seat of the entity, which controls it, or nil if there is none.
+func (entity *Entity) GetControllingRider() *Entity {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	if len(entity.riding.riders) == 0 {
+		return nil
+	}
+	return entity.riding.riders[0]
+}
+
+// sendLinks sends the links of the entity to its vehicle and to its riders to the viewer.
+func (entity *Entity) sendLinks(viewer Viewer) {
+	entity.sendLink(viewer)
+	for _, rider := range entity.GetRiders() {
+		rider.sendLink(viewer)
+	}
+}
+
+// sendLink sends the link of the entity to its vehicle to the viewer, if the entity is riding and the viewer supports links.
+func (entity *Entity) sendLink(viewer Viewer) {
+	var linkViewer, ok = viewer.(LinkViewer)
+	var vehicle = entity.GetVehicle()
+	if !ok || vehicle == nil {
+		return
+	}
+	var linkType = LinkPassenger
+	if rideable, _ := GetRideable(vehicle.entityType); entity.GetSeat() == 0 && rideable.Controlled {
+		linkType = LinkRider
+	}
+	linkViewer.SendEntityLink(vehicle.GetRuntimeId(), entity.GetRuntimeId(), linkType)
+}
+
+// syncRiders moves all riders of the entity to their seats, and limits the rotation of riders in seats with their rotation locked.
+// It gets called every tick after the entity moved, by the goroutine ticking the entity and its riders.
+func (entity *Entity) syncRiders() {
+	entity.mutex.RLock()
+	var riders = append([]*Entity(nil), entity.riding.riders...)
+	entity.mutex.RUnlock()
+	if len(riders) == 0 {
+		return
+	}
+	var rideable, _ = GetRideable(entity.entityType)
+	var position, rotation = entity.GetPosition(), entity.GetRotation()
+	var radians = rotation.Yaw * math.Pi / 180
+	var sin, cos = math.Sin(radians), math.Cos(radians)
+	for seat, rider := range riders {
+		if rider == nil || seat >= len(rideable.Seats) {
+			continue
+		}
+		var properties = rideable.Seats[seat]
+		var offset = properties.Offset
+		rider.SetPosition(position.Add(r3.Vector{X: offset.X*cos - offset.Z*sin, Y: offset.Y, Z: offset.X*sin + offset.Z*cos}))
+		if properties.RotationLocked {
+			var riderRotation = rider.GetRotation()
+			var yaw = rotation.Yaw + clampYaw(riderRotation.Yaw-rotation.Yaw, float64(properties.MaxRotation))
+			rider.SetRotation(data.NewRotation(yaw, yaw, riderRotation.Pitch))
+		}
+	}
+}
+
+// clampYaw wraps the yaw difference to the range of -180 to 180 degrees, and clamps it to the maximum rotation.
+func clampYaw(difference, maxRotation float64) float64 {
+	difference = math.Remainder(difference, 360)
+	if difference > maxRotation {
+		return maxRotation
+	}
+	if difference < -maxRotation {
+		return -maxRotation
+	}
+	return difference
+}
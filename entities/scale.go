@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"github.com/irmine/worlds/entities/data"
+)
+
+// GetScale returns the scale of the entity. Entities have a scale of 1 by default.
+func (entity *Entity) GetScale() float32 {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.scale
+}
+
+// SetScale sets the scale of the entity, such as 0.5 for babies.
+// The bounding box of the entity gets scaled along, and the new size is sent to viewers.
+// Scales of 0 or lower are ignored.
+func (entity *Entity) SetScale(scale float32) {
+	if scale <= 0 {
+		return
+	}
+	entity.mutex.Lock()
+	entity.scale = scale
+	entity.mutex.Unlock()
+	entity.updateSize()
+}
+
+// GetSize returns the width and height of the bounding box of the entity,
+// which are the width and height of the physics of its entity type multiplied by its scale.
+func (entity *Entity) GetSize() (float64, float64) {
+	var typePhysics, scale = GetPhysics(entity.entityType), float64(entity.GetScale())
+	return typePhysics.Width * scale, typePhysics.Height * scale
+}
+
+// updateSize sets the scale and bounding box size entity data of the entity, so that viewers get sent them.
+func (entity *Entity) updateSize() {
+	var width, height = entity.GetSize()
+	entity.SetEntityDataFlag(data.EntityDataScale, data.EntityDataFloat, entity.GetScale())
+	entity.SetEntityDataFlag(data.EntityDataBoundingBoxWidth, data.EntityDataFloat, float32(width))
+	entity.SetEntityDataFlag(data.EntityDataBoundingBoxHeight, data.EntityDataFloat, float32(height))
+}
@@ -101,3 +101,9 @@ const (
 	Evoker
 	Vex
 )
+
+// IsMob checks if the entity type is a mob, such as animals and monsters.
+// Loot of mobs is dropped depending on the doMobLoot game rule, rather than the doEntityDrops game rule.
+func (entityType EntityType) IsMob() bool {
+	return entityType >= Chicken && entityType < ArmorStand
+}
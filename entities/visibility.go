@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"github.com/irmine/worlds"
+	"github.com/irmine/worlds/chunks"
+)
+
+// DefaultViewRange is the distance within which new entities get spawned to viewers.
+const DefaultViewRange = 64
+
+// GetViewRange returns the distance within which the entity gets spawned to viewers.
+func (entity *Entity) GetViewRange() float64 {
+	entity.mutex.RLock()
+	defer entity.mutex.RUnlock()
+	return entity.viewRange
+}
+
+// SetViewRange sets the distance within which the entity gets spawned to viewers.
+// A view range of 0 or lower makes the entity visible to all viewers of its chunk.
+func (entity *Entity) SetViewRange(viewRange float64) {
+	entity.mutex.Lock()
+	entity.viewRange = viewRange
+	entity.mutex.Unlock()
+}
+
+// IsInViewRange checks if the viewer is within the view range of the entity.
+// Viewers not implementing worlds.PositionedViewer see entities in the chunks they view regardless of their view range.
+func (entity *Entity) IsInViewRange(viewer Viewer) bool {
+	var viewRange = entity.GetViewRange()
+	if positioned, ok := viewer.(worlds.PositionedViewer); ok && viewRange > 0 {
+		return positioned.GetPosition().Distance(entity.GetPosition()) <= viewRange
+	}
+	return true
+}
+
+// IsSpawnedTo checks if the entity is spawned to the viewer.
+func (entity *Entity) IsSpawnedTo(viewer chunks.Viewer) bool {
+	var _, ok = entity.GetViewers()[viewer.GetUUID()]
+	return ok
+}
+
+// UpdateVisibility spawns the entity to the viewer if the viewer views its chunk and is within its view range,
+// and despawns it from the viewer otherwise. Entities are never spawned to themselves.
+func (entity *Entity) UpdateVisibility(viewer chunks.Viewer) {
+	var entityViewer, ok = viewer.(Viewer)
+	if !ok || entity.IsClosed() {
+		return
+	}
+	if self, ok := viewer.(chunks.ChunkEntity); ok && self.GetRuntimeId() == entity.runtimeId {
+		return
+	}
+	var visible = false
+	if chunk := entity.GetChunk(); chunk != nil {
+		var _, viewing = chunk.GetViewers()[viewer.GetUUID()]
+		visible = viewing && entity.IsInViewRange(entityViewer)
+	}
+	if spawned := entity.IsSpawnedTo(viewer); visible && !spawned {
+		entity.SpawnTo(entityViewer)
+	} else if !visible && spawned {
+		entity.DespawnFrom(entityViewer)
+	}
+}
+
+// HideFrom despawns the entity from the viewer if it is spawned to it.
+func (entity *Entity) HideFrom(viewer chunks.Viewer) {
+	if entityViewer, ok := viewer.(Viewer); ok && entity.IsSpawnedTo(viewer) {
+		entity.DespawnFrom(entityViewer)
+	}
+}
+
+// despawnFromChunkLeavers despawns the entity from all viewers that do not view the given chunk, after the entity moved into it.
+func (entity *Entity) despawnFromChunkLeavers(chunk *chunks.Chunk) {
+	var chunkViewers = chunk.GetViewers()
+	for id, viewer := range entity.GetViewers() {
+		if _, ok := chunkViewers[id]; !ok {
+			entity.DespawnFrom(viewer)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package entities
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds"
+	"github.com/irmine/worlds/entities/data"
+)
+
+const (
+	// XpOrbLifetime is the amount of ticks an experience orb exists before it despawns.
+	XpOrbLifetime = 6000
+	// XpOrbAttractRange is the distance within which experience orbs move towards viewers.
+	XpOrbAttractRange = 8
+	// XpOrbPickupRange is the distance within which experience orbs get picked up by viewers.
+	XpOrbPickupRange = 1
+	// XpOrbMergeRange is the distance within which experience orbs merge into one orb.
+	XpOrbMergeRange = 1
+	// XpOrbMergeInterval is the amount of ticks between experience orbs looking for orbs to merge with.
+	XpOrbMergeInterval = 20
+)
+
+// PickupFunction gets called when a viewer is close enough to the experience orb to pick it up.
+// The experience orb gets picked up and closed if the function returns true, for which the function should give the viewer the experience.
+type PickupFunction func(orb *ExperienceOrb, viewer Viewer) bool
+
+// ExperienceOrb is an entity holding experience, which moves towards nearby viewers and merges with nearby orbs.
+// Experience orbs are only picked up by viewers if they have a pickup function.
+type ExperienceOrb struct {
+	*Entity
+	amount         int
+	lifetime       int
+	pickupFunction PickupFunction
+}
+
+// NewExperienceOrb returns a new experience orb holding the amount of experience.
+func NewExperienceOrb(amount int) *ExperienceOrb {
+	var orb = &ExperienceOrb{New(XpOrb), 0, 0, nil}
+	orb.SetAmount(amount)
+	orb.SetOuterEntity(orb)
+	return orb
+}
+
+// ExperienceOrbFunction returns a new experience orb holding the amount of experience.
+// It can be used as the experience function of a dimension, after which Dimension.SpawnXp spawns experience orbs.
+func ExperienceOrbFunction(amount int, position r3.Vector) worlds.DroppedItem {
+	return NewExperienceOrb(amount)
+}
+
+// GetAmount returns the amount of experience the experience orb holds.
+func (orb *ExperienceOrb) GetAmount() int {
+	orb.mutex.RLock()
+	defer orb.mutex.RUnlock()
+	return orb.amount
+}
+
+// SetAmount sets the amount of experience the experience orb holds, which decides the size it is shown with.
+func (orb *ExperienceOrb) SetAmount(amount int) {
+	orb.mutex.Lock()
+	orb.amount = amount
+	orb.setEntityDataFlag(data.EntityDataExperienceValue, data.EntityDataInt, int32(amount))
+	orb.mutex.Unlock()
+}
+
+// SetPickupFunction sets the function called when a viewer is close enough to pick up the experience orb.
+func (orb *ExperienceOrb) SetPickupFunction(function PickupFunction) {
+	orb.mutex.Lock()
+	orb.pickupFunction = function
+	orb.mutex.Unlock()
+}
+
+// Tick ticks the experience orb, moving it towards the nearest viewer and merging it with nearby experience orbs.
+// Experience orbs despawn once they existed for XpOrbLifetime ticks.
+func (orb *ExperienceOrb) Tick() {
+	orb.Entity.Tick()
+	if orb.IsClosed() || !orb.tickLifetime() {
+		return
+	}
+	orb.attract()
+	orb.mutex.RLock()
+	var merge = orb.lifetime%XpOrbMergeInterval == 0
+	orb.mutex.RUnlock()
+	if merge {
+		orb.merge()
+	}
+}
+
+// IdleTick ticks the experience orb while it is not simulated, only counting its lifetime.
+func (orb *ExperienceOrb) IdleTick() {
+	orb.Entity.IdleTick()
+	if !orb.IsClosed() {
+		orb.tickLifetime()
+	}
+}
+
+// tickLifetime counts the lifetime of the experience orb, closing it once its lifetime is over.
+// Returns false if the experience orb got closed.
+func (orb *ExperienceOrb) tickLifetime() bool {
+	orb.mutex.Lock()
+	orb.lifetime++
+	var over = orb.lifetime >= XpOrbLifetime
+	orb.mutex.Unlock()
+	if over {
+		orb.Close()
+	}
+	return !over
+}
+
+// attract moves the experience orb towards the nearest viewer within the attract range, and lets it get picked up when close enough.
+func (orb *ExperienceOrb) attract() {
+	var position = orb.GetPosition()
+	var nearest Viewer
+	var target r3.Vector
+	var distance = float64(XpOrbAttractRange)
+	for _, viewer := range orb.GetViewers() {
+		var positioned, ok = viewer.(worlds.PositionedViewer)
+		if !ok {
+			continue
+		}
+		if d := positioned.GetPosition().Sub(position).Norm(); d < distance {
+			nearest, target, distance = viewer, positioned.GetPosition(), d
+		}
+	}
+	if nearest == nil {
+		return
+	}
+
+	orb.mutex.RLock()
+	var function = orb.pickupFunction
+	orb.mutex.RUnlock()
+	if distance <= XpOrbPickupRange && function != nil && function(orb, nearest) {
+		orb.Close()
+		return
+	}
+	if distance == 0 {
+		return
+	}
+	// Experience orbs are pulled harder the closer they are, the same way as in Minecraft.
+	var pull = 1 - distance/XpOrbAttractRange
+	orb.SetMotion(orb.GetMotion().Add(target.Sub(position).Mul(pull * pull * 0.1 / distance)))
+}
+
+// merge merges all experience orbs within the merge range into this experience orb.
+func (orb *ExperienceOrb) merge() {
+	var dimension = orb.GetDimension()
+	if dimension == nil {
+		return
+	}
+	for _, entity := range dimension.GetCollidingEntities(orb.GetBoundingBox().Grow(XpOrbMergeRange), orb.Entity) {
+		if other, ok := entity.(*ExperienceOrb); ok && !other.IsClosed() {
+			orb.SetAmount(orb.GetAmount() + other.GetAmount())
+			other.Close()
+		}
+	}
+}
+
+// GetNBT returns the NBT of the experience orb, with the amount of experience it holds written into it.
+func (orb *ExperienceOrb) GetNBT() *gonbt.Compound {
+	var nbt = orb.Entity.GetNBT()
+	nbt.SetTag(gonbt.NewShort("Value", int16(orb.GetAmount())))
+	return nbt
+}
+
+// SetNBT sets the NBT of the experience orb, and loads the amount of experience it holds from it.
+func (orb *ExperienceOrb) SetNBT(nbt *gonbt.Compound) {
+	orb.Entity.SetNBT(nbt)
+	if nbt != nil {
+		orb.SetAmount(int(nbt.GetShort("Value", int16(orb.GetAmount()))))
+	}
+}
@@ -0,0 +1,90 @@
+package worlds
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/chunks"
+	"github.com/irmine/worlds/utils"
+	"math"
+)
+
+// BoxedEntity is an entity with a bounding box, used to check what entities a bounding box collides with.
+// The entities of the entities package implement it. Entities not implementing this interface collide only at their position.
+type BoxedEntity interface {
+	chunks.ChunkEntity
+	GetBoundingBox() utils.AABB
+}
+
+// EntitySearchMargin is the distance outside of a bounding box entities are searched for in,
+// so that entities positioned outside of the chunks the bounding box is in, but with bounding boxes reaching into it, are found.
+const EntitySearchMargin = 2
+
+// GetCollidingEntities returns all entities whose bounding box intersects the given bounding box, in chunks that are loaded.
+// The excluded entity, such as the entity the bounding box belongs to, is left out. Exclude may be nil.
+func (dimension *Dimension) GetCollidingEntities(box utils.AABB, exclude chunks.ChunkEntity) []chunks.ChunkEntity {
+	var search = box.Grow(EntitySearchMargin)
+	var colliding []chunks.ChunkEntity
+	for _, entity := range dimension.getChunkEntities(search.Min.X, search.Min.Z, search.Max.X, search.Max.Z) {
+		if exclude != nil && entity.GetRuntimeId() == exclude.GetRuntimeId() {
+			continue
+		}
+		if collidesWith(entity, box) {
+			colliding = append(colliding, entity)
+		}
+	}
+	return colliding
+}
+
+// collidesWith checks if the entity collides with the bounding box.
+func collidesWith(entity chunks.ChunkEntity, box utils.AABB) bool {
+	if boxed, ok := entity.(BoxedEntity); ok {
+		return boxed.GetBoundingBox().Intersects(box)
+	}
+	return box.Contains(entity.GetPosition())
+}
+
+// getChunkEntities returns all entities that are not closed in the loaded chunks the given X and Z range overlaps.
+// Only the chunks in the range are searched, rather than all entities of the dimension.
+func (dimension *Dimension) getChunkEntities(minX, minZ, maxX, maxZ float64) []chunks.ChunkEntity {
+	var minChunkX, maxChunkX = int32(math.Floor(minX)) >> 4, int32(math.Floor(maxX)) >> 4
+	var minChunkZ, maxChunkZ = int32(math.Floor(minZ)) >> 4, int32(math.Floor(maxZ)) >> 4
+	var entities []chunks.ChunkEntity
+	for chunkX := minChunkX; chunkX <= maxChunkX; chunkX++ {
+		for chunkZ := minChunkZ; chunkZ <= maxChunkZ; chunkZ++ {
+			var chunk, ok = dimension.GetChunk(chunkX, chunkZ)
+			if !ok {
+				continue
+			}
+			chunk.RLock()
+			for _, entity := range chunk.GetEntities() {
+				if !entity.IsClosed() {
+					entities = append(entities, entity)
+				}
+			}
+			chunk.RUnlock()
+		}
+	}
+	return entities
+}
+
+// GetNearbyEntities returns all entities within the given radius of the position, in chunks that are loaded.
+func (dimension *Dimension) GetNearbyEntities(position r3.Vector, radius float64) []chunks.ChunkEntity {
+	var nearby []chunks.ChunkEntity
+	for _, entity := range dimension.getChunkEntities(position.X-radius, position.Z-radius, position.X+radius, position.Z+radius) {
+		if entity.GetPosition().Sub(position).Norm2() <= radius*radius {
+			nearby = append(nearby, entity)
+		}
+	}
+	return nearby
+}
+
+// GetEntitiesInAABB returns all entities positioned inside of the given bounding box, in chunks that are loaded.
+// Use GetCollidingEntities to also find entities whose bounding box only partially reaches into it.
+func (dimension *Dimension) GetEntitiesInAABB(box utils.AABB) []chunks.ChunkEntity {
+	var inside []chunks.ChunkEntity
+	for _, entity := range dimension.getChunkEntities(box.Min.X, box.Min.Z, box.Max.X, box.Max.Z) {
+		if box.Contains(entity.GetPosition()) {
+			inside = append(inside, entity)
+		}
+	}
+	return inside
+}
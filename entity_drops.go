@@ -0,0 +1,67 @@
+package worlds
+
+import (
+	"github.com/golang/geo/r3"
+)
+
+// ExperienceFunction returns a new experience orb holding the amount of experience, to be added to the dimension at the given position.
+// Returns nil if no experience orb should be added.
+type ExperienceFunction func(amount int, position r3.Vector) DroppedItem
+
+// experienceOrbSizes are the amounts of experience orbs get split into, from large to small.
+var experienceOrbSizes = []int{2477, 1237, 617, 307, 149, 73, 37, 17, 7, 3, 1}
+
+// SetExperienceFunction sets the function creating experience orbs for experience dropped in the dimension.
+// No experience is dropped if nil.
+func (dimension *Dimension) SetExperienceFunction(function ExperienceFunction) {
+	dimension.mutex.Lock()
+	dimension.experienceFunction = function
+	dimension.mutex.Unlock()
+}
+
+// GetExperienceFunction returns the function creating experience orbs for experience dropped in the dimension.
+func (dimension *Dimension) GetExperienceFunction() ExperienceFunction {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.experienceFunction
+}
+
+// SplitXp splits the amount of experience into the experience of orbs the way Minecraft does, from large to small.
+func SplitXp(amount int) []int {
+	var orbs []int
+	for amount > 0 {
+		var size = 1
+		for _, orbSize := range experienceOrbSizes {
+			if amount >= orbSize {
+				size = orbSize
+				break
+			}
+		}
+		orbs = append(orbs, size)
+		amount -= size
+	}
+	return orbs
+}
+
+// SpawnXp spawns the amount of experience naturally as experience orbs at the position, split into orbs the way Minecraft does.
+// No experience is spawned if the dimension has no experience function.
+func (dimension *Dimension) SpawnXp(position r3.Vector, amount int) {
+	var function = dimension.GetExperienceFunction()
+	if function == nil {
+		return
+	}
+	for _, size := range SplitXp(amount) {
+		if orb := function(size, position); orb != nil {
+			dimension.DropItemNaturally(orb, position)
+		}
+	}
+}
+
+// CanDropEntityLoot checks if entities dying in the dimension may drop loot.
+// Mobs may drop loot if the doMobLoot game rule is enabled, other entities if the doEntityDrops game rule is enabled.
+func (dimension *Dimension) CanDropEntityLoot(mob bool) bool {
+	if mob {
+		return dimension.level.GetBoolGameRule(GameRuleDoMobLoot, true)
+	}
+	return dimension.level.GetBoolGameRule(GameRuleDoEntityDrops, true)
+}
@@ -0,0 +1,67 @@
+package worlds
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/blocks"
+	"github.com/irmine/worlds/chunks"
+)
+
+// DamageCause is the cause of damage an entity takes.
+type DamageCause byte
+
+const (
+	// DamageCustom is damage dealt by plugins.
+	DamageCustom DamageCause = iota
+	// DamageAttack is damage dealt by another entity attacking.
+	DamageAttack
+	// DamageFall is damage taken by landing after falling further than the safe fall distance.
+	DamageFall
+	// DamageFire is damage taken by standing in or burning from fire.
+	DamageFire
+	// DamageVoid is damage taken by falling out of the world. It ignores armor and invulnerability.
+	DamageVoid
+	// DamageLightning is damage taken by getting struck by lightning.
+	DamageLightning
+)
+
+// DamageSource is the source of damage an entity takes.
+type DamageSource struct {
+	// Cause is the cause of the damage.
+	Cause DamageCause
+	// Attacker is the entity that dealt the damage. It is nil for damage not dealt by an entity.
+	Attacker chunks.ChunkEntity
+}
+
+// EntityHooks are functions called for all entities of a dimension, so that servers can intercept their behavior.
+// Hooks that are nil are not called.
+type EntityHooks struct {
+	// OnEntitySpawn gets called after an entity was added to the dimension.
+	OnEntitySpawn func(entity chunks.ChunkEntity)
+	// OnEntityDespawn gets called after an entity was removed from the dimension.
+	OnEntityDespawn func(entity chunks.ChunkEntity)
+	// OnEntityMove gets called before an entity in the dimension moves, and returns the position the entity moves to instead.
+	// The entity does not move if the hook returns false.
+	OnEntityMove func(entity chunks.ChunkEntity, from, to r3.Vector) (r3.Vector, bool)
+	// OnEntityDamage gets called before an entity in the dimension takes damage, with the damage left after armor.
+	// The entity does not take the damage if the hook returns false.
+	OnEntityDamage func(entity chunks.ChunkEntity, source DamageSource, amount float32) bool
+	// OnEntityDeath gets called when an entity in the dimension dies, with the source of the damage that killed it.
+	OnEntityDeath func(entity chunks.ChunkEntity, source DamageSource)
+	// OnEntityLoot gets called when an entity in the dimension dies, with the items and experience it is about to drop.
+	// It returns the items and experience actually dropped, and is not called if the game rules prevent the entity from dropping loot.
+	OnEntityLoot func(entity chunks.ChunkEntity, source DamageSource, drops []blocks.ItemStack, experience int) ([]blocks.ItemStack, int)
+}
+
+// SetEntityHooks sets the entity hooks of the dimension.
+func (dimension *Dimension) SetEntityHooks(hooks EntityHooks) {
+	dimension.mutex.Lock()
+	dimension.entityHooks = hooks
+	dimension.mutex.Unlock()
+}
+
+// GetEntityHooks returns the entity hooks of the dimension.
+func (dimension *Dimension) GetEntityHooks() EntityHooks {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.entityHooks
+}
@@ -0,0 +1,71 @@
+package worlds
+
+import (
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/chunks"
+)
+
+// loadEntities creates the entities of the entity NBT of the chunk using the entity registry, and adds them to the dimension.
+func (dimension *Dimension) loadEntities(chunk *chunks.Chunk) {
+	var registry = dimension.GetEntityRegistry()
+	if registry == nil {
+		return
+	}
+	var loaded, _ = chunk.LoadEntitiesFromNBT(getEntityFactory(registry))
+	for _, entity := range loaded {
+		dimension.addEntity(chunk, entity, entity.GetPosition())
+	}
+}
+
+// getEntityFactory returns the factory creating entities from entity NBT with the registry.
+// Entity NBT of Anvil worlds holds the string identifier of its entity type as id, such as "minecraft:zombie",
+// which gets resolved to the entity type through the registry. Numeric ids are used as entity type directly.
+func getEntityFactory(registry EntityRegistry) chunks.EntityFactory {
+	return func(entityType uint32, nbt *gonbt.Compound) (chunks.ChunkEntity, error) {
+		if identifier := nbt.GetString("id", ""); identifier != "" {
+			var resolved, ok = registry.GetEntityType(identifier)
+			if !ok {
+				return nil, UnknownEntityIdentifier
+			}
+			entityType = resolved
+		}
+		return registry.Create(entityType, nbt)
+	}
+}
+
+// saveEntities marks all loaded chunks holding entities other than viewers as dirty,
+// so that the current NBT of their entities gets written when the chunks are saved.
+// Entities changing within a chunk do not mark the chunk dirty by themselves.
+func (dimension *Dimension) saveEntities() {
+	for _, chunk := range dimension.chunkProvider.GetLoadedChunks() {
+		chunk.RLock()
+		var persisted = false
+		for _, entity := range chunk.GetEntities() {
+			if _, ok := entity.(chunks.Viewer); !ok {
+				persisted = true
+				break
+			}
+		}
+		chunk.RUnlock()
+		if persisted {
+			chunk.SetDirty(true)
+		}
+	}
+}
+
+// unloadEntities writes the entities of the chunk back into its entity NBT, and removes and closes them.
+// Viewers are left in the dimension, as they are not persisted in chunks.
+func (dimension *Dimension) unloadEntities(chunk *chunks.Chunk) {
+	chunk.SetEntityNBT(chunk.EntitiesToNBT())
+	chunk.RLock()
+	var runtimeIds = make([]uint64, 0, len(chunk.GetEntities()))
+	for runtimeId, entity := range chunk.GetEntities() {
+		if _, ok := entity.(chunks.Viewer); !ok {
+			runtimeIds = append(runtimeIds, runtimeId)
+		}
+	}
+	chunk.RUnlock()
+	for _, runtimeId := range runtimeIds {
+		dimension.RemoveEntity(runtimeId)
+	}
+}
@@ -0,0 +1,54 @@
+package worlds
+
+import (
+	"errors"
+	"github.com/golang/geo/r3"
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/chunks"
+)
+
+// EntityRegistry constructs entities by the string identifier of their entity type, such as "minecraft:zombie",
+// and creates entities from the entity NBT of chunks. The registry of the entities package implements it.
+type EntityRegistry interface {
+	NewEntity(identifier string) (chunks.ChunkEntity, error)
+	Create(entityType uint32, nbt *gonbt.Compound) (chunks.ChunkEntity, error)
+	GetEntityType(identifier string) (uint32, bool)
+}
+
+// NoEntityRegistry gets returned if an entity is attempted to be spawned in a dimension without entity registry.
+var NoEntityRegistry = errors.New("dimension has no entity registry")
+
+// UnknownEntityIdentifier gets returned if entity NBT holds a string identifier not registered in the entity registry.
+var UnknownEntityIdentifier = errors.New("entity NBT has an unregistered identifier")
+
+// SetEntityRegistry sets the entity registry of the dimension, used to construct entities spawned by their identifier
+// and to create entities from the entity NBT of chunks.
+// Entity NBT of chunks loaded while the dimension has no registry is kept in the chunk until a registry is set.
+func (dimension *Dimension) SetEntityRegistry(registry EntityRegistry) {
+	dimension.mutex.Lock()
+	dimension.entityRegistry = registry
+	dimension.mutex.Unlock()
+}
+
+// GetEntityRegistry returns the entity registry of the dimension.
+func (dimension *Dimension) GetEntityRegistry() EntityRegistry {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.entityRegistry
+}
+
+// SpawnEntity constructs an entity of the entity type with the given identifier using the entity registry, and adds it at the position.
+// Like AddEntity, the entity gets added once its chunk is loaded.
+// Returns NoEntityRegistry if the dimension has no entity registry, or the error of the registry if the entity could not be constructed.
+func (dimension *Dimension) SpawnEntity(identifier string, position r3.Vector) (chunks.ChunkEntity, error) {
+	var registry = dimension.GetEntityRegistry()
+	if registry == nil {
+		return nil, NoEntityRegistry
+	}
+	var entity, err = registry.NewEntity(identifier)
+	if err != nil {
+		return nil, err
+	}
+	dimension.AddEntity(entity, position)
+	return entity, nil
+}
@@ -0,0 +1,81 @@
+package worlds
+
+import (
+	"github.com/golang/geo/r3"
+	"math"
+)
+
+// IdleTicker is an entity that can be ticked cheaply while it is not simulated, such as to count down timers.
+// The entities of the entities package implement it. Entities not implementing this interface are not ticked at all while not simulated.
+type IdleTicker interface {
+	IdleTick()
+}
+
+// SetSimulationDistance sets the distance in chunks around viewers entities get ticked in.
+// Entities in chunks with viewers are always ticked. Other entities only get an idle tick.
+func (dimension *Dimension) SetSimulationDistance(distance int32) {
+	dimension.mutex.Lock()
+	dimension.simulationDistance = distance
+	dimension.mutex.Unlock()
+}
+
+// GetSimulationDistance returns the distance in chunks around viewers entities get ticked in.
+func (dimension *Dimension) GetSimulationDistance() int32 {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.simulationDistance
+}
+
+// tickEntities ticks all simulated entities, gives an idle tick to all other entities, and removes closed entities.
+func (dimension *Dimension) tickEntities() {
+	var distance, viewerChunks = dimension.GetSimulationDistance(), dimension.getViewerChunks()
+	for runtimeId, entity := range dimension.entities {
+		if entity.IsClosed() {
+			dimension.RemoveEntity(runtimeId)
+		} else if dimension.isSimulated(entity.GetPosition(), distance, viewerChunks) {
+			entity.Tick()
+		} else if idle, ok := entity.(IdleTicker); ok {
+			idle.IdleTick()
+		}
+	}
+}
+
+// getViewerChunks returns the chunk X and Z of all viewers with a position in the dimension.
+func (dimension *Dimension) getViewerChunks() [][2]int32 {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	if dimension.simulationDistance <= 0 {
+		return nil
+	}
+	var viewerChunks = make([][2]int32, 0, len(dimension.viewers))
+	for _, viewer := range dimension.viewers {
+		if positioned, ok := viewer.(PositionedViewer); ok {
+			var position = positioned.GetPosition()
+			viewerChunks = append(viewerChunks, [2]int32{int32(math.Floor(position.X)) >> 4, int32(math.Floor(position.Z)) >> 4})
+		}
+	}
+	return viewerChunks
+}
+
+// isSimulated checks if an entity at the position is simulated: if its chunk has viewers,
+// or if it is within the simulation distance of any of the viewer chunks.
+func (dimension *Dimension) isSimulated(position r3.Vector, distance int32, viewerChunks [][2]int32) bool {
+	var x, z = int32(math.Floor(position.X)) >> 4, int32(math.Floor(position.Z)) >> 4
+	if chunk, ok := dimension.GetChunk(x, z); ok && len(chunk.GetViewers()) > 0 {
+		return true
+	}
+	for _, viewerChunk := range viewerChunks {
+		if abs32(viewerChunk[0]-x) <= distance && abs32(viewerChunk[1]-z) <= distance {
+			return true
+		}
+	}
+	return false
+}
+
+// abs32 returns the absolute value of the int32.
+func abs32(value int32) int32 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,199 @@
+package io
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// Codec compresses and decompresses chunk data of a compression type.
+// Codecs must be safe for concurrent use.
+type Codec interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+var codecMutex sync.RWMutex
+
+// codecs are all codecs registered, keyed by their compression type.
+var codecs = map[CompressionType]Codec{
+	CompressionGzip: &gzipCodec{},
+	CompressionZlib: &zlibCodec{},
+	CompressionZstd: &zstdCodec{},
+}
+
+// RegisterCodec registers the codec for the given compression type, overwriting any codec previously registered.
+func RegisterCodec(compressionType CompressionType, codec Codec) {
+	codecMutex.Lock()
+	codecs[compressionType] = codec
+	codecMutex.Unlock()
+}
+
+// GetCodec returns the codec of the given compression type, and a bool indicating if it was registered.
+func GetCodec(compressionType CompressionType) (Codec, bool) {
+	codecMutex.RLock()
+	var codec, ok = codecs[compressionType]
+	codecMutex.RUnlock()
+	return codec, ok
+}
+
+// Compress compresses the data with the codec of the given compression type.
+// Returns UnknownCompression if no codec was registered for the compression type.
+func Compress(compressionType CompressionType, data []byte) ([]byte, error) {
+	var codec, ok = GetCodec(compressionType)
+	if !ok {
+		return nil, UnknownCompression
+	}
+	return codec.Compress(data)
+}
+
+// DetectCompression detects the compression type of the data by its magic bytes.
+// Returns false if the data does not start with the magic bytes of gzip, zlib or zstd.
+func DetectCompression(data []byte) (CompressionType, bool) {
+	switch {
+	case len(data) >= 4 && data[0] == 0x28 && data[1] == 0xb5 && data[2] == 0x2f && data[3] == 0xfd:
+		return CompressionZstd, true
+	case len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b:
+		return CompressionGzip, true
+	case len(data) >= 2 && data[0]&0x0f == 8 && (uint16(data[0])<<8|uint16(data[1]))%31 == 0:
+		return CompressionZlib, true
+	}
+	return 0, false
+}
+
+// Decompress decompresses the data with the codec of the given compression type.
+// The compression type is detected from the data if possible, so that chunks with a wrong compression type still get read.
+// Returns UnknownCompression if no codec was registered for the compression type.
+func Decompress(compressionType CompressionType, data []byte) ([]byte, error) {
+	if detected, ok := DetectCompression(data); ok {
+		compressionType = detected
+	}
+	var codec, ok = GetCodec(compressionType)
+	if !ok {
+		return nil, UnknownCompression
+	}
+	return codec.Decompress(data)
+}
+
+// zlibCodec is the codec for CompressionZlib, pooling its readers and writers.
+type zlibCodec struct {
+	readers sync.Pool
+	writers sync.Pool
+}
+
+// Compress compresses the data using zlib.
+func (codec *zlibCodec) Compress(data []byte) ([]byte, error) {
+	var buffer = bytes.NewBuffer([]byte{})
+	var writer, ok = codec.writers.Get().(*zlib.Writer)
+	if ok {
+		writer.Reset(buffer)
+	} else {
+		writer = zlib.NewWriter(buffer)
+	}
+	defer codec.writers.Put(writer)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Decompress decompresses zlib compressed data.
+func (codec *zlibCodec) Decompress(data []byte) ([]byte, error) {
+	var reader, ok = codec.readers.Get().(io.ReadCloser)
+	if ok {
+		if err := reader.(zlib.Resetter).Reset(bytes.NewReader(data), nil); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if reader, err = zlib.NewReader(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+	}
+	defer codec.readers.Put(reader)
+	return ioutil.ReadAll(reader)
+}
+
+// gzipCodec is the codec for CompressionGzip, pooling its readers and writers.
+type gzipCodec struct {
+	readers sync.Pool
+	writers sync.Pool
+}
+
+// Compress compresses the data using gzip.
+func (codec *gzipCodec) Compress(data []byte) ([]byte, error) {
+	var buffer = bytes.NewBuffer([]byte{})
+	var writer, ok = codec.writers.Get().(*gzip.Writer)
+	if ok {
+		writer.Reset(buffer)
+	} else {
+		writer = gzip.NewWriter(buffer)
+	}
+	defer codec.writers.Put(writer)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Decompress decompresses gzip compressed data.
+func (codec *gzipCodec) Decompress(data []byte) ([]byte, error) {
+	var reader, ok = codec.readers.Get().(*gzip.Reader)
+	if ok {
+		if err := reader.Reset(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if reader, err = gzip.NewReader(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+	}
+	defer codec.readers.Put(reader)
+	return ioutil.ReadAll(reader)
+}
+
+// zstdCodec is the codec for CompressionZstd.
+// Its encoder and decoder are created once and shared, as they are safe for concurrent use.
+type zstdCodec struct {
+	once    sync.Once
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+	err     error
+}
+
+// init creates the encoder and decoder of the codec.
+func (codec *zstdCodec) init() {
+	codec.once.Do(func() {
+		if codec.encoder, codec.err = zstd.NewWriter(nil); codec.err != nil {
+			return
+		}
+		codec.decoder, codec.err = zstd.NewReader(nil)
+	})
+}
+
+// Compress compresses the data using zstd.
+func (codec *zstdCodec) Compress(data []byte) ([]byte, error) {
+	if codec.init(); codec.err != nil {
+		return nil, codec.err
+	}
+	return codec.encoder.EncodeAll(data, nil), nil
+}
+
+// Decompress decompresses zstd compressed data.
+func (codec *zstdCodec) Decompress(data []byte) ([]byte, error) {
+	if codec.init(); codec.err != nil {
+		return nil, codec.err
+	}
+	return codec.decoder.DecodeAll(data, nil)
+}
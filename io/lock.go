@@ -0,0 +1,6 @@
+package io
+
+import "errors"
+
+// WorldInUse gets returned if a file of a world could not be locked, because another process holds the lock.
+var WorldInUse = errors.New("world is in use by another process")
@@ -0,0 +1,24 @@
+//go:build !windows
+// +build !windows
+
+package io
+
+import (
+	"os"
+	"syscall"
+)
+
+// LockFile places an exclusive advisory lock on the given file.
+// Returns WorldInUse if another process already holds a lock on it.
+func LockFile(file *os.File) error {
+	var err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == syscall.EWOULDBLOCK {
+		return WorldInUse
+	}
+	return err
+}
+
+// UnlockFile removes the advisory lock on the given file.
+func UnlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,51 @@
+//go:build windows
+// +build windows
+
+package io
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+const (
+	lockfileFailImmediately = 0x00000001
+	lockfileExclusiveLock   = 0x00000002
+
+	errorLockViolation syscall.Errno = 33
+)
+
+// lockRange returns the overlapped structure of the byte range locked in files.
+// Windows locks are mandatory, so the range lies far beyond the end of the file to not block reads and writes of its data.
+func lockRange() *syscall.Overlapped {
+	return &syscall.Overlapped{Offset: 0xffffffff, OffsetHigh: 0x7fffffff}
+}
+
+// LockFile places an exclusive lock on the given file using LockFileEx.
+// Returns WorldInUse if another process already holds a lock on it.
+func LockFile(file *os.File) error {
+	var result, _, err = procLockFileEx.Call(file.Fd(), lockfileExclusiveLock|lockfileFailImmediately, 0, 1, 0, uintptr(unsafe.Pointer(lockRange())))
+	if result != 0 {
+		return nil
+	}
+	if err == errorLockViolation {
+		return WorldInUse
+	}
+	return err
+}
+
+// UnlockFile removes the lock on the given file using UnlockFileEx.
+func UnlockFile(file *os.File) error {
+	var result, _, err = procUnlockFileEx.Call(file.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(lockRange())))
+	if result != 0 {
+		return nil
+	}
+	return err
+}
@@ -1,43 +1,130 @@
 package io
 
 import (
+	"errors"
 	"github.com/irmine/gonbt"
 	"github.com/irmine/worlds/chunks"
 )
 
+var (
+	// MissingLevel gets returned if the chunk NBT does not have a Level compound.
+	MissingLevel = errors.New("chunk NBT has no Level compound")
+	// MismatchingPosition gets returned if the position in the chunk NBT does not match the position it was read at.
+	MismatchingPosition = errors.New("chunk NBT position does not match the requested position")
+	// InvalidSection gets returned if a section in the chunk NBT has arrays of the wrong length.
+	InvalidSection = errors.New("chunk NBT has an invalid section")
+)
+
+// ValidateAnvilChunk checks if the given NBT compound is a structurally valid Anvil chunk at the given chunk X and Z.
+// GetAnvilChunkFromNBT should only be used on compounds that passed validation.
+func ValidateAnvilChunk(compound *gonbt.Compound, x, z int32) error {
+	var level = compound.GetCompound("Level")
+	if level == nil {
+		return MissingLevel
+	}
+	if level.GetInt("xPos", x) != x || level.GetInt("zPos", z) != z {
+		return MismatchingPosition
+	}
+	var sections = level.GetList("Sections", gonbt.TAG_Compound)
+	if sections == nil {
+		return nil
+	}
+	for _, tag := range sections.GetTags() {
+		section, ok := tag.(*gonbt.Compound)
+		if !ok {
+			return InvalidSection
+		}
+		if len(section.GetByteArray("Blocks", make([]byte, 4096))) != 4096 {
+			return InvalidSection
+		}
+		for _, name := range []string{"Data", "BlockLight", "SkyLight"} {
+			if len(section.GetByteArray(name, make([]byte, 2048))) != 2048 {
+				return InvalidSection
+			}
+		}
+	}
+	return nil
+}
+
 /**
  * Returns a new Anvil chunk from the given NBT compound.
  */
 func GetAnvilChunkFromNBT(compound *gonbt.Compound) *chunks.Chunk {
+	return GetAnvilChunkFromNBTWithRange(compound, chunks.DefaultSubChunkRange)
+}
+
+// GetAnvilChunkFromNBTWithRange returns a new Anvil chunk holding the given range of sub chunks from the given NBT compound.
+// The Y of sections is read as signed, and sections outside of the range are skipped.
+func GetAnvilChunkFromNBTWithRange(compound *gonbt.Compound, subChunkRange chunks.SubChunkRange) *chunks.Chunk {
 	var level = compound.GetCompound("Level")
-	var chunk = chunks.New(level.GetInt("xPos", 0), level.GetInt("zPos", 0))
+	var chunk = chunks.NewWithRange(level.GetInt("xPos", 0), level.GetInt("zPos", 0), subChunkRange)
 	chunk.LightPopulated = getBool(level.GetByte("LightPopulated", 0))
 	chunk.TerrainPopulated = getBool(level.GetByte("TerrainPopulated", 0))
 	chunk.Biomes = level.GetByteArray("Biomes", make([]byte, 256))
 	chunk.InhabitedTime = level.GetLong("InhabitedTime", 0)
 	chunk.LastUpdate = level.GetLong("LastUpdate", 0)
-	for i, b := range level.GetByteArray("HeightMap", make([]byte, 256)) {
-		chunk.HeightMap[i] = int16(b)
+	// Anvil stores the height map as ints, older writers stored it as bytes.
+	if heightMap := level.GetIntArray("HeightMap", nil); len(heightMap) == 256 {
+		for i, height := range heightMap {
+			chunk.HeightMap[i] = int16(height)
+		}
+	} else {
+		for i, b := range level.GetByteArray("HeightMap", make([]byte, 256)) {
+			chunk.HeightMap[i] = int16(b)
+		}
+	}
+
+	if entities := level.GetList("Entities", gonbt.TAG_Compound); entities != nil {
+		chunk.SetEntityNBT(entities)
+	}
+	if tileEntities := level.GetList("TileEntities", gonbt.TAG_Compound); tileEntities != nil {
+		readTileEntities(chunk, tileEntities)
+	}
+	if ticks := level.GetList("TileTicks", gonbt.TAG_Compound); ticks != nil {
+		chunk.SetRelativeScheduledTicks(readTileTicks(ticks))
 	}
 
 	var sections = level.GetList("Sections", gonbt.TAG_Compound)
 	if sections == nil {
+		chunk.SetDirty(false)
 		return chunk
 	}
 	for _, comp := range sections.GetTags() {
 		section := comp.(*gonbt.Compound)
+		var index = int(int8(section.GetByte("Y", 0)))
+		if !subChunkRange.ContainsIndex(index) {
+			continue
+		}
 		subChunk := chunks.NewSubChunk()
 		subChunk.BlockLight = reorderNibbleArray(section.GetByteArray("BlockLight", make([]byte, 2048)))
 		subChunk.BlockData = reorderNibbleArray(section.GetByteArray("Data", make([]byte, 2048)))
 		subChunk.SkyLight = reorderNibbleArray(section.GetByteArray("SkyLight", make([]byte, 2048)))
 		subChunk.BlockIds = reorderBlocks(section.GetByteArray("Blocks", make([]byte, 4096)))
+		subChunk.RecountBlocks()
 
-		chunk.SetSubChunk(section.GetByte("Y", 0), subChunk)
+		chunk.SetSubChunk(byte(index-int(subChunkRange.Min)), subChunk)
 	}
 
+	chunk.SetDirty(false)
 	return chunk
 }
 
+// readTileEntities sets the block NBT of the chunk from its TileEntities NBT.
+// Compounds without a position inside of the sub chunk range of the chunk are skipped.
+func readTileEntities(chunk *chunks.Chunk, list *gonbt.List) {
+	for _, tag := range list.GetTags() {
+		compound, ok := tag.(*gonbt.Compound)
+		if !ok || !compound.HasTag("x") || !compound.HasTag("y") || !compound.HasTag("z") {
+			continue
+		}
+		var y = int(compound.GetInt("y", 0))
+		if !chunk.GetSubChunkRange().ContainsY(y) {
+			continue
+		}
+		chunk.SetBlockNBTAt(int(compound.GetInt("x", 0))&15, y, int(compound.GetInt("z", 0))&15, compound)
+	}
+}
+
 func reorderBlocks(blocks []byte) []byte {
 	var data = make([]byte, 4096)
 	var i = 0
@@ -65,7 +152,7 @@ func reorderNibbleArray(arr []byte) []byte {
 				var j80 = j | 0x80
 				if arr[j] != 0 || arr[j80] != 0 {
 					data[i] = (arr[j80] << 4) | (arr[j] & 0x0f)
-					data[i|80] = (arr[j] >> 4) | (arr[j80] & 0xf0)
+					data[i|0x80] = (arr[j] >> 4) | (arr[j80] & 0xf0)
 				}
 				i++
 			}
@@ -6,6 +6,8 @@ import (
 	"io"
 	"math"
 	"os"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -28,6 +30,9 @@ const (
 	CompressionGzip CompressionType = 1
 	// Zlib compression is the main compression for region files in Minecraft.
 	CompressionZlib CompressionType = 2
+	// Zstd compression is not used by Minecraft itself, and must be opted into when writing chunks.
+	// It compresses chunks smaller and faster than zlib, but regions using it can not be read by other software.
+	CompressionZstd CompressionType = 5
 )
 
 // RegionHeader contains locations of chunks, as well as timestamps for these.
@@ -43,16 +48,23 @@ type Location struct {
 }
 
 // A region holds a reference to the attached file, and has a header containing information of the region.
+// All methods of a region are safe for concurrent use.
+// The header and file should not be accessed directly while the region is in use by other goroutines.
 type Region struct {
 	Header RegionHeader
 	File   *os.File
+
+	path    string
+	mutex   sync.RWMutex
+	sectors *sectorMap
+	locked  bool
 }
 
 // NewRegion returns a new region struct with data at the given path.
 // It does not load the header, and therefore OpenRegion is recommended for usage.
 func NewRegion(path string) (*Region, error) {
 	var file, err = os.OpenFile(path, os.O_RDWR, 0644)
-	return &Region{RegionHeader{}, file}, err
+	return &Region{RegionHeader{}, file, path, sync.RWMutex{}, newSectorMap(), false}, err
 }
 
 // OpenRegion opens a region at the given path.
@@ -63,26 +75,62 @@ func OpenRegion(path string) (*Region, error) {
 	return region, err
 }
 
+// OpenLockedRegion opens a region at the given path and places an advisory lock on it.
+// Returns WorldInUse if another process already has the region locked.
+// The lock is held until the region is closed.
+func OpenLockedRegion(path string) (*Region, error) {
+	var region, err = NewRegion(path)
+	if err != nil {
+		return region, err
+	}
+	if err := LockFile(region.File); err != nil {
+		region.File.Close()
+		return nil, err
+	}
+	region.locked = true
+	region.LoadHeader()
+	return region, nil
+}
+
 // GetChunkLocationIndex returns the chunk location index, where information of a chunk can be found.
 func GetChunkLocationIndex(x, z int32) int {
 	return int((x & 31) + (z&31)*32)
 }
 
-// Close closes the region file, cleans garbage and writes the region header.
-func (r *Region) Close(save bool) {
-	r.Save()
-	r.File.Close()
+// Close closes the region file.
+// If save is true, the region gets saved before closing, and any error saving it gets returned.
+// The region file is closed regardless of the error.
+func (r *Region) Close(save bool) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var err error
+	if save {
+		_, err = r.defragment()
+	}
+	r.closeFile()
+	return err
+}
+
+// Save saves the region file atomically, cleaning the garbage and writing the header.
+// The region gets written to a temporary file first, which then replaces the region file.
+// The region file is left untouched if an error is returned.
+func (r *Region) Save() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var _, err = r.defragment()
+	return err
 }
 
-// Save saves the region file, cleaning the garbage and writing the header.
-func (r *Region) Save() {
-	r.CleanGarbage()
-	r.WriteHeader()
+// GetPath returns the path of the region file, as the region was opened with.
+func (r *Region) GetPath() string {
+	return r.path
 }
 
 // LoadHeader loads the header of the region.
 // This includes the loading of timestamps and chunk locations.
 func (r *Region) LoadHeader() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	var buff = make([]byte, 8192)
 	r.File.Read(buff)
 
@@ -97,6 +145,7 @@ func (r *Region) LoadHeader() {
 
 		r.Header.Locations[i] = &Location{offset << 12, o & 0xff}
 	}
+	r.loadSectors()
 
 	var in int32 = 0
 	var buffer *bytes.Buffer
@@ -109,137 +158,433 @@ func (r *Region) LoadHeader() {
 }
 
 // CleanGarbage cleans all garbage of the region file.
-// This function
+// This function defragments the region, discarding its statistics.
 func (r *Region) CleanGarbage() {
-	var l int32
-	var lastOffset int64 = HeaderSize
+	r.Defragment()
+}
 
-	var fileBuffer = bytes.NewBuffer([]byte{})
+// DefragmentStats holds statistics of a defragmentation of one or more regions.
+type DefragmentStats struct {
+	// BytesReclaimed is the amount of bytes the region files shrunk.
+	BytesReclaimed int64
+	// ChunksMoved is the amount of chunks that got moved to a lower offset.
+	ChunksMoved int
+}
 
-	for i := 0; i < 1024; i++ {
-		loc := r.Header.Locations[i]
-		if loc.Offset == 0 {
-			continue
+// Defragment rebuilds the region file compactly, moving all chunks to the front of the file without holes.
+// The region gets written to a temporary file first, which then atomically replaces the region file,
+// so that a crash while defragmenting leaves the old region file intact.
+func (r *Region) Defragment() (DefragmentStats, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.defragment()
+}
+
+// defragment defragments the region without locking it.
+func (r *Region) defragment() (DefragmentStats, error) {
+	var stats DefragmentStats
+	var info, err = r.File.Stat()
+	if err != nil {
+		return stats, err
+	}
+	// The region file gets replaced by the temporary file, so its name can not be used as path.
+	temp, err := os.OpenFile(r.path+".tmp", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return stats, err
+	}
+	var abort = func(err error) (DefragmentStats, error) {
+		temp.Close()
+		os.Remove(temp.Name())
+		return DefragmentStats{}, err
+	}
+
+	var indices []int
+	for i, loc := range r.Header.Locations {
+		if loc.IsExistent() {
+			indices = append(indices, i)
 		}
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return r.Header.Locations[indices[i]].Offset < r.Header.Locations[indices[j]].Offset
+	})
 
-		b := make([]byte, 4)
-		r.File.ReadAt(b, int64(loc.Offset))
-		buffer := bytes.NewBuffer(b)
-		binary.Read(buffer, binary.BigEndian, &l)
+	var header = RegionHeader{Timestamps: r.Header.Timestamps}
+	for i := range header.Locations {
+		header.Locations[i] = &Location{}
+	}
+	var next int32 = HeaderSize / SectorSize
+	for _, i := range indices {
+		var loc = r.Header.Locations[i]
+		var data = make([]byte, loc.SectorLength*SectorSize)
+		if _, err := r.File.ReadAt(data, int64(loc.Offset)); err != nil && err != io.EOF {
+			return abort(err)
+		}
+		if _, err := temp.WriteAt(data, int64(next*SectorSize)); err != nil {
+			return abort(err)
+		}
+		if loc.Offset != next*SectorSize {
+			stats.ChunksMoved++
+		}
+		header.Locations[i] = &Location{next * SectorSize, loc.SectorLength}
+		next += loc.SectorLength
+	}
 
-		if l <= 0 {
-			loc.Offset = 0
-			continue
+	if _, err := temp.WriteAt(encodeHeader(&header), 0); err != nil {
+		return abort(err)
+	}
+	if err := temp.Sync(); err != nil {
+		return abort(err)
+	}
+	// Open files can not be renamed over on all platforms, so both files are closed before the rename.
+	if err := temp.Close(); err != nil {
+		os.Remove(temp.Name())
+		return DefragmentStats{}, err
+	}
+	r.closeFile()
+	if err := os.Rename(temp.Name(), r.path); err != nil {
+		os.Remove(temp.Name())
+		if openErr := r.openFile(); openErr != nil {
+			return DefragmentStats{}, openErr
 		}
+		return DefragmentStats{}, err
+	}
+	if err := r.openFile(); err != nil {
+		return DefragmentStats{}, err
+	}
+	r.Header = header
+	r.loadSectors()
 
-		data := make([]byte, l)
-		r.File.ReadAt(data, int64(loc.Offset)+4)
-		buffer.Write(data)
-		paddingLength := int32(math.Ceil(float64(buffer.Len())/SectorSize) * SectorSize)
-		buffer.Write(make([]byte, paddingLength-l))
+	if info.Size() > int64(next*SectorSize) {
+		stats.BytesReclaimed = info.Size() - int64(next*SectorSize)
+	}
+	return stats, nil
+}
 
-		fileBuffer.Write(buffer.Bytes())
-		lastOffset += int64(len(buffer.Bytes()))
+// closeFile unlocks the region file if the region holds a lock on it, and closes it.
+func (r *Region) closeFile() {
+	if r.locked {
+		UnlockFile(r.File)
 	}
+	r.File.Close()
+}
 
-	io.Copy(r.File, fileBuffer)
-	r.File.Truncate(lastOffset)
+// openFile opens the region file at the path of the region again, locking it if the region held a lock on it.
+func (r *Region) openFile() error {
+	var file, err = os.OpenFile(r.path, os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	if r.locked {
+		if err := LockFile(file); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	r.File = file
+	return nil
 }
 
 // WriteHeader writes the header to the file.
 // This includes the writing of locations and timestamps.
+// WriteHeader writes the header in place, Save should be used to write it atomically.
 func (r *Region) WriteHeader() {
+	r.mutex.Lock()
+	r.File.WriteAt(encodeHeader(&r.Header), 0)
+	r.loadSectors()
+	r.mutex.Unlock()
+}
+
+// encodeHeader returns the binary representation of the given region header.
+func encodeHeader(regionHeader *RegionHeader) []byte {
 	var header = bytes.NewBuffer([]byte{})
 	var offsets []int32
 	for i := 0; i < 1024; i++ {
-		offsetL := r.Header.Locations[i].SectorLength
-		offsetI := r.Header.Locations[i].Offset >> 12 << 8
+		offsetL := regionHeader.Locations[i].SectorLength
+		offsetI := regionHeader.Locations[i].Offset >> 12 << 8
 		offset := offsetI | offsetL
 		offsets = append(offsets, offset)
 	}
 	binary.Write(header, binary.BigEndian, offsets)
 
 	var timestamps = bytes.NewBuffer([]byte{})
-	binary.Write(timestamps, binary.BigEndian, r.Header.Timestamps[:])
+	binary.Write(timestamps, binary.BigEndian, regionHeader.Timestamps[:])
 
-	var headerBytes = append(header.Bytes(), timestamps.Bytes()...)
-	r.File.WriteAt(headerBytes, 0)
+	return append(header.Bytes(), timestamps.Bytes()...)
 }
 
 // GetLocation returns the location of a chunk with the given X and Z in the region.
+// The location returned should not be modified.
 func (r *Region) GetLocation(x, z int32) *Location {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.getLocation(x, z)
+}
+
+// getLocation returns the location of a chunk without locking the region.
+func (r *Region) getLocation(x, z int32) *Location {
 	return r.Header.Locations[GetChunkLocationIndex(x, z)]
 }
 
-// GetChunkData returns the chunk data of a chunk with the given X and Z in the region.
-// It also provides the compression type, in order to know how to decompress it.
-func (r *Region) GetChunkData(x, z int32) (compressionType CompressionType, chunkData []byte) {
-	var loc = r.GetLocation(x, z)
-	if loc.Offset == 0 {
-		return 0, []byte{}
+// ChunkHeader is the header prefixing the data of a chunk in the region file.
+type ChunkHeader struct {
+	// Offset is the offset of the chunk in the region file, pointing at its length.
+	Offset int32
+	// Length is the length of the compressed chunk data, excluding the compression type byte.
+	Length int32
+	// Compression is the compression type of the chunk data.
+	Compression CompressionType
+}
+
+// ReadChunkHeader reads the header of a chunk with the given X and Z in the region, without reading its data.
+// Returns UngeneratedChunk if the chunk was not generated, OutOfBoundsOffset if its sectors lie outside of the file,
+// and MismatchingLength if its length does not fit in its sectors.
+func (r *Region) ReadChunkHeader(x, z int32) (ChunkHeader, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.readChunkHeader(x, z)
+}
+
+// readChunkHeader reads the header of a chunk without locking the region.
+func (r *Region) readChunkHeader(x, z int32) (ChunkHeader, error) {
+	var loc = r.getLocation(x, z)
+	if !loc.IsExistent() {
+		return ChunkHeader{}, UngeneratedChunk
+	}
+	info, err := r.File.Stat()
+	if err != nil {
+		return ChunkHeader{}, err
+	}
+	var end = int64(loc.Offset) + int64(loc.SectorLength)*SectorSize
+	if loc.Offset < HeaderSize || int64(loc.Offset)+LengthOffset+1 > info.Size() {
+		return ChunkHeader{}, OutOfBoundsOffset
+	}
+	if end > info.Size() {
+		end = info.Size()
 	}
-	var buff = make([]byte, 5)
 
-	r.File.ReadAt(buff, int64(loc.Offset))
+	var buff = make([]byte, LengthOffset+1)
+	if _, err := r.File.ReadAt(buff, int64(loc.Offset)); err != nil {
+		return ChunkHeader{}, err
+	}
+	// The length includes the compression type byte, but not the length itself.
+	var length = int32(binary.BigEndian.Uint32(buff))
+	if length < 1 || int64(loc.Offset)+LengthOffset+int64(length) > end {
+		return ChunkHeader{}, MismatchingLength
+	}
+	return ChunkHeader{loc.Offset, length - 1, CompressionType(buff[LengthOffset])}, nil
+}
 
-	var buffer = bytes.NewBuffer(buff[:4])
+// OpenChunkReader returns the header of a chunk with the given X and Z in the region,
+// and a reader over its compressed data. The header is validated as in ReadChunkHeader.
+// The reader reads directly from the region file, and is only valid until the chunk or region is next written.
+func (r *Region) OpenChunkReader(x, z int32) (ChunkHeader, io.Reader, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var header, err = r.readChunkHeader(x, z)
+	if err != nil {
+		return header, nil, err
+	}
+	return header, io.NewSectionReader(r.File, int64(header.Offset)+LengthOffset+1, int64(header.Length)), nil
+}
 
-	var length int32
-	binary.Read(buffer, binary.BigEndian, &length)
-	compressionType = CompressionType(buff[4])
+// GetRawChunkData returns the compressed chunk data of a chunk with the given X and Z in the region.
+// It also provides the compression type, in order to know how to decompress it.
+// Returns empty data if the chunk was not generated or its data could not be read.
+func (r *Region) GetRawChunkData(x, z int32) (compressionType CompressionType, chunkData []byte) {
+	var data, header, err = r.readRawChunkData(x, z)
+	if err != nil {
+		return header.Compression, []byte{}
+	}
+	return header.Compression, data
+}
 
-	chunkData = make([]byte, length)
-	r.File.ReadAt(chunkData, int64(loc.Offset+5))
-	return
+// readRawChunkData reads the header and compressed data of a chunk, returning any error that occurred.
+func (r *Region) readRawChunkData(x, z int32) ([]byte, ChunkHeader, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var header, err = r.readChunkHeader(x, z)
+	if err != nil {
+		return nil, header, err
+	}
+	var data = make([]byte, header.Length)
+	if _, err := r.File.ReadAt(data, int64(header.Offset)+LengthOffset+1); err != nil && err != io.EOF {
+		return nil, header, err
+	}
+	return data, header, nil
+}
+
+// GetChunkData returns the decompressed chunk data of a chunk with the given X and Z in the region.
+// The data is decompressed with the codec registered for the compression type of the chunk.
+// Returns empty data if the chunk was not generated, or an error if its header or data could not be read.
+func (r *Region) GetChunkData(x, z int32) ([]byte, error) {
+	var data, header, err = r.readRawChunkData(x, z)
+	if err == UngeneratedChunk {
+		return []byte{}, nil
+	}
+	if err != nil || len(data) == 0 {
+		return data, err
+	}
+	return Decompress(header.Compression, data)
 }
 
 // WriteChunkData writes the given chunk data at the given X and Z.
-// Compression type should be CompressionZlib. (or CompressionGzip)
-func (r *Region) WriteChunkData(x, z int32, data []byte, compressionType byte) {
-	var loc = r.GetLocation(x, z)
-	var buff = make([]byte, LengthOffset)
+// Compression type should be CompressionZlib, or CompressionZstd if opted into. (or CompressionGzip)
+// The chunk is always written to free sectors, using the first hole big enough, and the header is written once the data is synced.
+// The old sectors of the chunk are only freed after the header was synced, so a crash while writing leaves the old chunk intact.
+// The header is left unchanged if an error is returned.
+func (r *Region) WriteChunkData(x, z int32, data []byte, compressionType byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 
-	r.File.ReadAt(buff, int64(loc.Offset))
+	// The length includes the compression type byte, but not the length itself.
+	var length = int32(len(data)) + 1
+	var sectorLength = int32(math.Ceil(float64(length+LengthOffset) / SectorSize))
 
-	var buffer = bytes.NewBuffer(buff[:4])
+	// The old sectors of the chunk are still marked as used, so the sectors found never overlap them.
+	var sector = r.sectors.find(sectorLength)
 
-	var oldLength int32
-	var newLength = int32(len(data)) + 5
-	binary.Read(buffer, binary.BigEndian, &oldLength)
-	oldLength += 4
-	var oldLengthPadded = int32(math.Ceil(float64(oldLength)/SectorSize) * SectorSize)
+	var buffer = bytes.NewBuffer([]byte{})
+	binary.Write(buffer, binary.BigEndian, length)
 
-	var sectorLength = int32(math.Ceil(float64(newLength) / SectorSize))
-	var newLengthPadded = sectorLength
+	buffer.WriteByte(compressionType)
+	buffer.Write(data)
+	buffer.Write(make([]byte, sectorLength*SectorSize-length-LengthOffset))
 
-	var padding []byte
-	if newLengthPadded-newLength > 0 {
-		padding = make([]byte, newLengthPadded-newLength)
+	if _, err := r.File.WriteAt(buffer.Bytes(), int64(sector*SectorSize)); err != nil {
+		return err
+	}
+	if err := r.File.Sync(); err != nil {
+		return err
 	}
 
-	var offset = loc.Offset
-	if newLengthPadded > oldLengthPadded {
-		d, _ := r.File.Stat()
-		offset = int32(d.Size())
-		loc.Offset = offset
+	var index = GetChunkLocationIndex(x, z)
+	var header = r.Header
+	header.Locations[index] = &Location{sector * SectorSize, sectorLength}
+	header.Timestamps[index] = int32(time.Now().Unix())
+	if _, err := r.File.WriteAt(encodeHeader(&header), 0); err != nil {
+		return err
+	}
+	if err := r.File.Sync(); err != nil {
+		return err
 	}
-	loc.SectorLength = sectorLength
+	*r.Header.Locations[index] = *header.Locations[index]
+	r.Header.Timestamps[index] = header.Timestamps[index]
+	r.loadSectors()
+	return nil
+}
 
-	r.Header.Timestamps[GetChunkLocationIndex(x, z)] = int32(time.Now().Unix())
+// WriteCompressedChunkData compresses the given chunk data with the codec of the compression type,
+// and writes it at the given X and Z.
+// Returns UnknownCompression if no codec was registered for the compression type.
+func (r *Region) WriteCompressedChunkData(x, z int32, data []byte, compressionType CompressionType) error {
+	var compressed, err = Compress(compressionType, data)
+	if err != nil {
+		return err
+	}
+	return r.WriteChunkData(x, z, compressed, byte(compressionType))
+}
 
-	buffer = bytes.NewBuffer([]byte{})
-	binary.Write(buffer, binary.BigEndian, newLength)
+// loadSectors rebuilds the sector map from the chunk locations in the header.
+func (r *Region) loadSectors() {
+	r.sectors = newSectorMap()
+	for _, loc := range r.Header.Locations {
+		if loc.IsExistent() {
+			r.sectors.allocate(loc.Offset/SectorSize, loc.SectorLength)
+		}
+	}
+}
 
-	buffer.WriteByte(compressionType)
-	buffer.Write(data)
-	buffer.Write(padding)
+// ForEach calls the given function for every generated chunk in the region, in the order of the header.
+// The chunk X and Z passed are local to the region, ranging from 0 to 31.
+// Iteration stops as soon as the function returns false.
+// The data passed is decompressed, or nil with the error if it could not be decompressed.
+// The region is not locked while the function runs, so it may modify the region.
+func (r *Region) ForEach(function func(x, z int32, data []byte, err error) bool) {
+	for _, coordinates := range r.GetGeneratedChunks() {
+		var x, z = coordinates[0], coordinates[1]
+		var data, err = r.GetChunkData(x, z)
+		if err == nil && len(data) == 0 {
+			continue
+		}
+		if !function(x, z, data, err) {
+			return
+		}
+	}
+}
+
+// GetGeneratedChunks returns the local chunk X and Z of every generated chunk in the region.
+func (r *Region) GetGeneratedChunks() [][2]int32 {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var coordinates [][2]int32
+	for i, loc := range r.Header.Locations {
+		if loc.IsExistent() {
+			coordinates = append(coordinates, [2]int32{int32(i & 31), int32(i >> 5)})
+		}
+	}
+	return coordinates
+}
+
+// LastSaved returns the time the chunk with the given X and Z was last written to the region.
+// Returns the zero time if the chunk was never written.
+func (r *Region) LastSaved(x, z int32) time.Time {
+	r.mutex.RLock()
+	var timestamp = r.Header.Timestamps[GetChunkLocationIndex(x, z)]
+	r.mutex.RUnlock()
+	if timestamp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(timestamp), 0)
+}
+
+// PruneOlderThan removes all chunks in the region that were last written before the given time.
+// The sectors of pruned chunks get freed once the region is saved.
+// Returns the amount of chunks pruned.
+func (r *Region) PruneOlderThan(t time.Time) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var pruned = 0
+	for i, loc := range r.Header.Locations {
+		if !loc.IsExistent() || int64(r.Header.Timestamps[i]) >= t.Unix() {
+			continue
+		}
+		r.removeChunk(i)
+		pruned++
+	}
+	return pruned
+}
+
+// DeleteChunk deletes the chunk with the given X and Z from the region.
+// The location and timestamp of the chunk are zeroed, and the header gets written so that its sectors can be freed.
+// Returns false if the chunk was not generated.
+func (r *Region) DeleteChunk(x, z int32) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var index = GetChunkLocationIndex(x, z)
+	if !r.Header.Locations[index].IsExistent() {
+		return false
+	}
+	r.removeChunk(index)
+	r.File.WriteAt(encodeHeader(&r.Header), 0)
+	r.loadSectors()
+	return true
+}
 
-	r.File.WriteAt(buffer.Bytes(), int64(offset))
+// removeChunk removes the chunk at the given location index from the header.
+// Its sectors are freed once the header is written.
+// The region must be locked when calling.
+func (r *Region) removeChunk(index int) {
+	var loc = r.Header.Locations[index]
+	loc.Offset = 0
+	loc.SectorLength = 0
+	r.Header.Timestamps[index] = 0
 }
 
 // HasChunkGenerated checks if the region has a chunk with the given X and Z generated.
 func (r *Region) HasChunkGenerated(x, z int32) bool {
-	return r.GetLocation(x, z).IsExistent()
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.getLocation(x, z).IsExistent()
 }
 
 // Checks if the location is existent.
@@ -0,0 +1,85 @@
+package io
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// newTestRegion returns a region in a temporary file holding a zeroed header and the given sectors of data.
+// The chunk locations are set directly, as the header of the file is empty.
+func newTestRegion(t *testing.T, sectors [][]byte, locations map[int]*Location) *Region {
+	t.Helper()
+	var file, err = ioutil.TempFile("", "region")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data = make([]byte, HeaderSize)
+	for _, sector := range sectors {
+		var padded = make([]byte, SectorSize)
+		copy(padded, sector)
+		data = append(data, padded...)
+	}
+	file.Write(data)
+	file.Close()
+
+	region, err := OpenRegion(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for index, location := range locations {
+		region.Header.Locations[index] = location
+	}
+	return region
+}
+
+// chunkSector returns a sector starting with the given chunk length and compression type.
+func chunkSector(length int32, compression CompressionType) []byte {
+	var sector = make([]byte, LengthOffset+1)
+	binary.BigEndian.PutUint32(sector, uint32(length))
+	sector[LengthOffset] = byte(compression)
+	return sector
+}
+
+func TestReadChunkHeader(t *testing.T) {
+	var region = newTestRegion(t, [][]byte{
+		chunkSector(5, CompressionZlib),
+		chunkSector(0, CompressionZlib),
+		chunkSector(5000, CompressionZlib),
+	}, map[int]*Location{
+		GetChunkLocationIndex(0, 0): {HeaderSize, 1},
+		GetChunkLocationIndex(1, 0): {HeaderSize + SectorSize, 1},
+		GetChunkLocationIndex(2, 0): {HeaderSize + SectorSize*2, 1},
+		GetChunkLocationIndex(3, 0): {HeaderSize + SectorSize*5, 1},
+		GetChunkLocationIndex(4, 0): {0, 0},
+		GetChunkLocationIndex(5, 0): {HeaderSize + SectorSize*2, 4},
+		GetChunkLocationIndex(6, 0): {SectorSize, 1},
+	})
+	defer os.Remove(region.GetPath())
+	defer region.Close(false)
+
+	var tests = []struct {
+		name     string
+		x        int32
+		header   ChunkHeader
+		expected error
+	}{
+		{"valid", 0, ChunkHeader{HeaderSize, 4, CompressionZlib}, nil},
+		{"zero length", 1, ChunkHeader{}, MismatchingLength},
+		{"length past sectors", 2, ChunkHeader{}, MismatchingLength},
+		{"offset past file", 3, ChunkHeader{}, OutOfBoundsOffset},
+		{"ungenerated", 4, ChunkHeader{}, UngeneratedChunk},
+		{"sectors past file", 5, ChunkHeader{}, MismatchingLength},
+		{"offset in header", 6, ChunkHeader{}, UngeneratedChunk},
+	}
+	for _, test := range tests {
+		var header, err = region.ReadChunkHeader(test.x, 0)
+		if err != test.expected {
+			t.Errorf("%v: got error %v, expected %v", test.name, err, test.expected)
+		}
+		if header != test.header {
+			t.Errorf("%v: got header %+v, expected %+v", test.name, header, test.header)
+		}
+	}
+}
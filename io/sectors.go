@@ -0,0 +1,57 @@
+package io
+
+// sectorMap is a bitmap keeping track of which sectors of a region file are in use.
+// Sectors left behind by chunks that shrunk or moved become free once the header is written, so that later writes can reuse them.
+type sectorMap struct {
+	words []uint64
+}
+
+// newSectorMap returns a new sector map with the sectors of the region header marked as used.
+func newSectorMap() *sectorMap {
+	var sectors = &sectorMap{}
+	sectors.allocate(0, HeaderSize/SectorSize)
+	return sectors
+}
+
+// isUsed checks if the sector with the given index is in use.
+// Sectors past the end of the map are always free.
+func (sectors *sectorMap) isUsed(sector int32) bool {
+	var word = int(sector >> 6)
+	if word >= len(sectors.words) {
+		return false
+	}
+	return sectors.words[word]&(1<<uint(sector&63)) != 0
+}
+
+// allocate marks count sectors starting at the given sector as used.
+func (sectors *sectorMap) allocate(sector, count int32) {
+	for i := sector; i < sector+count; i++ {
+		var word = int(i >> 6)
+		for word >= len(sectors.words) {
+			sectors.words = append(sectors.words, 0)
+		}
+		sectors.words[word] |= 1 << uint(i&63)
+	}
+}
+
+// find returns the first sector of a run of count free sectors.
+// If no hole is big enough, the first sector after the last used one is returned.
+func (sectors *sectorMap) find(count int32) int32 {
+	if count <= 0 {
+		return 0
+	}
+	var start, run int32
+	for i := int32(HeaderSize / SectorSize); ; i++ {
+		if sectors.isUsed(i) {
+			run = 0
+			continue
+		}
+		if run == 0 {
+			start = i
+		}
+		run++
+		if run == count {
+			return start
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package io
+
+import (
+	"testing"
+)
+
+func TestSectorMapHeader(t *testing.T) {
+	var sectors = newSectorMap()
+	for sector := int32(0); sector < HeaderSize/SectorSize; sector++ {
+		if !sectors.isUsed(sector) {
+			t.Errorf("header sector %v is not used", sector)
+		}
+	}
+	if sectors.isUsed(HeaderSize / SectorSize) {
+		t.Error("first sector after the header is used")
+	}
+	if sectors.isUsed(1 << 20) {
+		t.Error("sector past the end of the map is used")
+	}
+}
+
+func TestSectorMapFind(t *testing.T) {
+	var tests = []struct {
+		name      string
+		allocated [][2]int32
+		count     int32
+		expected  int32
+	}{
+		{"empty", nil, 1, 2},
+		{"empty run", nil, 5, 2},
+		{"zero count", [][2]int32{{2, 3}}, 0, 0},
+		{"after used", [][2]int32{{2, 3}}, 1, 5},
+		{"exact hole", [][2]int32{{2, 1}, {4, 2}}, 1, 3},
+		{"hole too small", [][2]int32{{2, 1}, {4, 2}}, 2, 6},
+		{"later hole", [][2]int32{{2, 1}, {4, 1}, {8, 1}}, 3, 5},
+		{"word boundary", [][2]int32{{2, 60}, {66, 1}}, 5, 67},
+		{"across words", [][2]int32{{2, 60}}, 10, 62},
+	}
+	for _, test := range tests {
+		var sectors = newSectorMap()
+		for _, allocation := range test.allocated {
+			sectors.allocate(allocation[0], allocation[1])
+		}
+		if sector := sectors.find(test.count); sector != test.expected {
+			t.Errorf("%v: got sector %v, expected %v", test.name, sector, test.expected)
+		}
+	}
+}
+
+func TestSectorMapAllocate(t *testing.T) {
+	var sectors = newSectorMap()
+	sectors.allocate(62, 5)
+	for sector := int32(60); sector < 70; sector++ {
+		var expected = sector >= 62 && sector < 67
+		if used := sectors.isUsed(sector); used != expected {
+			t.Errorf("sector %v: got used %v, expected %v", sector, used, expected)
+		}
+	}
+	if len(sectors.words) != 2 {
+		t.Errorf("got %v words, expected 2", len(sectors.words))
+	}
+}
@@ -0,0 +1,44 @@
+package io
+
+import (
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/chunks"
+)
+
+// readTileTicks reads the scheduled ticks of a chunk from its TileTicks NBT.
+// The ticks returned are relative to the moment the chunk gets loaded.
+func readTileTicks(list *gonbt.List) []chunks.ScheduledTick {
+	var ticks = make([]chunks.ScheduledTick, 0, len(list.GetTags()))
+	for _, tag := range list.GetTags() {
+		compound, ok := tag.(*gonbt.Compound)
+		if !ok {
+			continue
+		}
+		ticks = append(ticks, chunks.ScheduledTick{
+			X:        int(compound.GetInt("x", 0)) & 15,
+			Y:        int(compound.GetInt("y", 0)),
+			Z:        int(compound.GetInt("z", 0)) & 15,
+			BlockId:  byte(compound.GetInt("i", 0)),
+			Tick:     int64(compound.GetInt("t", 0)),
+			Priority: compound.GetInt("p", 0),
+		})
+	}
+	return ticks
+}
+
+// GetTileTicksNBT returns the TileTicks NBT of the scheduled ticks of the chunk, used to persist them.
+// The delay of every tick is stored relative to the given current tick.
+func GetTileTicksNBT(chunk *chunks.Chunk, currentTick int64) *gonbt.List {
+	var tags []gonbt.INamedTag
+	for _, tick := range chunk.GetRelativeScheduledTicks(currentTick) {
+		tags = append(tags, gonbt.NewCompound("", map[string]gonbt.INamedTag{
+			"i": gonbt.NewInt("i", int32(tick.BlockId)),
+			"x": gonbt.NewInt("x", chunk.X<<4|int32(tick.X)),
+			"y": gonbt.NewInt("y", int32(tick.Y)),
+			"z": gonbt.NewInt("z", chunk.Z<<4|int32(tick.Z)),
+			"t": gonbt.NewInt("t", int32(tick.Tick)),
+			"p": gonbt.NewInt("p", tick.Priority),
+		}))
+	}
+	return gonbt.NewList("TileTicks", gonbt.TAG_Compound, tags)
+}
@@ -0,0 +1,111 @@
+package io
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+var (
+	// OutOfBoundsOffset is found if the sectors of a chunk lie outside of the region file or inside of the header.
+	OutOfBoundsOffset = errors.New("chunk sectors are out of file bounds")
+	// OverlappingSectors is found if a chunk uses sectors that are also used by another chunk.
+	OverlappingSectors = errors.New("chunk sectors overlap with another chunk")
+	// MismatchingLength is found if the stored chunk length does not match the sector length in the header.
+	MismatchingLength = errors.New("chunk length does not match sector length")
+	// UnknownCompression is found if the compression type of a chunk is not known.
+	UnknownCompression = errors.New("chunk has unknown compression type")
+	// UngeneratedChunk gets returned if a chunk read was never generated.
+	UngeneratedChunk = errors.New("chunk was not generated")
+	// InvalidTimestamp is found if the timestamp of a chunk is missing, lies in the future or exists for a non-existent chunk.
+	InvalidTimestamp = errors.New("chunk has invalid timestamp")
+)
+
+// IntegrityIssue is an issue found in the region file for the chunk at the local X and Z.
+type IntegrityIssue struct {
+	X, Z int32
+	Err  error
+}
+
+// IntegrityReport is the result of a verification of a region.
+type IntegrityReport struct {
+	// ChunksChecked is the amount of existent chunks that were checked.
+	ChunksChecked int
+	// Issues are all issues found in the region.
+	Issues []IntegrityIssue
+}
+
+// IsValid checks if no issues were found in the region.
+func (report IntegrityReport) IsValid() bool {
+	return len(report.Issues) == 0
+}
+
+// add adds an issue for the chunk at the given location index.
+func (report *IntegrityReport) add(index int, err error) {
+	report.Issues = append(report.Issues, IntegrityIssue{int32(index & 31), int32(index >> 5), err})
+}
+
+// Verify validates the header of the region against the region file.
+// It checks if offsets are within file bounds, sectors don't overlap,
+// sector lengths match the stored chunk lengths and if timestamps are sane.
+// Verify does not modify the region.
+func (r *Region) Verify() (IntegrityReport, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var report IntegrityReport
+	var info, err = r.File.Stat()
+	if err != nil {
+		return report, err
+	}
+	var sectorCount = int32((info.Size() + SectorSize - 1) / SectorSize)
+	var used = make(map[int32]bool)
+	var maxTimestamp = int32(time.Now().Add(24 * time.Hour).Unix())
+
+	for i, loc := range r.Header.Locations {
+		var timestamp = r.Header.Timestamps[i]
+		if !loc.IsExistent() {
+			if loc.Offset != 0 || loc.SectorLength != 0 {
+				report.add(i, OutOfBoundsOffset)
+			} else if timestamp != 0 {
+				report.add(i, InvalidTimestamp)
+			}
+			continue
+		}
+		report.ChunksChecked++
+
+		var sector = loc.Offset / SectorSize
+		if sector+loc.SectorLength > sectorCount {
+			report.add(i, OutOfBoundsOffset)
+			continue
+		}
+		var overlapping = false
+		for s := sector; s < sector+loc.SectorLength; s++ {
+			if used[s] {
+				overlapping = true
+			}
+			used[s] = true
+		}
+		if overlapping {
+			report.add(i, OverlappingSectors)
+		}
+
+		var buff = make([]byte, LengthOffset+1)
+		if _, err := r.File.ReadAt(buff, int64(loc.Offset)); err != nil {
+			report.add(i, OutOfBoundsOffset)
+			continue
+		}
+		var length int32
+		binary.Read(bytes.NewBuffer(buff[:LengthOffset]), binary.BigEndian, &length)
+		if length <= 0 || (length+LengthOffset+SectorSize-1)/SectorSize > loc.SectorLength {
+			report.add(i, MismatchingLength)
+		}
+		if _, ok := GetCodec(CompressionType(buff[LengthOffset])); !ok {
+			report.add(i, UnknownCompression)
+		}
+		if timestamp <= 0 || timestamp > maxTimestamp {
+			report.add(i, InvalidTimestamp)
+		}
+	}
+	return report, nil
+}
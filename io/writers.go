@@ -0,0 +1,113 @@
+package io
+
+import (
+	"github.com/irmine/binutils"
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/chunks"
+)
+
+// GetAnvilNBTFromChunk returns the Anvil NBT compound of the given chunk, which can be read back using GetAnvilChunkFromNBTWithRange.
+// Every sub chunk is written as a section with its signed sub chunk index as Y.
+// Scheduled ticks are written to TileTicks relative to the given current tick, entities of the chunk are written to Entities,
+// and block NBT is written to TileEntities with the world position of the block.
+func GetAnvilNBTFromChunk(chunk *chunks.Chunk, currentTick int64) *gonbt.Compound {
+	var heightMap = make([]int32, 256)
+	chunk.RLock()
+	for i, height := range chunk.HeightMap {
+		heightMap[i] = int32(height)
+	}
+	var biomes = append([]byte{}, chunk.Biomes...)
+	chunk.RUnlock()
+	var level = gonbt.NewCompound("Level", map[string]gonbt.INamedTag{
+		"xPos":             gonbt.NewInt("xPos", chunk.X),
+		"zPos":             gonbt.NewInt("zPos", chunk.Z),
+		"LightPopulated":   gonbt.NewByte("LightPopulated", getByte(chunk.LightPopulated)),
+		"TerrainPopulated": gonbt.NewByte("TerrainPopulated", getByte(chunk.TerrainPopulated)),
+		"InhabitedTime":    gonbt.NewLong("InhabitedTime", chunk.InhabitedTime),
+		"LastUpdate":       gonbt.NewLong("LastUpdate", chunk.LastUpdate),
+		"Biomes":           gonbt.NewByteArray("Biomes", biomes),
+		"HeightMap":        gonbt.NewIntArray("HeightMap", heightMap),
+	})
+	level.SetTag(getSectionsNBT(chunk))
+	level.SetTag(chunk.EntitiesToNBT())
+	level.SetTag(getTileEntitiesNBT(chunk))
+	level.SetTag(GetTileTicksNBT(chunk, currentTick))
+	return gonbt.NewCompound("", map[string]gonbt.INamedTag{
+		"Level": level,
+	})
+}
+
+// EncodeAnvilChunk returns the uncompressed big endian NBT of the given chunk, ready to be written to a region.
+// See GetAnvilNBTFromChunk.
+func EncodeAnvilChunk(chunk *chunks.Chunk, currentTick int64) []byte {
+	var writer = gonbt.NewWriter(false, binutils.BigEndian)
+	writer.WriteUncompressedCompound(GetAnvilNBTFromChunk(chunk, currentTick))
+	return writer.GetData()
+}
+
+// getSectionsNBT returns the Sections NBT of all sub chunks of the chunk, ordered by their Y.
+func getSectionsNBT(chunk *chunks.Chunk) *gonbt.List {
+	var subChunkRange = chunk.GetSubChunkRange()
+	var tags []gonbt.INamedTag
+	chunk.RangeSubChunks(func(key byte, subChunk *chunks.SubChunk) {
+		tags = append(tags, gonbt.NewCompound("", map[string]gonbt.INamedTag{
+			"Y":          gonbt.NewByte("Y", byte(int8(int(key)+int(subChunkRange.Min)))),
+			"Blocks":     gonbt.NewByteArray("Blocks", reorderBlocksToAnvil(subChunk.BlockIds)),
+			"Data":       gonbt.NewByteArray("Data", reorderNibbleArrayToAnvil(subChunk.BlockData)),
+			"BlockLight": gonbt.NewByteArray("BlockLight", reorderNibbleArrayToAnvil(subChunk.BlockLight)),
+			"SkyLight":   gonbt.NewByteArray("SkyLight", reorderNibbleArrayToAnvil(subChunk.SkyLight)),
+		}))
+	})
+	return gonbt.NewList("Sections", gonbt.TAG_Compound, tags)
+}
+
+// getTileEntitiesNBT returns the TileEntities NBT of all block NBT in the chunk.
+// Every compound is copied with the world position of its block set, regardless of the position it held.
+func getTileEntitiesNBT(chunk *chunks.Chunk) *gonbt.List {
+	var blockNBT = chunk.GetBlockNBT()
+	var tags = make([]gonbt.INamedTag, 0, len(blockNBT))
+	for key, compound := range blockNBT {
+		var tag = chunks.CopyCompound(compound)
+		tag.SetTag(gonbt.NewInt("x", chunk.X<<4|int32(key.X)))
+		tag.SetTag(gonbt.NewInt("y", int32(key.Y)))
+		tag.SetTag(gonbt.NewInt("z", chunk.Z<<4|int32(key.Z)))
+		tags = append(tags, tag)
+	}
+	return gonbt.NewList("TileEntities", gonbt.TAG_Compound, tags)
+}
+
+// reorderBlocksToAnvil reorders the block IDs of a sub chunk from XZY order to the YZX order of Anvil sections.
+// It is the inverse of reorderBlocks.
+func reorderBlocksToAnvil(blocks []byte) []byte {
+	var data = make([]byte, 4096)
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := 0; y < 16; y++ {
+				data[y<<8|z<<4|x] = blocks[x<<8|z<<4|y]
+			}
+		}
+	}
+	return data
+}
+
+// reorderNibbleArrayToAnvil reorders a nibble array of a sub chunk from XZY order to the YZX order of Anvil sections.
+// It is the inverse of reorderNibbleArray.
+func reorderNibbleArrayToAnvil(arr []byte) []byte {
+	var data = make([]byte, 2048)
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := 0; y < 16; y++ {
+				var nibble = (arr[x<<7|z<<3|y>>1] >> (uint(y&1) << 2)) & 0x0f
+				data[(y<<8|z<<4|x)>>1] |= nibble << (uint(x&1) << 2)
+			}
+		}
+	}
+	return data
+}
+
+func getByte(value bool) byte {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -1,10 +1,17 @@
 package worlds
 
 import (
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/io"
+	"math"
 	"os"
 	"sync"
+	"time"
 )
 
+// DayLength is the amount of ticks a full day and night cycle takes.
+const DayLength = 24000
+
 // Level is a struct that manages an unlimited set of dimensions.
 // Every level has its own set of game rules.
 type Level struct {
@@ -13,22 +20,69 @@ type Level struct {
 	defaultDimension *Dimension
 
 	currentTick int64
+	time        int64
+	difficulty  Difficulty
 
 	mutex      sync.RWMutex
 	dimensions map[string]*Dimension
 	gameRules  map[GameRuleName]*GameRule
+
+	// deferredDimensions are the names of the dimensions that were not ticked last tick, because the deadline passed.
+	deferredDimensions map[string]bool
+
+	sessionLock *os.File
+
+	data    *gonbt.Compound
+	dataErr error
 }
 
 // NewLevel returns a new level with the given level name and server path.
 // World data will be generated in: `serverPath/worlds/`
+// The level data of the level is loaded from its level.dat file if it has one.
 func NewLevel(levelName string, serverPath string) *Level {
-	var level = &Level{levelName, serverPath, nil, 0, sync.RWMutex{}, make(map[string]*Dimension), make(map[GameRuleName]*GameRule)}
+	var level = &Level{levelName, serverPath, nil, 0, 0, DifficultyNormal, sync.RWMutex{}, make(map[string]*Dimension), make(map[GameRuleName]*GameRule), make(map[string]bool), nil, newLevelData(), nil}
 	os.MkdirAll(serverPath+"worlds/"+levelName, 0700)
+	level.LoadData()
 
 	level.initializeGameRules()
 	return level
 }
 
+// AcquireLock places an advisory lock on the `session.lock` file of the level,
+// preventing other processes from opening the same level.
+// The level data is loaded again once the level is locked, as it may have been written by another process before.
+// Returns io.WorldInUse if another process already has the level locked, or UnreadableLevelData if the level data could not be read,
+// in which case the lock is released again.
+func (level *Level) AcquireLock() error {
+	if level.sessionLock != nil {
+		return nil
+	}
+	var file, err = os.OpenFile(level.serverPath+"worlds/"+level.name+"/session.lock", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if err := io.LockFile(file); err != nil {
+		file.Close()
+		return err
+	}
+	level.sessionLock = file
+	if err := level.LoadData(); err != nil {
+		level.ReleaseLock()
+		return err
+	}
+	return nil
+}
+
+// ReleaseLock releases the advisory lock on the level, if held.
+func (level *Level) ReleaseLock() {
+	if level.sessionLock == nil {
+		return
+	}
+	io.UnlockFile(level.sessionLock)
+	level.sessionLock.Close()
+	level.sessionLock = nil
+}
+
 // GetGameRule returns a game rule with the given name.
 func (level *Level) GetGameRule(gameRule GameRuleName) *GameRule {
 	level.mutex.RLock()
@@ -36,6 +90,17 @@ func (level *Level) GetGameRule(gameRule GameRuleName) *GameRule {
 	return level.gameRules[gameRule]
 }
 
+// GetBoolGameRule returns the value of the bool game rule with the given name.
+// The default value is returned if the level does not have the game rule, or if its value is not a bool.
+func (level *Level) GetBoolGameRule(gameRule GameRuleName, def bool) bool {
+	if rule := level.GetGameRule(gameRule); rule != nil {
+		if value, ok := rule.GetValue().(bool); ok {
+			return value
+		}
+	}
+	return def
+}
+
 // GetGameRules returns all game rules of the level in a name => game rule map.
 func (level *Level) GetGameRules() map[GameRuleName]*GameRule {
 	level.mutex.RLock()
@@ -115,8 +180,42 @@ func (level *Level) GetDimension(name string) (*Dimension, bool) {
 
 // Tick ticks the level, ticking all dimensions and their contents.
 func (level *Level) Tick() {
+	level.TickUntil(time.Time{})
+}
+
+// TickUntil ticks the level, ticking its dimensions and their contents as long as the deadline has not passed.
+// Dimensions that were not ticked because the deadline passed get deferred to the next tick,
+// in which they are ticked first regardless of the deadline, so that every dimension gets ticked at least every other tick.
+// A zero deadline ticks all dimensions.
+func (level *Level) TickUntil(deadline time.Time) {
 	level.currentTick++
-	for _, dimension := range level.dimensions {
+	if level.GetBoolGameRule(GameRuleDoDaylightCycle, true) {
+		level.time++
+	}
+	level.mutex.Lock()
+	var deferred = level.deferredDimensions
+	level.deferredDimensions = make(map[string]bool)
+	var dimensions = make(map[string]*Dimension, len(level.dimensions))
+	for name, dimension := range level.dimensions {
+		dimensions[name] = dimension
+	}
+	level.mutex.Unlock()
+
+	for name := range deferred {
+		if dimension, ok := dimensions[name]; ok {
+			dimension.Tick()
+		}
+	}
+	for name, dimension := range dimensions {
+		if deferred[name] {
+			continue
+		}
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			level.mutex.Lock()
+			level.deferredDimensions[name] = true
+			level.mutex.Unlock()
+			continue
+		}
 		dimension.Tick()
 	}
 }
@@ -126,6 +225,46 @@ func (level *Level) GetCurrentTick() int64 {
 	return level.currentTick
 }
 
+// GetTime returns the time of the level in ticks.
+// The time of day can be found by taking the time modulo DayLength.
+func (level *Level) GetTime() int64 {
+	return level.time
+}
+
+// SetTime sets the time of the level in ticks.
+func (level *Level) SetTime(time int64) {
+	level.time = time
+}
+
+// GetDifficulty returns the difficulty of the level.
+func (level *Level) GetDifficulty() Difficulty {
+	return level.difficulty
+}
+
+// SetDifficulty sets the difficulty of the level.
+func (level *Level) SetDifficulty(difficulty Difficulty) {
+	level.difficulty = difficulty
+}
+
+// GetCelestialAngle returns the angle of the sun in the sky, ranging from 0 to 1.
+// 0 is noon, 0.5 is midnight.
+func (level *Level) GetCelestialAngle() float64 {
+	var angle = float64(level.time%DayLength)/DayLength - 0.25
+	if angle < 0 {
+		angle++
+	}
+	var smoothed = 1 - (math.Cos(angle*math.Pi)+1)/2
+	return angle + (smoothed-angle)/3
+}
+
+// GetSkyLightSubtracted returns the amount of sky light subtracted at the current time of day.
+// It ranges from 0 at day to 11 at night.
+func (level *Level) GetSkyLightSubtracted() byte {
+	var factor = 1 - (math.Cos(level.GetCelestialAngle()*2*math.Pi)*2 + 0.5)
+	factor = math.Max(0, math.Min(1, factor))
+	return byte(factor * 11)
+}
+
 // initializeGameRules initializes all game rules of the level, setting them to their default values.
 func (level *Level) initializeGameRules() {
 	level.AddGameRule(NewGameRule(GameRuleCommandBlockOutput, true))
@@ -0,0 +1,133 @@
+package worlds
+
+import (
+	"errors"
+	"github.com/irmine/binutils"
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/io"
+	"io/ioutil"
+	"os"
+)
+
+// UnreadableLevelData gets returned when the level.dat file of a level could not be read.
+var UnreadableLevelData = errors.New("level data could not be read")
+
+// newLevelData returns new empty level data, holding an empty Data compound.
+func newLevelData() *gonbt.Compound {
+	return gonbt.NewCompound("", map[string]gonbt.INamedTag{
+		"Data": gonbt.NewCompound("Data", make(map[string]gonbt.INamedTag)),
+	})
+}
+
+// getLevelDataPath returns the path of the level.dat file of the level.
+func (level *Level) getLevelDataPath() string {
+	return level.serverPath + "worlds/" + level.name + "/level.dat"
+}
+
+// LoadData loads the level data of the level from its gzip compressed level.dat file, such as the spawns of its dimensions.
+// Nothing is loaded if the level has no level.dat file yet. Returns UnreadableLevelData if the file could not be read,
+// in which case the level data does not get saved until it was loaded successfully, so that the file is not overwritten.
+func (level *Level) LoadData() error {
+	var err = level.loadData()
+	level.mutex.Lock()
+	level.dataErr = err
+	level.mutex.Unlock()
+	return err
+}
+
+// loadData loads the level data of the level from its level.dat file.
+func (level *Level) loadData() error {
+	var data, err = ioutil.ReadFile(level.getLevelDataPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if data, err = io.Decompress(io.CompressionGzip, data); err != nil {
+		return UnreadableLevelData
+	}
+	var compound = readLevelData(data)
+	if compound == nil || compound.GetCompound("Data") == nil {
+		return UnreadableLevelData
+	}
+	level.mutex.Lock()
+	level.data = compound
+	level.mutex.Unlock()
+	return nil
+}
+
+// readLevelData reads the decompressed level data into a compound, returning nil if it could not be read.
+func readLevelData(data []byte) (compound *gonbt.Compound) {
+	defer func() {
+		if recover() != nil {
+			compound = nil
+		}
+	}()
+	return gonbt.NewReader(data, false, binutils.BigEndian).ReadUncompressedIntoCompound()
+}
+
+// SaveData saves the level data of the level to its level.dat file, gzip compressed.
+// The file is written atomically through a temporary file, so that a crash never leaves it half written.
+// Tags of the level data not handled by the level are kept, so they get saved again.
+// Returns the error of loading the level data instead if it could not be loaded.
+func (level *Level) SaveData() error {
+	var writer = gonbt.NewWriter(false, binutils.BigEndian)
+	level.mutex.RLock()
+	if level.dataErr != nil {
+		level.mutex.RUnlock()
+		return level.dataErr
+	}
+	writer.WriteUncompressedCompound(level.data)
+	level.mutex.RUnlock()
+	var data, err = io.Compress(io.CompressionGzip, writer.GetData())
+	if err != nil {
+		return err
+	}
+	var path = level.getLevelDataPath()
+	if err := ioutil.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// getData returns the Data compound of the level data. The level must be locked while calling it.
+func (level *Level) getData() *gonbt.Compound {
+	var data = level.data.GetCompound("Data")
+	if data == nil {
+		data = gonbt.NewCompound("Data", make(map[string]gonbt.INamedTag))
+		level.data.SetTag(data)
+	}
+	return data
+}
+
+// lookupDimensionData returns the compound of the dimension with the given name in the level data,
+// or nil if the level data has none. Unlike getDimensionData it never creates compounds, so the level only needs to be read locked.
+func (level *Level) lookupDimensionData(name string) *gonbt.Compound {
+	var data = level.data.GetCompound("Data")
+	if data == nil {
+		return nil
+	}
+	var dimensions = data.GetCompound("Dimensions")
+	if dimensions == nil {
+		return nil
+	}
+	return dimensions.GetCompound(name)
+}
+
+// getDimensionData returns the compound of the dimension with the given name in the level data, creating it if needed.
+// The level must be locked while calling it.
+func (level *Level) getDimensionData(name string) *gonbt.Compound {
+	var data = level.getData()
+	var dimensions = data.GetCompound("Dimensions")
+	if dimensions == nil {
+		dimensions = gonbt.NewCompound("Dimensions", make(map[string]gonbt.INamedTag))
+		data.SetTag(dimensions)
+	}
+	var dimension = dimensions.GetCompound(name)
+	if dimension == nil {
+		dimension = gonbt.NewCompound(name, make(map[string]gonbt.INamedTag))
+		dimensions.SetTag(dimension)
+	}
+	return dimension
+}
@@ -0,0 +1,29 @@
+package light
+
+import (
+	"github.com/irmine/worlds/blocks"
+)
+
+// GetEmission returns the light level emitted by the block with the given ID, as registered in the block registry.
+func GetEmission(id byte) byte {
+	return blocks.GetLightEmission(id)
+}
+
+// GetFilter returns the light level filtered by the block with the given ID.
+// A filter of 15 means the block is opaque and blocks all light.
+// Solid blocks without a filter in the block registry filter all light, other blocks without one filter no light.
+func GetFilter(id byte) byte {
+	if filter, ok := blocks.GetLightFilter(id); ok {
+		return filter
+	}
+	if blocks.IsSolid(id) {
+		return blocks.FullOpacity
+	}
+	return 0
+}
+
+// SetBlockLight sets the light emission and filter of the block with the given ID in the block registry.
+// Custom blocks must be registered this way in order to emit or let through light.
+func SetBlockLight(id, emission, filter byte) {
+	blocks.SetLight(id, emission, filter)
+}
@@ -0,0 +1,368 @@
+package light
+
+import (
+	"github.com/irmine/worlds/chunks"
+)
+
+// ChunkSource provides the loaded chunks light propagates through.
+// Light never propagates into chunks that are not loaded.
+type ChunkSource interface {
+	GetChunk(x, z int32) (*chunks.Chunk, bool)
+}
+
+// Engine computes sky light and block light of chunks, and propagates changes in light across sub chunk and chunk boundaries.
+// Like other block changes, light changes are made to chunks without locking them.
+type Engine struct {
+	source ChunkSource
+	hasSky bool
+}
+
+// node is a position in the world, with the light level it had or got when queued.
+type node struct {
+	x, y, z int
+	light   byte
+}
+
+// faces are the offsets of all neighbours of a block.
+var faces = [6][3]int{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}}
+
+// NewEngine returns a new light engine propagating light through the chunks of the source.
+// Sky light is only computed if hasSky is true, such as in the overworld.
+func NewEngine(source ChunkSource, hasSky bool) *Engine {
+	return &Engine{source, hasSky}
+}
+
+// HasSky checks if the engine computes sky light.
+func (engine *Engine) HasSky() bool {
+	return engine.hasSky
+}
+
+// getChunk returns the chunk the given world X and Z are in.
+func (engine *Engine) getChunk(x, z int) (*chunks.Chunk, bool) {
+	return engine.source.GetChunk(int32(x>>4), int32(z>>4))
+}
+
+// GetLight returns the sky light or block light at the given world position, and a bool indicating if its chunk is loaded.
+// Positions in sub chunks that do not exist have no block light and full sky light.
+// Positions outside of the sub chunk range of their chunk are reported as not loaded.
+func (engine *Engine) GetLight(x, y, z int, sky bool) (byte, bool) {
+	var chunk, ok = engine.getChunk(x, z)
+	if !ok {
+		return 0, false
+	}
+	if subChunkRange := chunk.GetSubChunkRange(); !subChunkRange.ContainsY(y) {
+		if sky && y > subChunkRange.GetMaxY() {
+			return 15, false
+		}
+		return 0, false
+	}
+	return GetChunkLight(chunk, x&15, y, z&15, sky), true
+}
+
+// GetChunkLight returns the sky light or block light at the given position in the chunk.
+// Positions in sub chunks that do not exist have no block light and full sky light.
+func GetChunkLight(chunk *chunks.Chunk, x, y, z int, sky bool) byte {
+	var subChunkRange = chunk.GetSubChunkRange()
+	if !subChunkRange.ContainsY(y) {
+		if sky && y > subChunkRange.GetMaxY() {
+			return 15
+		}
+		return 0
+	}
+	if !chunk.SubChunkExists(subChunkRange.GetKey(y)) {
+		if sky {
+			return 15
+		}
+		return 0
+	}
+	if sky {
+		return chunk.GetSkyLight(x, y, z)
+	}
+	return chunk.GetBlockLight(x, y, z)
+}
+
+// setLight sets the sky light or block light at the given world position.
+// Sub chunks are only created if the light differs from the light of sub chunks that do not exist.
+func (engine *Engine) setLight(x, y, z int, sky bool, light byte) {
+	var chunk, ok = engine.getChunk(x, z)
+	if !ok || !chunk.GetSubChunkRange().ContainsY(y) {
+		return
+	}
+	if !chunk.SubChunkExists(chunk.GetSubChunkRange().GetKey(y)) && ((sky && light == 15) || (!sky && light == 0)) {
+		return
+	}
+	if sky {
+		chunk.SetSkyLight(x&15, y, z&15, light)
+		return
+	}
+	chunk.SetBlockLight(x&15, y, z&15, light)
+}
+
+// getBlockId returns the block ID at the given world position, or air if its chunk is not loaded.
+func (engine *Engine) getBlockId(x, y, z int) byte {
+	var chunk, ok = engine.getChunk(x, z)
+	if !ok || !chunk.GetSubChunkRange().ContainsY(y) || !chunk.SubChunkExists(chunk.GetSubChunkRange().GetKey(y)) {
+		return 0
+	}
+	return chunk.GetBlockId(x&15, y, z&15)
+}
+
+// Populate computes the sky light and block light of the chunk, and propagates light between the chunk and its loaded neighbours.
+// The chunk is marked as light populated afterwards.
+func (engine *Engine) Populate(chunk *chunks.Chunk) {
+	var blockQueue, skyQueue = engine.reset(chunk)
+	blockQueue = append(blockQueue, engine.borderNodes(chunk, false, nil)...)
+	engine.increase(blockQueue, false)
+	if engine.hasSky {
+		skyQueue = append(skyQueue, engine.borderNodes(chunk, true, nil)...)
+		engine.increase(skyQueue, true)
+	}
+	chunk.LightPopulated = true
+}
+
+// Relight recomputes the light of the chunk and its loaded neighbours, such as after many blocks of the chunk changed at once.
+// Light spreads at most 15 blocks, so light spread from the chunk never reaches further than its neighbours,
+// which is why light no longer spreading from the chunk is removed from its neighbours as well.
+func (engine *Engine) Relight(chunk *chunks.Chunk) {
+	var relit = make(map[[2]int32]bool)
+	var blockQueue, skyQueue []node
+	for x := chunk.X - 1; x <= chunk.X+1; x++ {
+		for z := chunk.Z - 1; z <= chunk.Z+1; z++ {
+			if neighbour, ok := engine.source.GetChunk(x, z); ok {
+				relit[[2]int32{x, z}] = true
+				var blockNodes, skyNodes = engine.reset(neighbour)
+				blockQueue, skyQueue = append(blockQueue, blockNodes...), append(skyQueue, skyNodes...)
+			}
+		}
+	}
+	// Only the light of chunks that were not reset spreads into the reset chunks.
+	for position := range relit {
+		var neighbour, _ = engine.source.GetChunk(position[0], position[1])
+		blockQueue = append(blockQueue, engine.borderNodes(neighbour, false, relit)...)
+		if engine.hasSky {
+			skyQueue = append(skyQueue, engine.borderNodes(neighbour, true, relit)...)
+		}
+		neighbour.LightPopulated = true
+	}
+	engine.increase(blockQueue, false)
+	if engine.hasSky {
+		engine.increase(skyQueue, true)
+	}
+}
+
+// reset sets the light of the chunk to the light emitted by its blocks and its direct sky light, without light spread from other blocks.
+// It returns the block light and sky light nodes light spreads from.
+func (engine *Engine) reset(chunk *chunks.Chunk) ([]node, []node) {
+	var baseX, baseZ = int(chunk.X) << 4, int(chunk.Z) << 4
+	var bottom, top = getBounds(chunk)
+	// Sub chunks that got emptied are above the bounds, but may still hold light.
+	var subChunkRange = chunk.GetSubChunkRange()
+	for key := subChunkRange.GetCount() - 1; key >= 0; key-- {
+		if chunk.SubChunkExists(byte(key)) {
+			if y := subChunkRange.GetKeyY(byte(key)) + 16; y > top {
+				top = y
+			}
+			break
+		}
+	}
+
+	var blockQueue, skyQueue []node
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			for y := bottom; y < top; y++ {
+				var light = GetEmission(chunk.GetBlockId(x, y, z))
+				chunk.SetBlockLight(x, y, z, light)
+				if light > 0 {
+					blockQueue = append(blockQueue, node{baseX | x, y, baseZ | z, light})
+				}
+			}
+			if engine.hasSky {
+				skyQueue = append(skyQueue, engine.populateColumn(chunk, x, z, bottom, top)...)
+			}
+		}
+	}
+	return blockQueue, skyQueue
+}
+
+// populateColumn sets the direct sky light of a column in the chunk, and returns the nodes sky light spreads from.
+// Direct sky light travels down without losing light, other than what is filtered by blocks.
+func (engine *Engine) populateColumn(chunk *chunks.Chunk, x, z, bottom, top int) []node {
+	var baseX, baseZ = int(chunk.X) << 4, int(chunk.Z) << 4
+	var nodes []node
+	var light byte = 15
+	for y := top - 1; y >= bottom; y-- {
+		if filter := GetFilter(chunk.GetBlockId(x, y, z)); filter >= light {
+			light = 0
+		} else {
+			light -= filter
+		}
+		chunk.SetSkyLight(x, y, z, light)
+		if light > 1 {
+			nodes = append(nodes, node{baseX | x, y, baseZ | z, light})
+		}
+	}
+	return nodes
+}
+
+// getBounds returns the lowest Y of the chunk, and the Y above its highest non-empty sub chunk.
+func getBounds(chunk *chunks.Chunk) (int, int) {
+	var subChunkRange = chunk.GetSubChunkRange()
+	return subChunkRange.GetMinY(), subChunkRange.GetMinY() + (chunk.GetHighestSubChunkIndex()+1)<<4
+}
+
+// borderNodes returns the nodes at the borders of the loaded neighbours of the chunk that light may spread into the chunk from.
+// Neighbours in the skipped chunks, which may be nil, are left out.
+func (engine *Engine) borderNodes(chunk *chunks.Chunk, sky bool, skipped map[[2]int32]bool) []node {
+	var baseX, baseZ = int(chunk.X) << 4, int(chunk.Z) << 4
+	var nodes []node
+	for i := 0; i < 16; i++ {
+		for _, position := range [4][2]int{{baseX - 1, baseZ + i}, {baseX + 16, baseZ + i}, {baseX + i, baseZ - 1}, {baseX + i, baseZ + 16}} {
+			var neighbour, ok = engine.getChunk(position[0], position[1])
+			if !ok || skipped[[2]int32{neighbour.X, neighbour.Z}] {
+				continue
+			}
+			var bottom, top = getBounds(neighbour)
+			for y := bottom; y < top; y++ {
+				if light := GetChunkLight(neighbour, position[0]&15, y, position[1]&15, sky); light > 1 {
+					nodes = append(nodes, node{position[0], y, position[1], light})
+				}
+			}
+		}
+	}
+	return nodes
+}
+
+// UpdateBlock updates the light around the block at the given world position after it changed.
+// Light no longer reaching positions gets removed, and light newly emitted or let through gets propagated.
+func (engine *Engine) UpdateBlock(x, y, z int) {
+	if chunk, ok := engine.getChunk(x, z); !ok || !chunk.GetSubChunkRange().ContainsY(y) {
+		return
+	}
+	engine.updateBlockLight(x, y, z)
+	if engine.hasSky {
+		engine.updateSkyLight(x, y, z)
+	}
+}
+
+// updateBlockLight updates the block light around the block at the given world position.
+func (engine *Engine) updateBlockLight(x, y, z int) {
+	var increase []node
+	if previous, _ := engine.GetLight(x, y, z, false); previous > 0 {
+		engine.setLight(x, y, z, false, 0)
+		increase = engine.decrease([]node{{x, y, z, previous}}, false)
+	} else {
+		increase = engine.neighbours(x, y, z, false)
+	}
+	if emission := GetEmission(engine.getBlockId(x, y, z)); emission > 0 {
+		engine.setLight(x, y, z, false, emission)
+		increase = append(increase, node{x, y, z, emission})
+	}
+	engine.increase(increase, false)
+}
+
+// updateSkyLight updates the sky light around the block at the given world position.
+// The direct sky light of the column of the block is recomputed, after which the changes get propagated.
+func (engine *Engine) updateSkyLight(x, y, z int) {
+	var chunk, _ = engine.getChunk(x, z)
+	var bottom, top = getBounds(chunk)
+	if y >= top {
+		top = (y>>4 + 1) << 4
+	}
+
+	var direct = make([]byte, top-bottom)
+	var light byte = 15
+	for i := top - 1; i >= bottom; i-- {
+		if filter := GetFilter(engine.getBlockId(x, i, z)); filter >= light {
+			light = 0
+		} else {
+			light -= filter
+		}
+		direct[i-bottom] = light
+	}
+
+	var decrease, increase []node
+	for i := top - 1; i >= bottom; i-- {
+		var current, _ = engine.GetLight(x, i, z, true)
+		if current > direct[i-bottom] {
+			engine.setLight(x, i, z, true, 0)
+			decrease = append(decrease, node{x, i, z, current})
+		}
+	}
+	increase = engine.decrease(decrease, true)
+	for i := top - 1; i >= bottom; i-- {
+		if current, _ := engine.GetLight(x, i, z, true); direct[i-bottom] > current {
+			engine.setLight(x, i, z, true, direct[i-bottom])
+			increase = append(increase, node{x, i, z, direct[i-bottom]})
+		}
+	}
+	increase = append(increase, engine.neighbours(x, y, z, true)...)
+	engine.increase(increase, true)
+}
+
+// neighbours returns the nodes of all lit neighbours of the given position.
+func (engine *Engine) neighbours(x, y, z int, sky bool) []node {
+	var nodes []node
+	for _, face := range faces {
+		var nx, ny, nz = x + face[0], y + face[1], z + face[2]
+		if light, ok := engine.GetLight(nx, ny, nz, sky); ok && light > 0 {
+			nodes = append(nodes, node{nx, ny, nz, light})
+		}
+	}
+	return nodes
+}
+
+// decrease removes all light that was spread from the given nodes, which must already have their light removed.
+// It returns the nodes bordering the removed light that still have light from other sources, which should be increased afterwards.
+func (engine *Engine) decrease(queue []node, sky bool) []node {
+	var increase []node
+	for len(queue) > 0 {
+		var current = queue[0]
+		queue = queue[1:]
+		for _, face := range faces {
+			var x, y, z = current.x + face[0], current.y + face[1], current.z + face[2]
+			var light, ok = engine.GetLight(x, y, z, sky)
+			if !ok || light == 0 {
+				continue
+			}
+			// Direct sky light travels down without losing light, so it must be removed from below too.
+			var direct = sky && face[1] == -1 && current.light == 15 && light == 15
+			if light < current.light || direct {
+				engine.setLight(x, y, z, sky, 0)
+				queue = append(queue, node{x, y, z, light})
+				continue
+			}
+			increase = append(increase, node{x, y, z, light})
+		}
+	}
+	return increase
+}
+
+// increase spreads the light of the given nodes to their neighbours, until the light has faded.
+func (engine *Engine) increase(queue []node, sky bool) {
+	for len(queue) > 0 {
+		var current = queue[0]
+		queue = queue[1:]
+		// The light of the node could have been changed after it was queued.
+		if light, _ := engine.GetLight(current.x, current.y, current.z, sky); light != current.light {
+			continue
+		}
+		for _, face := range faces {
+			var x, y, z = current.x + face[0], current.y + face[1], current.z + face[2]
+			var light, ok = engine.GetLight(x, y, z, sky)
+			if !ok {
+				continue
+			}
+			var filter = GetFilter(engine.getBlockId(x, y, z))
+			if filter == 0 && !(sky && face[1] == -1 && current.light == 15) {
+				filter = 1
+			}
+			if filter >= current.light {
+				continue
+			}
+			if target := current.light - filter; target > light {
+				engine.setLight(x, y, z, sky, target)
+				queue = append(queue, node{x, y, z, target})
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package light
+
+import (
+	"github.com/irmine/worlds/chunks"
+	"testing"
+)
+
+const (
+	// testLamp and testWall are block IDs that get registered with custom light values for the tests.
+	testLamp byte = 250
+	testWall byte = 251
+)
+
+// testSource is a chunk source holding a fixed set of chunks.
+type testSource map[[2]int32]*chunks.Chunk
+
+func (source testSource) GetChunk(x, z int32) (*chunks.Chunk, bool) {
+	var chunk, ok = source[[2]int32{x, z}]
+	return chunk, ok
+}
+
+// newTestSource returns a chunk source holding new empty chunks at the given chunk coordinates.
+func newTestSource(positions ...[2]int32) testSource {
+	SetBlockLight(testLamp, 14, 0)
+	SetBlockLight(testWall, 0, 15)
+	var source = make(testSource)
+	for _, position := range positions {
+		source[position] = chunks.New(position[0], position[1])
+	}
+	return source
+}
+
+// placeBlock sets the block at the world position and updates the light around it.
+func placeBlock(engine *Engine, source testSource, x, y, z int, id byte) {
+	var chunk, _ = source.GetChunk(int32(x>>4), int32(z>>4))
+	chunk.SetBlockId(x&15, y, z&15, id)
+	engine.UpdateBlock(x, y, z)
+}
+
+func TestBlockLightAcrossChunkBorder(t *testing.T) {
+	var source = newTestSource([2]int32{0, 0}, [2]int32{1, 0})
+	var engine = NewEngine(source, false)
+	placeBlock(engine, source, 15, 10, 5, testLamp)
+
+	var tests = []struct {
+		x, y, z int
+		light   byte
+	}{
+		{15, 10, 5, 14},
+		{16, 10, 5, 13},
+		{20, 10, 5, 9},
+		{28, 10, 5, 1},
+		{29, 10, 5, 0},
+		{16, 11, 6, 11},
+		{16, 15, 5, 8},
+		{16, 16, 5, 7},
+	}
+	for _, test := range tests {
+		if light, ok := engine.GetLight(test.x, test.y, test.z, false); !ok || light != test.light {
+			t.Errorf("block light at %v %v %v: got %v (loaded %v), expected %v", test.x, test.y, test.z, light, ok, test.light)
+		}
+	}
+	if _, ok := engine.GetLight(-1, 10, 5, false); ok {
+		t.Error("light reported in a chunk that is not loaded")
+	}
+
+	placeBlock(engine, source, 15, 10, 5, 0)
+	for _, test := range tests {
+		if light, _ := engine.GetLight(test.x, test.y, test.z, false); light != 0 {
+			t.Errorf("block light at %v %v %v after removing the lamp: got %v, expected 0", test.x, test.y, test.z, light)
+		}
+	}
+}
+
+func TestBlockLightBlockedAtChunkBorder(t *testing.T) {
+	var source = newTestSource([2]int32{0, 0}, [2]int32{0, 1})
+	var engine = NewEngine(source, false)
+	// A wall along the border of the chunks keeps light from passing straight through.
+	for x := 0; x < 16; x++ {
+		for y := 0; y < 32; y++ {
+			placeBlock(engine, source, x, y, 16, testWall)
+		}
+	}
+	placeBlock(engine, source, 8, 10, 15, testLamp)
+	if light, _ := engine.GetLight(8, 10, 16, false); light != 0 {
+		t.Errorf("block light inside the wall: got %v, expected 0", light)
+	}
+	if light, _ := engine.GetLight(8, 10, 17, false); light != 0 {
+		t.Errorf("block light behind the wall: got %v, expected 0", light)
+	}
+	if light, _ := engine.GetLight(8, 10, 14, false); light != 13 {
+		t.Errorf("block light in front of the lamp: got %v, expected 13", light)
+	}
+}
+
+func TestPopulateSpreadsFromNeighbours(t *testing.T) {
+	var source = newTestSource([2]int32{0, 0})
+	var engine = NewEngine(source, false)
+	placeBlock(engine, source, 0, 20, 8, testLamp)
+
+	// The neighbour gets loaded after the light was spread, so populating it must pull in the light at the border.
+	var neighbour = chunks.New(-1, 0)
+	// A block far from the lamp makes the sub chunk of the lamp exist in the neighbour.
+	neighbour.SetBlockId(0, 20, 0, 1)
+	source[[2]int32{-1, 0}] = neighbour
+	engine.Populate(neighbour)
+	var tests = []struct {
+		x, y, z int
+		light   byte
+	}{
+		{-1, 20, 8, 13},
+		{-5, 20, 8, 9},
+		{-1, 20, 9, 12},
+	}
+	for _, test := range tests {
+		if light, _ := engine.GetLight(test.x, test.y, test.z, false); light != test.light {
+			t.Errorf("block light at %v %v %v: got %v, expected %v", test.x, test.y, test.z, light, test.light)
+		}
+	}
+	if !neighbour.LightPopulated {
+		t.Error("chunk is not light populated after populating")
+	}
+}
@@ -0,0 +1,154 @@
+package worlds
+
+import (
+	"github.com/irmine/worlds/chunks"
+	"math"
+)
+
+// Resource is a resource of a dimension that can be capped by resource limits.
+type Resource byte
+
+const (
+	ResourceLoadedChunks Resource = iota
+	ResourceEntities
+	ResourcePendingRequests
+)
+
+// EntitySheddingPolicy decides what happens when an entity is added to a dimension at its entity limit.
+type EntitySheddingPolicy byte
+
+const (
+	// RefuseEntities refuses to add any new entities until entities get removed.
+	RefuseEntities EntitySheddingPolicy = iota
+	// DespawnLowPriority despawns the entity with the lowest priority to make room for the new entity,
+	// as long as its priority is lower than that of the new entity.
+	DespawnLowPriority
+)
+
+// ResourceLimits are hard caps on the resources a dimension may use.
+// A limit of 0 or lower means the resource is not capped.
+type ResourceLimits struct {
+	// MaxLoadedChunks is the maximum amount of chunks loaded at once.
+	// Requests to load new chunks are refused at the limit, and their function never runs.
+	// Chunks loaded for viewers being added are exempt, so that viewers can always be added.
+	MaxLoadedChunks int
+	// MaxEntities is the maximum amount of entities in the dimension.
+	// Entities added at the limit are shed according to the entity shedding policy.
+	MaxEntities int
+	// MaxPendingRequests is the maximum amount of chunk requests waiting to be processed by the chunk provider.
+	// Requests to load new chunks are refused at the limit, and their function never runs.
+	MaxPendingRequests int
+
+	// EntityShedding is the policy used when an entity is added at the entity limit.
+	EntityShedding EntitySheddingPolicy
+	// EntityPriority returns the priority of an entity used by the DespawnLowPriority policy.
+	// Viewers are never despawned. Every entity has a priority of 0 if nil.
+	EntityPriority func(entity chunks.ChunkEntity) int
+
+	// LimitFunction gets called every time a limit is hit, with the resource and its limit.
+	LimitFunction func(resource Resource, limit int)
+}
+
+// SetResourceLimits sets the resource limits of the dimension.
+// Resources already in use over the new limits are not shed, but no new resources are consumed until back under the limit.
+func (dimension *Dimension) SetResourceLimits(limits ResourceLimits) {
+	dimension.mutex.Lock()
+	dimension.limits = limits
+	dimension.mutex.Unlock()
+}
+
+// GetResourceLimits returns the resource limits of the dimension.
+func (dimension *Dimension) GetResourceLimits() ResourceLimits {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.limits
+}
+
+// limitHit calls the limit function of the limits for the given resource.
+func (limits ResourceLimits) limitHit(resource Resource, limit int) {
+	if limits.LimitFunction != nil {
+		limits.LimitFunction(resource, limit)
+	}
+}
+
+// canLoadChunk checks if a new chunk can be loaded under the resource limits of the dimension.
+func (dimension *Dimension) canLoadChunk() bool {
+	var limits = dimension.GetResourceLimits()
+	if limits.MaxLoadedChunks > 0 && dimension.chunkProvider.GetLoadedChunkCount() >= limits.MaxLoadedChunks {
+		limits.limitHit(ResourceLoadedChunks, limits.MaxLoadedChunks)
+		return false
+	}
+	if limits.MaxPendingRequests > 0 && dimension.chunkProvider.GetPendingRequestCount() >= limits.MaxPendingRequests {
+		limits.limitHit(ResourcePendingRequests, limits.MaxPendingRequests)
+		return false
+	}
+	return true
+}
+
+// makeEntityRoom reserves room for the given entity under the resource limits of the dimension,
+// despawning an entity with a lower priority if the shedding policy allows it.
+// Room is checked and reserved under the lock of the dimension, so that entities added concurrently cannot exceed the limit.
+// Reserved room must be released using releaseEntityRoom, or taken by adding the entity to the dimension.
+func (dimension *Dimension) makeEntityRoom(entity chunks.ChunkEntity) bool {
+	dimension.mutex.Lock()
+	var limits = dimension.limits
+	if limits.MaxEntities <= 0 || len(dimension.entities)+dimension.reservedEntities < limits.MaxEntities {
+		dimension.reservedEntities++
+		dimension.mutex.Unlock()
+		return true
+	}
+	dimension.mutex.Unlock()
+	limits.limitHit(ResourceEntities, limits.MaxEntities)
+	if limits.EntityShedding != DespawnLowPriority {
+		return false
+	}
+
+	var priority = func(entity chunks.ChunkEntity) int {
+		if limits.EntityPriority == nil {
+			return 0
+		}
+		return limits.EntityPriority(entity)
+	}
+	var lowest, lowestPriority = uint64(0), math.MaxInt64
+	for runtimeId, candidate := range dimension.getEntities() {
+		if _, ok := candidate.(chunks.Viewer); ok {
+			continue
+		}
+		if p := priority(candidate); p < lowestPriority {
+			lowest, lowestPriority = runtimeId, p
+		}
+	}
+	if lowestPriority == math.MaxInt64 || lowestPriority >= priority(entity) {
+		return false
+	}
+	dimension.mutex.Lock()
+	// Room is only reserved if no other entity took the room of the despawned entity in the meantime.
+	var _, ok = dimension.entities[lowest]
+	ok = ok && len(dimension.entities)+dimension.reservedEntities <= limits.MaxEntities
+	if ok {
+		dimension.reservedEntities++
+	}
+	dimension.mutex.Unlock()
+	if ok {
+		dimension.RemoveEntity(lowest)
+	}
+	return ok
+}
+
+// getEntities returns a copy of the entities of the dimension, so that they can be ranged over without holding its lock.
+func (dimension *Dimension) getEntities() map[uint64]chunks.ChunkEntity {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	var entities = make(map[uint64]chunks.ChunkEntity, len(dimension.entities))
+	for runtimeId, entity := range dimension.entities {
+		entities[runtimeId] = entity
+	}
+	return entities
+}
+
+// releaseEntityRoom releases room reserved by makeEntityRoom for an entity that did not get added.
+func (dimension *Dimension) releaseEntityRoom() {
+	dimension.mutex.Lock()
+	dimension.reservedEntities--
+	dimension.mutex.Unlock()
+}
@@ -0,0 +1,250 @@
+package worlds
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/blocks"
+	"github.com/irmine/worlds/chunks"
+)
+
+// LiquidBehavior is the behavior of water and lava, flowing out of sources into neighbouring blocks on scheduled block ticks.
+// The data of a liquid is 0 for sources, the distance to the source for flowing liquid, and has the 0x08 bit set for falling liquid.
+// Liquids only flow in dimensions with liquid flow enabled.
+type LiquidBehavior struct {
+	BaseBehavior
+	// FlowingId and StillId are the block IDs of the flowing and the still liquid.
+	FlowingId, StillId byte
+	lava               bool
+}
+
+var (
+	// WaterBehavior is the behavior of water.
+	WaterBehavior = &LiquidBehavior{BaseBehavior{}, 8, 9, false}
+	// LavaBehavior is the behavior of lava, which turns into obsidian, cobblestone or stone when touching water.
+	LavaBehavior = &LiquidBehavior{BaseBehavior{}, 10, 11, true}
+)
+
+// horizontalFaces are the offsets of the horizontal neighbours of a block.
+var horizontalFaces = [4][3]int{{1, 0, 0}, {-1, 0, 0}, {0, 0, 1}, {0, 0, -1}}
+
+// RegisterLiquidBehaviors registers the water and lava behaviors into the behavior manager.
+func RegisterLiquidBehaviors(manager BehaviorManager) {
+	manager.Register(WaterBehavior.FlowingId, WaterBehavior)
+	manager.Register(WaterBehavior.StillId, WaterBehavior)
+	manager.Register(LavaBehavior.FlowingId, LavaBehavior)
+	manager.Register(LavaBehavior.StillId, LavaBehavior)
+}
+
+// SetLiquidFlow sets if liquids flow in the dimension. Liquid flow is disabled by default.
+func (dimension *Dimension) SetLiquidFlow(value bool) {
+	dimension.mutex.Lock()
+	dimension.liquidFlow = value
+	dimension.mutex.Unlock()
+}
+
+// HasLiquidFlow checks if liquids flow in the dimension.
+func (dimension *Dimension) HasLiquidFlow() bool {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.liquidFlow
+}
+
+// getLoadedBlock returns the block ID and data at the given world position, and a bool indicating if its chunk is loaded.
+// Positions outside of the sub chunk range of their chunk are reported as not loaded.
+// Positions in unloaded chunks are reported as loaded bedrock if border walls are enabled, so that liquids treat them as solid.
+func (dimension *Dimension) getLoadedBlock(x, y, z int) (byte, byte, bool) {
+	var chunk, ok = dimension.GetChunk(int32(x>>4), int32(z>>4))
+	if !ok {
+		if dimension.IsBorderWallAt(r3.Vector{X: float64(x), Y: float64(y), Z: float64(z)}) {
+			return 7, 0, true
+		}
+		return 0, 0, false
+	}
+	if !chunk.GetSubChunkRange().ContainsY(y) {
+		return 0, 0, false
+	}
+	return chunk.GetBlockIdUnchecked(x&15, y, z&15), chunk.GetBlockDataUnchecked(x&15, y, z&15), true
+}
+
+// OnPlace schedules the liquid to flow.
+func (liquid *LiquidBehavior) OnPlace(dimension *Dimension, x, y, z int, block blocks.Block) {
+	liquid.schedule(dimension, x, y, z)
+}
+
+// OnNeighborUpdate schedules the liquid to flow.
+func (liquid *LiquidBehavior) OnNeighborUpdate(dimension *Dimension, x, y, z, neighbourX, neighbourY, neighbourZ int) {
+	liquid.schedule(dimension, x, y, z)
+}
+
+// OnScheduledTick updates the level of the liquid, and makes it flow into its neighbours.
+func (liquid *LiquidBehavior) OnScheduledTick(dimension *Dimension, x, y, z int, tick chunks.ScheduledTick) {
+	if !dimension.HasLiquidFlow() {
+		return
+	}
+	var id, data, ok = dimension.getLoadedBlock(x, y, z)
+	if !ok || !liquid.isLiquid(id) {
+		return
+	}
+	if liquid.lava && liquid.solidify(dimension, x, y, z, data) {
+		return
+	}
+	var decay = liquid.getDecay(dimension)
+	if data != 0 {
+		var expected = liquid.getExpectedData(dimension, x, y, z, decay)
+		if expected < 0 {
+			liquid.setBlock(dimension, x, y, z, 0, 0)
+			return
+		}
+		if byte(expected) != data {
+			data = byte(expected)
+			liquid.setLiquid(dimension, x, y, z, data)
+		}
+	}
+
+	if belowId, _, ok := dimension.getLoadedBlock(x, y-1, z); ok {
+		if liquid.lava && WaterBehavior.isLiquid(belowId) {
+			liquid.setBlock(dimension, x, y-1, z, 1, 0)
+			return
+		}
+		if liquid.flowInto(dimension, x, y-1, z, 0x08) && data != 0 {
+			// Flowing liquid able to fall only spreads down, where sources also spread sideways.
+			return
+		}
+	}
+	var level = data
+	if data&0x08 != 0 {
+		level = 0
+	}
+	if level+decay >= 8 {
+		return
+	}
+	for _, face := range horizontalFaces {
+		liquid.flowInto(dimension, x+face[0], y, z+face[2], level+decay)
+	}
+}
+
+// isLiquid checks if the block ID is the flowing or still block ID of the liquid.
+func (liquid *LiquidBehavior) isLiquid(id byte) bool {
+	return id == liquid.FlowingId || id == liquid.StillId
+}
+
+// IsVariant checks if the block ID is the flowing or still block ID of the liquid,
+// so that scheduled flows are kept when the liquid switches between them.
+func (liquid *LiquidBehavior) IsVariant(id byte) bool {
+	return liquid.isLiquid(id)
+}
+
+// getDecay returns the level the liquid loses for every block it flows in the dimension.
+// Lava flows further in the nether.
+func (liquid *LiquidBehavior) getDecay(dimension *Dimension) byte {
+	if liquid.lava && dimension.id != NetherId {
+		return 2
+	}
+	return 1
+}
+
+// getDelay returns the amount of ticks it takes for the liquid to flow into a block in the dimension.
+func (liquid *LiquidBehavior) getDelay(dimension *Dimension) int64 {
+	if !liquid.lava {
+		return 5
+	}
+	if dimension.id == NetherId {
+		return 10
+	}
+	return 30
+}
+
+// schedule schedules the liquid at the given position to flow, if liquids flow in the dimension and no update was scheduled yet.
+func (liquid *LiquidBehavior) schedule(dimension *Dimension, x, y, z int) {
+	if !dimension.HasLiquidFlow() {
+		return
+	}
+	var chunk, ok = dimension.GetChunk(int32(x>>4), int32(z>>4))
+	if !ok || chunk.HasScheduledTick(x&15, y, z&15) {
+		return
+	}
+	dimension.ScheduleBlockTick(r3.Vector{X: float64(x), Y: float64(y), Z: float64(z)}, liquid.getDelay(dimension), 0)
+}
+
+// getExpectedData returns the data the flowing liquid at the given position should have based on its neighbours,
+// or -1 if the liquid is no longer fed and should dry up.
+func (liquid *LiquidBehavior) getExpectedData(dimension *Dimension, x, y, z int, decay byte) int {
+	if aboveId, _, ok := dimension.getLoadedBlock(x, y+1, z); ok && liquid.isLiquid(aboveId) {
+		return 0x08
+	}
+	var lowest, sources = byte(8), 0
+	for _, face := range horizontalFaces {
+		var id, data, ok = dimension.getLoadedBlock(x+face[0], y, z+face[2])
+		if !ok || !liquid.isLiquid(id) {
+			continue
+		}
+		if data == 0 {
+			sources++
+		}
+		if data&0x08 != 0 {
+			data = 0
+		}
+		if data < lowest {
+			lowest = data
+		}
+	}
+	// Water between two sources becomes a source itself, as long as it rests on something.
+	if !liquid.lava && sources >= 2 {
+		if belowId, belowData, ok := dimension.getLoadedBlock(x, y-1, z); ok && (chunks.IsSolidBlock(belowId) || (liquid.isLiquid(belowId) && belowData == 0)) {
+			return 0
+		}
+	}
+	if lowest+decay >= 8 {
+		return -1
+	}
+	return int(lowest + decay)
+}
+
+// flowInto makes the liquid flow into the block at the given position with the given data, and returns if it did.
+// Liquid flows into air and blocks that can be washed away, and into flowing liquid of the same kind further from its source.
+func (liquid *LiquidBehavior) flowInto(dimension *Dimension, x, y, z int, data byte) bool {
+	var id, current, ok = dimension.getLoadedBlock(x, y, z)
+	if !ok {
+		return false
+	}
+	if liquid.isLiquid(id) {
+		if current == 0 || current&0x08 != 0 || current <= data {
+			return false
+		}
+	} else if id != 0 && (chunks.IsSolidBlock(id) || chunks.IsLiquidBlock(id)) {
+		return false
+	}
+	liquid.setLiquid(dimension, x, y, z, data)
+	return true
+}
+
+// solidify turns the lava at the given position into obsidian if a source, or into cobblestone otherwise, if it touches water.
+// It returns if the lava was solidified.
+func (liquid *LiquidBehavior) solidify(dimension *Dimension, x, y, z int, data byte) bool {
+	for _, face := range [5][3]int{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {0, 0, -1}} {
+		var id, _, ok = dimension.getLoadedBlock(x+face[0], y+face[1], z+face[2])
+		if !ok || !WaterBehavior.isLiquid(id) {
+			continue
+		}
+		if data == 0 {
+			liquid.setBlock(dimension, x, y, z, 49, 0)
+		} else {
+			liquid.setBlock(dimension, x, y, z, 4, 0)
+		}
+		return true
+	}
+	return false
+}
+
+// setLiquid sets the liquid with the given data at the given position, using the still block ID for sources.
+func (liquid *LiquidBehavior) setLiquid(dimension *Dimension, x, y, z int, data byte) {
+	if data == 0 {
+		liquid.setBlock(dimension, x, y, z, liquid.StillId, 0)
+		return
+	}
+	liquid.setBlock(dimension, x, y, z, liquid.FlowingId, data)
+}
+
+// setBlock sets the block with the given ID and data at the given position.
+func (liquid *LiquidBehavior) setBlock(dimension *Dimension, x, y, z int, id, data byte) {
+	dimension.SetBlockAt(r3.Vector{X: float64(x), Y: float64(y), Z: float64(z)}, blocks.NewLegacyBlock(id, data, nil))
+}
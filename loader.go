@@ -1,6 +1,7 @@
 package worlds
 
 import (
+	"github.com/golang/geo/r3"
 	"github.com/irmine/worlds/chunks"
 	"math"
 	"sync"
@@ -18,23 +19,43 @@ type Loader struct {
 	// LoadFunction gets called for every chunk loaded by this loader.
 	LoadFunction            func(*chunks.Chunk)
 	PublisherUpdateFunction func()
+	// BiomeChangeFunction gets called when the center of the loader crosses into a different biome.
+	// The previous biome is -1 if the biome of the loader was not yet known.
+	BiomeChangeFunction func(previous int16, current byte)
+
+	centerX, centerZ int
+	biome            int16
 
 	mutex sync.RWMutex
 
 	loadedChunks     map[int]*chunks.Chunk
 	loadChunkQueue   map[int]bool
 	unloadChunkQueue map[int]bool
+
+	viewer chunks.Viewer
 }
 
 // NewLoader returns a new loader on the given dimension with the given chunk X and Z.
 func NewLoader(dimension *Dimension, x, z int32) *Loader {
-	return &Loader{dimension, x, z, func(chunk *chunks.Chunk) {}, func(chunk *chunks.Chunk) {}, func(){}, sync.RWMutex{}, make(map[int]*chunks.Chunk), make(map[int]bool), make(map[int]bool)}
+	return &Loader{dimension, x, z, func(chunk *chunks.Chunk) {}, func(chunk *chunks.Chunk) {}, func(){}, func(previous int16, current byte) {}, int(x<<4) + 8, int(z<<4) + 8, -1, sync.RWMutex{}, make(map[int]*chunks.Chunk), make(map[int]bool), make(map[int]bool), nil}
 }
 
 // Move moves the loader to the given chunk X and Z.
+// The center of the loader is set to the center of the chunk.
 func (loader *Loader) Move(chunkX, chunkZ int32) {
 	loader.ChunkX = chunkX
 	loader.ChunkZ = chunkZ
+	loader.centerX, loader.centerZ = int(chunkX<<4)+8, int(chunkZ<<4)+8
+	loader.checkBiome()
+}
+
+// MoveTo moves the loader to the chunk of the given position, and sets its center to the position.
+func (loader *Loader) MoveTo(position r3.Vector) {
+	var x, z = int(math.Floor(position.X)), int(math.Floor(position.Z))
+	loader.ChunkX = int32(x >> 4)
+	loader.ChunkZ = int32(z >> 4)
+	loader.centerX, loader.centerZ = x, z
+	loader.checkBiome()
 }
 
 // Warp warps the loader to the given dimension and moves it to the given chunk X and Z.
@@ -43,6 +64,27 @@ func (loader *Loader) Warp(dimension *Dimension, chunkX, chunkZ int32) {
 	loader.Move(chunkX, chunkZ)
 }
 
+// GetBiome returns the biome at the center of the loader, or -1 if it is not yet known.
+func (loader *Loader) GetBiome() int16 {
+	return loader.biome
+}
+
+// checkBiome checks the biome at the center of the loader and calls the BiomeChangeFunction if it changed.
+// The biome can only be checked once the chunk at the center of the loader is loaded.
+func (loader *Loader) checkBiome() {
+	var chunk, ok = loader.Dimension.GetChunk(int32(loader.centerX>>4), int32(loader.centerZ>>4))
+	if !ok {
+		return
+	}
+	var biome = chunk.GetBiome(loader.centerX&15, loader.centerZ&15)
+	if int16(biome) == loader.biome {
+		return
+	}
+	var previous = loader.biome
+	loader.biome = int16(biome)
+	loader.BiomeChangeFunction(previous, biome)
+}
+
 // GetLoadedChunkCount returns loaded chunks.
 func (loader *Loader) GetLoadedChunkCount() int {
 	return len(loader.loadedChunks)
@@ -132,6 +174,7 @@ func (loader *Loader) ProcessLoadQueue(perTick int) {
 	var f = func(chunk *chunks.Chunk) {
 		loader.setChunkInUse(chunk.X, chunk.Z, chunk)
 		loader.LoadFunction(chunk)
+		loader.showEntities(chunk)
 	}
 	var count = 1
 	for index := range loader.loadChunkQueue {
@@ -139,7 +182,7 @@ func (loader *Loader) ProcessLoadQueue(perTick int) {
 			break
 		}
 		var x, z= loader.GetChunkXZ(index)
-		loader.Dimension.chunkProvider.LoadChunk(int32(x), int32(z), f)
+		loader.Dimension.LoadChunk(int32(x), int32(z), f)
 		delete(loader.loadChunkQueue, index)
 		count++
 	}
@@ -158,6 +201,7 @@ func (loader *Loader) ProcessUnloadQueue(perTick int) {
 		var chunk, ok= loader.Dimension.chunkProvider.GetChunk(int32(x), int32(z))
 		if ok {
 			loader.UnloadFunction(chunk)
+			loader.hideEntities(chunk)
 		}
 		if _, ok := loader.loadedChunks[index]; ok {
 			delete(loader.loadedChunks, index)
@@ -171,6 +215,8 @@ func (loader *Loader) ProcessUnloadQueue(perTick int) {
 }
 
 func (loader *Loader) Request(distance int32, perTick int) {
+	loader.checkBiome()
+	loader.UpdateEntityVisibility()
 	loader.SortChunks(distance)
 	if len(loader.loadChunkQueue) > 0 {
 		loader.PublisherUpdateFunction()
@@ -179,4 +225,87 @@ func (loader *Loader) Request(distance int32, perTick int) {
 	if len(loader.unloadChunkQueue) > 0 {
 		loader.ProcessUnloadQueue(perTick)
 	}
-}
\ No newline at end of file
+}
+// VisibleEntity is an entity managing its own visibility to viewers, such as by a view range.
+// The entities of the entities package implement it.
+type VisibleEntity interface {
+	chunks.ChunkEntity
+	// UpdateVisibility spawns the entity to the viewer or despawns it from the viewer, depending on whether the viewer should see it.
+	UpdateVisibility(viewer chunks.Viewer)
+	// HideFrom despawns the entity from the viewer if it is spawned to it.
+	HideFrom(viewer chunks.Viewer)
+}
+
+// SetViewer sets the viewer of the chunks loaded by the loader.
+// The viewer is added to chunks as they get loaded and removed as they get unloaded,
+// and entities in the chunks get spawned to and despawned from the viewer as it moves around.
+func (loader *Loader) SetViewer(viewer chunks.Viewer) {
+	loader.mutex.Lock()
+	loader.viewer = viewer
+	loader.mutex.Unlock()
+}
+
+// GetViewer returns the viewer of the chunks loaded by the loader, or nil if it has none.
+func (loader *Loader) GetViewer() chunks.Viewer {
+	loader.mutex.RLock()
+	defer loader.mutex.RUnlock()
+	return loader.viewer
+}
+
+// showEntities adds the viewer of the loader to the chunk, and spawns the entities in the chunk it should see.
+func (loader *Loader) showEntities(chunk *chunks.Chunk) {
+	var viewer = loader.GetViewer()
+	if viewer == nil {
+		return
+	}
+	chunk.AddViewer(viewer)
+	for _, entity := range getVisibleEntities(chunk) {
+		entity.UpdateVisibility(viewer)
+	}
+}
+
+// hideEntities despawns all entities in the chunk from the viewer of the loader, and removes the viewer from the chunk.
+// The loader must be locked.
+func (loader *Loader) hideEntities(chunk *chunks.Chunk) {
+	if loader.viewer == nil {
+		return
+	}
+	for _, entity := range getVisibleEntities(chunk) {
+		entity.HideFrom(loader.viewer)
+	}
+	chunk.RemoveViewer(loader.viewer)
+}
+
+// UpdateEntityVisibility spawns and despawns the entities in the chunks loaded by the loader to its viewer,
+// depending on whether they are within their view range of the viewer.
+func (loader *Loader) UpdateEntityVisibility() {
+	loader.mutex.RLock()
+	var viewer = loader.viewer
+	var loaded = make([]*chunks.Chunk, 0, len(loader.loadedChunks))
+	for _, chunk := range loader.loadedChunks {
+		loaded = append(loaded, chunk)
+	}
+	loader.mutex.RUnlock()
+	if viewer == nil {
+		return
+	}
+	for _, chunk := range loaded {
+		for _, entity := range getVisibleEntities(chunk) {
+			entity.UpdateVisibility(viewer)
+		}
+	}
+}
+
+// getVisibleEntities returns all entities in the chunk managing their own visibility.
+// The entities are collected first, so that they can be spawned and despawned without holding the chunk lock.
+func getVisibleEntities(chunk *chunks.Chunk) []VisibleEntity {
+	chunk.RLock()
+	defer chunk.RUnlock()
+	var entities []VisibleEntity
+	for _, entity := range chunk.GetEntities() {
+		if visible, ok := entity.(VisibleEntity); ok {
+			entities = append(entities, visible)
+		}
+	}
+	return entities
+}
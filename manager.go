@@ -5,8 +5,13 @@ import (
 	"github.com/irmine/worlds/generation"
 	"os"
 	"sync"
+	"time"
 )
 
+// DefaultTickBudget is the default time budget for a single tick of the manager.
+// It is slightly less than the 50 milliseconds a tick takes at 20 ticks per second.
+const DefaultTickBudget = 45 * time.Millisecond
+
 // Manager is a struct managing all levels and provides helper functions.
 type Manager struct {
 	serverPath       string
@@ -15,13 +20,16 @@ type Manager struct {
 	defaultLevel *Level
 	mutex        sync.RWMutex
 	levels       map[string]*Level
+
+	tickBudget time.Duration
+	tasks      *taskQueue
 }
 
 // NewManager returns a new worlds manager.
 // The manager will create its content inside of the `serverPath/worlds/` folder.
 func NewManager(serverPath string) *Manager {
 	os.MkdirAll(serverPath+"/worlds", 0700)
-	return &Manager{serverPath, generation.NewManager(), nil, sync.RWMutex{}, make(map[string]*Level)}
+	return &Manager{serverPath, generation.NewManager(), nil, sync.RWMutex{}, make(map[string]*Level), DefaultTickBudget, &taskQueue{}}
 }
 
 // GetLoadedLevels returns all loaded levels of the manager in a name => level map.
@@ -69,6 +77,23 @@ func (manager *Manager) LoadLevel(levelName string) bool {
 	return true
 }
 
+// LoadLevelLocked loads a level with the given name and locks it, so that other processes can not open it.
+// Returns io.WorldInUse if the level is in use by another process,
+// and an error if the level was already loaded.
+func (manager *Manager) LoadLevelLocked(levelName string) error {
+	if manager.IsLevelLoaded(levelName) {
+		return errors.New("level with given name is already loaded")
+	}
+	var level = NewLevel(levelName, manager.serverPath)
+	if err := level.AcquireLock(); err != nil {
+		return err
+	}
+	manager.mutex.Lock()
+	manager.levels[levelName] = level
+	manager.mutex.Unlock()
+	return nil
+}
+
 // GetDefaultLevel returns the default level of the manager.
 func (manager *Manager) GetDefaultLevel() *Level {
 	return manager.defaultLevel
@@ -96,19 +121,59 @@ func (manager *Manager) GetLevel(name string) (*Level, error) {
 	return manager.levels[name], nil
 }
 
-// Tick ticks all levels managed by the Manager.
+// Tick ticks all levels managed by the Manager within the tick budget.
+// Dimensions that did not fit in the budget are deferred to the next tick, see Level.TickUntil.
+// Deferred tasks run after the levels are ticked, as long as the tick budget allows.
+// Tasks that did not fit in the budget are deferred to the next tick.
 func (manager *Manager) Tick() {
-	for _, level := range manager.levels {
-		level.Tick()
+	var deadline = time.Now().Add(manager.tickBudget)
+	for _, level := range manager.GetLevels() {
+		level.TickUntil(deadline)
+	}
+	manager.tasks.run(deadline)
+}
+
+// GetTickBudget returns the time budget for a single tick.
+func (manager *Manager) GetTickBudget() time.Duration {
+	return manager.tickBudget
+}
+
+// SetTickBudget sets the time budget for a single tick.
+// The budget limits the dimensions ticked and the deferred tasks scheduled with ScheduleTask, see Tick.
+func (manager *Manager) SetTickBudget(budget time.Duration) {
+	manager.tickBudget = budget
+}
+
+// ScheduleTask schedules a task to run at the end of a tick with the given priority.
+// Low priority work such as loading far chunks, pregeneration and saving should be scheduled
+// with a lower priority, so that it gets deferred when ticks take long.
+func (manager *Manager) ScheduleTask(priority TaskPriority, task func()) {
+	manager.tasks.add(priority, task)
+}
+
+// GetPendingTaskCount returns the amount of scheduled tasks that have not yet run.
+func (manager *Manager) GetPendingTaskCount() int {
+	return manager.tasks.len()
+}
+
+// ScheduleSave schedules a low priority save of all levels and their dimensions.
+// Every dimension gets saved in a separate task, so saving can be spread over multiple ticks.
+func (manager *Manager) ScheduleSave() {
+	for _, level := range manager.GetLevels() {
+		for _, dimension := range level.GetDimensions() {
+			manager.ScheduleTask(PriorityLow, dimension.Save)
+		}
 	}
 }
 
-// Close closes all levels and their dimensions.
+// Close closes all levels and their dimensions, saving the level data of levels and releasing their locks.
 func (manager *Manager) Close() {
 	for _, level := range manager.levels {
 		for _, dimension := range level.GetDimensions() {
 			dimension.Close(false)
 		}
+		level.SaveData()
+		level.ReleaseLock()
 	}
 }
 
@@ -118,5 +183,6 @@ func (manager *Manager) Save() {
 		for _, dimension := range level.GetDimensions() {
 			dimension.Save()
 		}
+		level.SaveData()
 	}
 }
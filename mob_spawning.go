@@ -0,0 +1,262 @@
+package worlds
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/chunks"
+	"math"
+	"sync"
+)
+
+// MobCategory is a category of mobs spawned naturally, each category having its own cap.
+type MobCategory byte
+
+const (
+	CategoryMonster MobCategory = iota
+	CategoryCreature
+	CategoryAmbient
+	CategoryWaterCreature
+)
+
+// PositionedViewer is a viewer with a position, such as a player.
+// Mobs only spawn naturally around viewers implementing this interface, and only these viewers are limited by view ranges.
+type PositionedViewer interface {
+	chunks.Viewer
+	GetPosition() r3.Vector
+}
+
+// MobSpawnRule is a rule for a mob to spawn naturally.
+type MobSpawnRule struct {
+	// Identifier is the identifier of the entity type of the mob, used to construct it through the entity registry of the dimension.
+	Identifier string
+	// Category is the category of the mob, whose cap limits the mobs spawned.
+	Category MobCategory
+	// Constraints are the constraints of the light, blocks and Y of positions the mob spawns at.
+	Constraints chunks.SpawnConstraints
+	// Biomes are the IDs of the biomes the mob spawns in. The mob spawns in every biome if empty.
+	Biomes []byte
+	// Weight is the weight of the rule among the rules of its category. Rules with a higher weight are picked more often.
+	Weight int
+	// MinGroup and MaxGroup are the inclusive range of the size of the groups the mob spawns in.
+	MinGroup, MaxGroup int
+}
+
+// spawnsIn checks if the mob of the rule spawns in the biome with the given ID.
+func (rule MobSpawnRule) spawnsIn(biome byte) bool {
+	if len(rule.Biomes) == 0 {
+		return true
+	}
+	for _, id := range rule.Biomes {
+		if id == biome {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// DefaultMobSpawnRadius is the default radius in chunks around viewers mobs spawn in.
+	DefaultMobSpawnRadius = 8
+	// DefaultMobSpawnDistance is the default minimum distance between viewers and mobs spawned.
+	DefaultMobSpawnDistance = 24
+	// GroupSpawnSpread is the distance in blocks mobs in a group spawn from the first mob of the group.
+	GroupSpawnSpread = 4
+)
+
+// DefaultMobCaps returns the caps of naturally spawned mobs per category used by Minecraft.
+func DefaultMobCaps() map[MobCategory]int {
+	return map[MobCategory]int{CategoryMonster: 70, CategoryCreature: 10, CategoryAmbient: 15, CategoryWaterCreature: 5}
+}
+
+// DefaultMobSpawnRules returns the rules of the common mobs of the overworld:
+// monsters spawning in the dark, and animals spawning on grass in the light.
+func DefaultMobSpawnRules() []MobSpawnRule {
+	var monster, animal = chunks.DefaultSpawnConstraints(), chunks.DefaultSpawnConstraints()
+	monster.MaxLight = 7
+	animal.MinLight = 9
+	animal.IsSolid = func(id byte) bool {
+		return id == 2
+	}
+	return []MobSpawnRule{
+		{"minecraft:zombie", CategoryMonster, monster, nil, 100, 4, 4},
+		{"minecraft:skeleton", CategoryMonster, monster, nil, 100, 4, 4},
+		{"minecraft:spider", CategoryMonster, monster, nil, 100, 4, 4},
+		{"minecraft:creeper", CategoryMonster, monster, nil, 100, 4, 4},
+		{"minecraft:pig", CategoryCreature, animal, nil, 10, 4, 4},
+		{"minecraft:sheep", CategoryCreature, animal, nil, 12, 4, 4},
+		{"minecraft:chicken", CategoryCreature, animal, nil, 10, 4, 4},
+	}
+}
+
+// MobSpawner spawns mobs naturally at random positions in loaded chunks around viewers every tick.
+// Mobs only spawn if the doMobSpawning game rule is enabled, and if the dimension has an entity registry.
+type MobSpawner struct {
+	// Rules are the rules of all mobs spawned naturally.
+	Rules []MobSpawnRule
+	// Caps are the maximum amount of naturally spawned mobs per category.
+	// Mobs of categories without cap are never spawned.
+	Caps map[MobCategory]int
+	// Radius is the radius in chunks around viewers mobs spawn in.
+	Radius int32
+	// MinDistance is the minimum distance between viewers and mobs spawned.
+	MinDistance float64
+
+	mutex sync.RWMutex
+	// spawned are the categories of mobs naturally spawned, keyed by their runtime ID.
+	spawned map[uint64]MobCategory
+}
+
+// NewMobSpawner returns a new mob spawner with the given rules and caps, spawning mobs at the default radius and distance.
+func NewMobSpawner(rules []MobSpawnRule, caps map[MobCategory]int) *MobSpawner {
+	return &MobSpawner{rules, caps, DefaultMobSpawnRadius, DefaultMobSpawnDistance, sync.RWMutex{}, make(map[uint64]MobCategory)}
+}
+
+// GetMobCount returns the amount of naturally spawned mobs of the category still in the dimension.
+func (spawner *MobSpawner) GetMobCount(category MobCategory) int {
+	spawner.mutex.RLock()
+	defer spawner.mutex.RUnlock()
+	var count = 0
+	for _, mobCategory := range spawner.spawned {
+		if mobCategory == category {
+			count++
+		}
+	}
+	return count
+}
+
+// SetMobSpawner sets the mob spawner of the dimension. Mobs do not spawn naturally if nil.
+func (dimension *Dimension) SetMobSpawner(spawner *MobSpawner) {
+	dimension.mutex.Lock()
+	dimension.mobSpawner = spawner
+	dimension.mutex.Unlock()
+}
+
+// GetMobSpawner returns the mob spawner of the dimension, or nil if it has none.
+func (dimension *Dimension) GetMobSpawner() *MobSpawner {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.mobSpawner
+}
+
+// spawnMobs makes a spawn attempt for every category of mobs under its cap, around a random viewer.
+func (dimension *Dimension) spawnMobs() {
+	var spawner = dimension.GetMobSpawner()
+	if spawner == nil || dimension.GetEntityRegistry() == nil || !dimension.level.GetBoolGameRule(GameRuleDoMobSpawning, true) {
+		return
+	}
+	spawner.mutex.Lock()
+	if spawner.spawned == nil {
+		spawner.spawned = make(map[uint64]MobCategory)
+	}
+	for runtimeId := range spawner.spawned {
+		if !dimension.HasEntity(runtimeId) {
+			delete(spawner.spawned, runtimeId)
+		}
+	}
+	spawner.mutex.Unlock()
+	var viewers []PositionedViewer
+	dimension.mutex.RLock()
+	for _, viewer := range dimension.viewers {
+		if positioned, ok := viewer.(PositionedViewer); ok {
+			viewers = append(viewers, positioned)
+		}
+	}
+	dimension.mutex.RUnlock()
+	if len(viewers) == 0 {
+		return
+	}
+	for category, limit := range spawner.Caps {
+		if spawner.GetMobCount(category) >= limit {
+			continue
+		}
+		var viewer = viewers[dimension.randomIntn(len(viewers))]
+		dimension.spawnMobGroup(spawner, category, limit, viewer.GetPosition(), viewers)
+	}
+}
+
+// spawnMobGroup picks a random position in a loaded chunk around the position, and spawns a group of mobs of the category there.
+func (dimension *Dimension) spawnMobGroup(spawner *MobSpawner, category MobCategory, limit int, position r3.Vector, viewers []PositionedViewer) {
+	var chunkX = int32(math.Floor(position.X))>>4 + int32(dimension.randomIntn(int(spawner.Radius*2+1))) - spawner.Radius
+	var chunkZ = int32(math.Floor(position.Z))>>4 + int32(dimension.randomIntn(int(spawner.Radius*2+1))) - spawner.Radius
+	var chunk, ok = dimension.GetChunk(chunkX, chunkZ)
+	if !ok {
+		return
+	}
+	var x, z = dimension.randomIntn(16), dimension.randomIntn(16)
+	var minY, maxY = chunk.GetSubChunkRange().GetMinY() + 1, int(chunk.GetHighestBlockY(x, z)) + 1
+	if maxY < minY {
+		return
+	}
+	var y = minY + dimension.randomIntn(maxY-minY+1)
+
+	var rule, found = dimension.pickMobRule(spawner, category, chunk.GetBiome(x, z))
+	if !found {
+		return
+	}
+	var size = rule.MinGroup
+	if rule.MaxGroup > rule.MinGroup {
+		size += dimension.randomIntn(rule.MaxGroup - rule.MinGroup + 1)
+	}
+	var worldX, worldZ = int(chunkX)<<4 | x, int(chunkZ)<<4 | z
+	for i := 0; i < size && spawner.GetMobCount(category) < limit; i++ {
+		var mobX, mobZ = worldX, worldZ
+		if i > 0 {
+			mobX += dimension.randomIntn(GroupSpawnSpread*2+1) - GroupSpawnSpread
+			mobZ += dimension.randomIntn(GroupSpawnSpread*2+1) - GroupSpawnSpread
+		}
+		var mobChunk, ok = dimension.GetChunk(int32(mobX>>4), int32(mobZ>>4))
+		if !ok || !mobChunk.IsSpawnable(mobX&15, y, mobZ&15, rule.Constraints) || !rule.spawnsIn(mobChunk.GetBiome(mobX&15, mobZ&15)) {
+			continue
+		}
+		var mobPosition = r3.Vector{X: float64(mobX) + 0.5, Y: float64(y), Z: float64(mobZ) + 0.5}
+		if isNearViewer(mobPosition, viewers, spawner.MinDistance) {
+			continue
+		}
+		if entity, err := dimension.SpawnEntity(rule.Identifier, mobPosition); err == nil && dimension.HasEntity(entity.GetRuntimeId()) {
+			spawner.mutex.Lock()
+			spawner.spawned[entity.GetRuntimeId()] = category
+			spawner.mutex.Unlock()
+		}
+	}
+}
+
+// pickMobRule picks a random rule of the category spawning in the biome, weighted by the weights of the rules.
+// Returns false if no rule of the category spawns in the biome.
+func (dimension *Dimension) pickMobRule(spawner *MobSpawner, category MobCategory, biome byte) (MobSpawnRule, bool) {
+	var total = 0
+	for _, rule := range spawner.Rules {
+		if rule.Category == category && rule.Weight > 0 && rule.spawnsIn(biome) {
+			total += rule.Weight
+		}
+	}
+	if total == 0 {
+		return MobSpawnRule{}, false
+	}
+	var target = dimension.randomIntn(total)
+	for _, rule := range spawner.Rules {
+		if rule.Category != category || rule.Weight <= 0 || !rule.spawnsIn(biome) {
+			continue
+		}
+		if target -= rule.Weight; target < 0 {
+			return rule, true
+		}
+	}
+	return MobSpawnRule{}, false
+}
+
+// randomIntn returns a random int in the range [0, n) from the random of the dimension.
+// The random is used with the dimension locked, as it is not safe for concurrent use.
+func (dimension *Dimension) randomIntn(n int) int {
+	dimension.mutex.Lock()
+	defer dimension.mutex.Unlock()
+	return dimension.random.Intn(n)
+}
+
+// isNearViewer checks if the position is within the distance of any of the viewers.
+func isNearViewer(position r3.Vector, viewers []PositionedViewer, distance float64) bool {
+	for _, viewer := range viewers {
+		if viewer.GetPosition().Sub(position).Norm2() < distance*distance {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package worlds
+
+import (
+	"errors"
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/blocks"
+	"github.com/irmine/worlds/chunks"
+	"math"
+)
+
+// ObstructedPlacement gets returned when a block made up out of multiple parts cannot be placed, as one of its parts is obstructed.
+var ObstructedPlacement = errors.New("block placement is obstructed")
+
+const (
+	// BedId is the block ID of beds.
+	BedId byte = 26
+	// DoublePlantId is the block ID of double plants, such as sunflowers and large ferns.
+	DoublePlantId byte = 175
+)
+
+// doorIds are the block IDs of all doors.
+var doorIds = []byte{64, 71, 193, 194, 195, 196, 197}
+
+// replaceableBlocks are the IDs of blocks that multi blocks may be placed over.
+var replaceableBlocks = map[byte]bool{0: true, 31: true, 32: true, 78: true, 106: true}
+
+// placeParts places all parts of a multi block at once, after checking that every part replaces a replaceable block.
+// Returns UnloadedChunk if a part is in a chunk that is not loaded, chunks.OutOfBoundsPosition if a part is out of the sub chunk range,
+// or ObstructedPlacement if a part is obstructed. No parts are placed if an error is returned.
+func (dimension *Dimension) placeParts(parts []blocks.BatchChange) error {
+	for _, part := range parts {
+		var chunk, ok = dimension.GetChunk(int32(part.X>>4), int32(part.Z>>4))
+		if !ok {
+			return UnloadedChunk
+		}
+		if !chunk.GetSubChunkRange().ContainsY(part.Y) {
+			return chunks.OutOfBoundsPosition
+		}
+		if !replaceableBlocks[chunk.GetBlockIdUnchecked(part.X&15, part.Y, part.Z&15)] {
+			return ObstructedPlacement
+		}
+	}
+	var batch = blocks.NewBatch()
+	for _, part := range parts {
+		batch.Set(part.X, part.Y, part.Z, part.Block)
+	}
+	dimension.SetBlockBatch(batch)
+	return nil
+}
+
+// PlaceDoor places a door with the given block ID at the given vector, facing the direction, with both its lower and upper half.
+// The hinge of the door is on its right side if hingeRight is true.
+// See placeParts for the errors returned.
+func (dimension *Dimension) PlaceDoor(vector r3.Vector, doorId byte, facing blocks.Direction, hingeRight bool) error {
+	var x, y, z = int(math.Floor(vector.X)), int(math.Floor(vector.Y)), int(math.Floor(vector.Z))
+	var upper byte = 0x08
+	if hingeRight {
+		upper |= 0x01
+	}
+	return dimension.placeParts([]blocks.BatchChange{
+		{X: x, Y: y, Z: z, Block: blocks.NewLegacyBlock(doorId, byte(facing+3)%4, nil)},
+		{X: x, Y: y + 1, Z: z, Block: blocks.NewLegacyBlock(doorId, upper, nil)},
+	})
+}
+
+// PlaceBed places a bed with its foot at the given vector, and its head in the direction it faces.
+// See placeParts for the errors returned.
+func (dimension *Dimension) PlaceBed(vector r3.Vector, facing blocks.Direction) error {
+	var x, y, z = int(math.Floor(vector.X)), int(math.Floor(vector.Y)), int(math.Floor(vector.Z))
+	var offsetX, offsetZ = facing.GetOffset()
+	var data = byte(facing+2) % 4
+	return dimension.placeParts([]blocks.BatchChange{
+		{X: x, Y: y, Z: z, Block: blocks.NewLegacyBlock(BedId, data, nil)},
+		{X: x + offsetX, Y: y, Z: z + offsetZ, Block: blocks.NewLegacyBlock(BedId, data|0x08, nil)},
+	})
+}
+
+// PlaceDoublePlant places a double plant of the given type, such as 0 for sunflowers, with its lower half at the given vector.
+// See placeParts for the errors returned.
+func (dimension *Dimension) PlaceDoublePlant(vector r3.Vector, plantType byte) error {
+	var x, y, z = int(math.Floor(vector.X)), int(math.Floor(vector.Y)), int(math.Floor(vector.Z))
+	return dimension.placeParts([]blocks.BatchChange{
+		{X: x, Y: y, Z: z, Block: blocks.NewLegacyBlock(DoublePlantId, plantType&0x07, nil)},
+		{X: x, Y: y + 1, Z: z, Block: blocks.NewLegacyBlock(DoublePlantId, plantType&0x07|0x08, nil)},
+	})
+}
+
+// IsDoor checks if the block with the given ID is a door.
+func IsDoor(blockId byte) bool {
+	for _, id := range doorIds {
+		if id == blockId {
+			return true
+		}
+	}
+	return false
+}
+
+// MultiBlockBehavior is the behavior of blocks made up out of two parts, such as doors, beds and double plants.
+// Breaking either part also removes the other part.
+type MultiBlockBehavior struct {
+	BaseBehavior
+}
+
+// RegisterMultiBlockBehaviors registers the multi block behavior for doors, beds and double plants into the behavior manager.
+func RegisterMultiBlockBehaviors(manager BehaviorManager) {
+	var behavior = MultiBlockBehavior{}
+	for _, id := range doorIds {
+		manager.Register(id, behavior)
+	}
+	manager.Register(BedId, behavior)
+	manager.Register(DoublePlantId, behavior)
+}
+
+// OnBreak removes the other part of the block, if it is still there.
+// Nothing is removed if the block was only changed to other data of the same block, such as when opening a door.
+func (MultiBlockBehavior) OnBreak(dimension *Dimension, x, y, z int, id, data byte) {
+	if currentId, _, ok := dimension.getLoadedBlock(x, y, z); ok && currentId == id {
+		return
+	}
+	var otherX, otherY, otherZ = x, y, z
+	switch {
+	case id == BedId:
+		var offsetX, offsetZ = blocks.Direction((data&0x03 + 2) % 4).GetOffset()
+		if data&0x08 != 0 {
+			offsetX, offsetZ = -offsetX, -offsetZ
+		}
+		otherX, otherZ = x+offsetX, z+offsetZ
+	case data&0x08 != 0:
+		otherY = y - 1
+	default:
+		otherY = y + 1
+	}
+	var otherId, otherData, ok = dimension.getLoadedBlock(otherX, otherY, otherZ)
+	if !ok || otherId != id || otherData&0x08 == data&0x08 {
+		return
+	}
+	// The other part is removed without physics, so that its own behavior does not try to remove this part again.
+	dimension.SetBlockAtWithFlags(r3.Vector{X: float64(otherX), Y: float64(otherY), Z: float64(otherZ)}, blocks.NewLegacyBlock(0, 0, nil), NoPhysics)
+}
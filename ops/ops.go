@@ -0,0 +1,81 @@
+package ops
+
+import (
+	"github.com/irmine/worlds"
+	"github.com/irmine/worlds/blocks"
+	"github.com/irmine/worlds/chunks"
+	"github.com/irmine/worlds/utils"
+)
+
+// Mask checks if a block with the given ID and data gets changed by an operation.
+type Mask func(id, data byte) bool
+
+// AllMask is a mask matching every block.
+func AllMask(id, data byte) bool {
+	return true
+}
+
+// IdMask returns a mask matching all blocks with any of the given block IDs, regardless of their data.
+func IdMask(ids ...byte) Mask {
+	var matching = make(map[byte]bool, len(ids))
+	for _, id := range ids {
+		matching[id] = true
+	}
+	return func(id, data byte) bool {
+		return matching[id]
+	}
+}
+
+// BlockMask returns a mask matching all blocks with the given block ID and data.
+func BlockMask(blockId, blockData byte) Mask {
+	return func(id, data byte) bool {
+		return id == blockId && data == blockData
+	}
+}
+
+// Not returns a mask matching all blocks the given mask does not match.
+func Not(mask Mask) Mask {
+	return func(id, data byte) bool {
+		return !mask(id, data)
+	}
+}
+
+// Fill sets all blocks intersecting the bounding box to the given block, and returns the count of blocks changed.
+// See Replace for how the blocks are changed.
+func Fill(dimension *worlds.Dimension, box utils.AABB, block blocks.Block) (int, error) {
+	return Replace(dimension, box, AllMask, block)
+}
+
+// Replace sets all blocks intersecting the bounding box matching the mask to the given block, and returns the count of blocks changed.
+// Blocks are changed chunk by chunk, without queuing block updates, after which every chunk changed is refreshed for its viewers.
+// Chunks that are not loaded are skipped, in which case worlds.UnloadedChunk is returned with the count of blocks changed.
+func Replace(dimension *worlds.Dimension, box utils.AABB, mask Mask, block blocks.Block) (int, error) {
+	var minX, _, minZ, maxX, _, maxZ = box.GetBlockBounds()
+	var changed int
+	var err error
+	for chunkX := minX >> 4; chunkX <= maxX>>4; chunkX++ {
+		for chunkZ := minZ >> 4; chunkZ <= maxZ>>4; chunkZ++ {
+			var chunk, ok = dimension.GetChunk(int32(chunkX), int32(chunkZ))
+			if !ok {
+				err = worlds.UnloadedChunk
+				continue
+			}
+			var writes []chunks.BlockWrite
+			for _, current := range chunk.GetBlocksInBox(box) {
+				if !mask(current.Id, current.Data) {
+					continue
+				}
+				writes = append(writes, chunks.BlockWrite{
+					X:    current.X & 15,
+					Y:    current.Y,
+					Z:    current.Z & 15,
+					Id:   block.GetId(),
+					Data: block.GetData(),
+					NBT:  block.GetNBT(),
+				})
+			}
+			changed += dimension.SetChunkBlocks(chunk, writes)
+		}
+	}
+	return changed, err
+}
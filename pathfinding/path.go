@@ -0,0 +1,56 @@
+package pathfinding
+
+import (
+	"github.com/golang/geo/r3"
+)
+
+// Path is a path of positions an entity can walk along, from its start to its end.
+// Positions are at the centre of blocks, at the height entities stand at.
+// A path keeps track of the position currently walked to, and is not safe for concurrent use.
+type Path struct {
+	points []r3.Vector
+	index  int
+}
+
+// NewPath returns a new path along the given positions.
+func NewPath(points []r3.Vector) *Path {
+	return &Path{points, 0}
+}
+
+// GetPoints returns all positions of the path.
+func (path *Path) GetPoints() []r3.Vector {
+	return path.points
+}
+
+// GetLength returns the amount of positions in the path.
+func (path *Path) GetLength() int {
+	return len(path.points)
+}
+
+// GetCurrent returns the position currently walked to, and a bool indicating if the path was not yet finished.
+func (path *Path) GetCurrent() (r3.Vector, bool) {
+	if path.IsFinished() {
+		return r3.Vector{}, false
+	}
+	return path.points[path.index], true
+}
+
+// GetEnd returns the last position of the path.
+func (path *Path) GetEnd() r3.Vector {
+	if len(path.points) == 0 {
+		return r3.Vector{}
+	}
+	return path.points[len(path.points)-1]
+}
+
+// Advance advances the path to the next position, after the current position was reached.
+func (path *Path) Advance() {
+	if !path.IsFinished() {
+		path.index++
+	}
+}
+
+// IsFinished checks if all positions of the path were reached.
+func (path *Path) IsFinished() bool {
+	return path.index >= len(path.points)
+}
@@ -0,0 +1,334 @@
+package pathfinding
+
+import (
+	"container/heap"
+	"errors"
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds"
+	"github.com/irmine/worlds/blocks/shapes"
+	"github.com/irmine/worlds/utils"
+	"math"
+)
+
+// NoPath gets returned if no position closer to the destination than the start could be reached.
+var NoPath = errors.New("no path found to the destination")
+
+// PartialPath gets returned with a path to the reachable position closest to the destination, if the destination itself could not be reached.
+var PartialPath = errors.New("destination could not be reached")
+
+// IronDoorId is the block ID of iron doors, which can only be opened by redstone.
+const IronDoorId = 71
+
+// Pathfinder finds paths through the blocks of a dimension for entities walking over them, using A*.
+// Only blocks in loaded chunks are walked over. Paths may be found from any goroutine, as blocks are only read.
+type Pathfinder struct {
+	dimension *worlds.Dimension
+
+	// Height is the height of the entity in blocks, which needs room at every position of a path.
+	Height float64
+	// StepHeight is the maximum height the entity can climb between two positions, by stepping or jumping.
+	StepHeight float64
+	// MaxDrop is the maximum amount of blocks the entity may drop down between two positions.
+	MaxDrop int
+	// CanOpenDoors makes paths go through closed wooden doors. Closed iron doors are never passed.
+	CanOpenDoors bool
+	// CanSwim makes paths go through water. Paths never go through lava.
+	CanSwim bool
+	// WaterCost is the additional cost of moving through water, making paths prefer land.
+	WaterCost float64
+	// MaxNodes is the maximum amount of positions visited before giving up on finding the destination.
+	MaxNodes int
+}
+
+// NewPathfinder returns a new pathfinder for the dimension, with defaults for entities the size of a player.
+func NewPathfinder(dimension *worlds.Dimension) *Pathfinder {
+	return &Pathfinder{dimension, 1.8, 1.125, 3, false, true, 2, 4096}
+}
+
+// GetDimension returns the dimension the pathfinder finds paths in.
+func (pathfinder *Pathfinder) GetDimension() *worlds.Dimension {
+	return pathfinder.dimension
+}
+
+// node is a position visited while finding a path, with the height the entity stands at.
+type node struct {
+	x, y, z   int
+	floor     float64
+	cost      float64
+	estimate  float64
+	parent    *node
+	heapIndex int
+}
+
+// nodeHeap is a heap of nodes, ordered by their estimated total cost.
+type nodeHeap []*node
+
+func (nodes nodeHeap) Len() int { return len(nodes) }
+
+func (nodes nodeHeap) Less(i, j int) bool {
+	return nodes[i].cost+nodes[i].estimate < nodes[j].cost+nodes[j].estimate
+}
+
+func (nodes nodeHeap) Swap(i, j int) {
+	nodes[i], nodes[j] = nodes[j], nodes[i]
+	nodes[i].heapIndex, nodes[j].heapIndex = i, j
+}
+
+func (nodes *nodeHeap) Push(n interface{}) {
+	n.(*node).heapIndex = len(*nodes)
+	*nodes = append(*nodes, n.(*node))
+}
+
+func (nodes *nodeHeap) Pop() interface{} {
+	var old = *nodes
+	var n = old[len(old)-1]
+	*nodes = old[:len(old)-1]
+	n.heapIndex = -1
+	return n
+}
+
+// getPosition returns the position of the node entities walk to.
+func (n *node) getPosition() r3.Vector {
+	return r3.Vector{X: float64(n.x) + 0.5, Y: n.floor, Z: float64(n.z) + 0.5}
+}
+
+// FindPath finds a path from the start to the destination.
+// If the destination cannot be reached, a path to the reachable position closest to it gets returned with PartialPath.
+// NoPath gets returned if no position closer to the destination than the start can be reached.
+func (pathfinder *Pathfinder) FindPath(start, destination r3.Vector) (*Path, error) {
+	var startX, startY, startZ = int(math.Floor(start.X)), int(math.Floor(start.Y)), int(math.Floor(start.Z))
+	var endX, endY, endZ = int(math.Floor(destination.X)), int(math.Floor(destination.Y)), int(math.Floor(destination.Z))
+	var estimate = func(x, y, z int) float64 {
+		return math.Sqrt(float64((x-endX)*(x-endX) + (y-endY)*(y-endY) + (z-endZ)*(z-endZ)))
+	}
+
+	var startFloor, ok = pathfinder.getFloor(startX, startY, startZ)
+	if !ok {
+		// The entity may be in the air, in which case the path starts where it is.
+		startFloor = start.Y
+	}
+	var first = &node{startX, startY, startZ, startFloor, 0, estimate(startX, startY, startZ), nil, 0}
+	var closest = first
+	var visited = map[[3]int]*node{{startX, startY, startZ}: first}
+	var closed = make(map[[3]int]bool)
+	var open = &nodeHeap{first}
+
+	for open.Len() > 0 && len(closed) < pathfinder.MaxNodes {
+		var current = heap.Pop(open).(*node)
+		if current.x == endX && current.y == endY && current.z == endZ {
+			return buildPath(current), nil
+		}
+		closed[[3]int{current.x, current.y, current.z}] = true
+		if current.estimate < closest.estimate {
+			closest = current
+		}
+
+		for _, neighbour := range pathfinder.getNeighbours(current) {
+			var key = [3]int{neighbour.x, neighbour.y, neighbour.z}
+			if closed[key] {
+				continue
+			}
+			if existing, ok := visited[key]; ok {
+				if neighbour.cost < existing.cost {
+					existing.cost, existing.floor, existing.parent = neighbour.cost, neighbour.floor, current
+					heap.Fix(open, existing.heapIndex)
+				}
+				continue
+			}
+			neighbour.estimate = estimate(neighbour.x, neighbour.y, neighbour.z)
+			visited[key] = neighbour
+			heap.Push(open, neighbour)
+		}
+	}
+	if closest == first {
+		return nil, NoPath
+	}
+	return buildPath(closest), PartialPath
+}
+
+// FindPathAsync finds a path from the start to the destination on a new goroutine, so that the tick of the dimension is not held up.
+// The function gets called with the result of FindPath on the tick of the dimension once done, so it can safely modify the dimension.
+func (pathfinder *Pathfinder) FindPathAsync(start, destination r3.Vector, function func(path *Path, err error)) {
+	go func() {
+		var path, err = pathfinder.FindPath(start, destination)
+		pathfinder.dimension.ScheduleTask(1, func() {
+			function(path, err)
+		})
+	}()
+}
+
+// buildPath builds a path from the positions leading to the node, excluding the start.
+func buildPath(end *node) *Path {
+	var points []r3.Vector
+	for n := end; n.parent != nil; n = n.parent {
+		points = append(points, n.getPosition())
+	}
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	return NewPath(points)
+}
+
+// directions are the horizontal offsets of all neighbours of a column, with the straight directions first.
+var directions = [8][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+
+// getNeighbours returns the nodes the entity can move to from the given node, with their cost set.
+func (pathfinder *Pathfinder) getNeighbours(current *node) []*node {
+	var neighbours []*node
+	for _, direction := range directions {
+		var x, z = current.x + direction[0], current.z + direction[1]
+		for y := current.y + int(math.Ceil(pathfinder.StepHeight)); y >= current.y-pathfinder.MaxDrop; y-- {
+			var floor, ok = pathfinder.getFloor(x, y, z)
+			if !ok || !pathfinder.canMove(current, x, z, floor, direction) {
+				continue
+			}
+			var cost = math.Hypot(float64(direction[0]), float64(direction[1])) + math.Abs(floor-current.floor)*0.5
+			neighbours = append(neighbours, &node{x, y, z, floor, current.cost + cost + pathfinder.getExtraCost(x, y, z), 0, current, 0})
+			break
+		}
+	}
+	if pathfinder.isWater(current.x, current.y, current.z) {
+		// Swimming entities may move straight up and down through water.
+		for _, y := range [2]int{current.y + 1, current.y - 1} {
+			if floor, ok := pathfinder.getFloor(current.x, y, current.z); ok && pathfinder.hasRoom(current.x, current.z, math.Min(floor, current.floor), math.Max(floor, current.floor)+pathfinder.Height) {
+				neighbours = append(neighbours, &node{current.x, y, current.z, floor, current.cost + 1 + pathfinder.getExtraCost(current.x, y, current.z), 0, current, 0})
+			}
+		}
+	}
+	return neighbours
+}
+
+// canMove checks if the entity can move from the node to the given floor in the neighbouring column in the direction.
+// The entity climbs in its own column and drops in the neighbouring column, so both need room, and diagonal moves may not cut corners.
+func (pathfinder *Pathfinder) canMove(current *node, x, z int, floor float64, direction [2]int) bool {
+	if floor-current.floor > pathfinder.StepHeight || current.floor-floor > float64(pathfinder.MaxDrop) {
+		return false
+	}
+	var high = math.Max(floor, current.floor) + pathfinder.Height
+	if !pathfinder.hasRoom(x, z, floor, high) || !pathfinder.hasRoom(current.x, current.z, current.floor, high) {
+		return false
+	}
+	if direction[0] != 0 && direction[1] != 0 {
+		var low = math.Max(floor, current.floor)
+		return pathfinder.hasRoom(current.x+direction[0], current.z, low, high) && pathfinder.hasRoom(current.x, current.z+direction[1], low, high)
+	}
+	return true
+}
+
+// getExtraCost returns the additional cost of moving to the given position.
+func (pathfinder *Pathfinder) getExtraCost(x, y, z int) float64 {
+	if pathfinder.isWater(x, y, z) {
+		return pathfinder.WaterCost
+	}
+	return 0
+}
+
+// getFloor returns the height the entity stands at with its feet in the block at the given position, and a bool indicating if it can stand there.
+// Entities stand on top of blocks below, or on blocks at their feet at most half a block high, such as slabs and carpet.
+// Entities able to swim stay afloat in water.
+func (pathfinder *Pathfinder) getFloor(x, y, z int) (float64, bool) {
+	var boxes, ok = pathfinder.getBoxes(x, y, z)
+	if !ok {
+		return 0, false
+	}
+	var floor float64
+	switch {
+	case len(boxes) != 0:
+		var top = getTop(boxes)
+		if top > 0.5 {
+			return 0, false
+		}
+		floor = float64(y) + top
+	case pathfinder.isWater(x, y, z) || pathfinder.isWater(x, y-1, z):
+		floor = float64(y)
+	default:
+		var below, ok = pathfinder.getBoxes(x, y-1, z)
+		if !ok || len(below) == 0 {
+			return 0, false
+		}
+		var top = getTop(below)
+		if top < 1 {
+			return 0, false
+		}
+		floor = float64(y-1) + top
+	}
+	return floor, pathfinder.hasRoom(x, z, floor, floor+pathfinder.Height)
+}
+
+// hasRoom checks if no block in the column collides with the space between the two heights.
+func (pathfinder *Pathfinder) hasRoom(x, z int, low, high float64) bool {
+	for y := int(math.Floor(low)); float64(y) < high; y++ {
+		var boxes, ok = pathfinder.getBoxes(x, y, z)
+		if !ok {
+			return false
+		}
+		for _, box := range boxes {
+			if float64(y)+box.Max.Y > low && float64(y)+box.Min.Y < high {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// getBoxes returns the collision boxes of the block at the given position, relative to the block, and a bool indicating if the entity may enter it.
+// Doors the entity can open have no collision. Lava, water if the entity cannot swim, and blocks in chunks that are not loaded may not be entered.
+func (pathfinder *Pathfinder) getBoxes(x, y, z int) ([]utils.AABB, bool) {
+	var id, data, ok = pathfinder.getBlock(x, y, z)
+	switch {
+	case !ok || id == 10 || id == 11:
+		return nil, false
+	case id == 8 || id == 9:
+		return nil, pathfinder.CanSwim
+	case worlds.IsDoor(id):
+		if pathfinder.isDoorPassable(x, y, z, id, data) {
+			return nil, true
+		}
+		return []utils.AABB{{Max: r3.Vector{X: 1, Y: 1, Z: 1}}}, true
+	}
+	return shapes.GetBoxes(id, data), true
+}
+
+// isDoorPassable checks if the door at the given position is open, or can be opened by the entity.
+// The open state of a door is stored in the lower half, which the upper half has the 0x08 bit set for.
+func (pathfinder *Pathfinder) isDoorPassable(x, y, z int, id, data byte) bool {
+	if pathfinder.CanOpenDoors && id != IronDoorId {
+		return true
+	}
+	if data&0x08 != 0 {
+		var lowerId, lowerData, ok = pathfinder.getBlock(x, y-1, z)
+		if !ok || lowerId != id {
+			return false
+		}
+		data = lowerData
+	}
+	return data&0x04 != 0
+}
+
+// isWater checks if the block at the given position is water.
+func (pathfinder *Pathfinder) isWater(x, y, z int) bool {
+	var id, _, ok = pathfinder.getBlock(x, y, z)
+	return ok && (id == 8 || id == 9)
+}
+
+// getBlock returns the block ID and data at the given position, and a bool indicating if its chunk is loaded.
+// Positions outside of the sub chunk range of a loaded chunk are air.
+func (pathfinder *Pathfinder) getBlock(x, y, z int) (byte, byte, bool) {
+	var chunk, ok = pathfinder.dimension.GetChunk(int32(x>>4), int32(z>>4))
+	if !ok {
+		return 0, 0, false
+	}
+	if !chunk.GetSubChunkRange().ContainsY(y) {
+		return 0, 0, true
+	}
+	return chunk.GetBlockIdUnchecked(x&15, y, z&15), chunk.GetBlockDataUnchecked(x&15, y, z&15), true
+}
+
+// getTop returns the highest point of the boxes.
+func getTop(boxes []utils.AABB) float64 {
+	var top float64
+	for _, box := range boxes {
+		top = math.Max(top, box.Max.Y)
+	}
+	return top
+}
@@ -1,12 +1,18 @@
 package providers
 
 import (
+	"context"
+	"errors"
 	"github.com/irmine/binutils"
+	"github.com/irmine/gomine/text"
 	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/chunks"
 	"github.com/irmine/worlds/io"
+	"io/ioutil"
 	"os"
 	"strconv"
 	"sync"
+	"time"
 )
 
 // Anvil is a provider for the MCAnvil world format.
@@ -15,47 +21,78 @@ type Anvil struct {
 	path string
 	*ChunkProvider
 
+	// CorruptionFunction gets called for every corrupted chunk found while loading.
+	// The corrupted chunk data has already been copied to the recovery path when called,
+	// and the chunk gets regenerated afterwards.
+	CorruptionFunction func(x, z int32, err error)
+	// LockRegions makes the provider place an advisory lock on every region file it opens,
+	// so that other processes cannot open the same regions. Chunk requests for regions locked by another process get rejected.
+	LockRegions bool
+	// Compression is the compression chunks get written to regions with. It defaults to io.CompressionZlib.
+	Compression io.CompressionType
+
 	mutex         sync.RWMutex
 	regions       map[int]*io.Region
+	closed        bool
+
+	// saveMutex is held while saving, so that regions are not closed while they are being saved.
+	saveMutex sync.Mutex
 }
 
+// UnreadableChunk gets returned if the NBT of chunk data could not be read.
+var UnreadableChunk = errors.New("chunk data could not be read as NBT")
+
 // NewAnvil returns an anvil chunk provider writing and reading regions from the given path.
 func NewAnvil(path string) *Anvil {
 	var provider = &Anvil{
 		path,
 		NewChunkProvider(),
+		func(x, z int32, err error) {},
+		false,
+		io.CompressionZlib,
 		sync.RWMutex{},
 		make(map[int]*io.Region),
+		false,
+		sync.Mutex{},
 	}
 	go provider.Process()
 	return provider
 }
 
 // Process continuously processes chunk requests for chunks that were not yet loaded when requested.
+// Process returns once the provider starts closing.
 func (provider *Anvil) Process() {
+	defer close(provider.stopped)
 	for {
-		var request= <-provider.requests
-		if provider.IsChunkLoaded(request.x, request.z) {
-			provider.completeRequest(request)
-			continue
+		select {
+		case <-provider.closing:
+			return
+		case request := <-provider.requests:
+			provider.process(request)
 		}
-		go func() {
-			var regionX, regionZ = request.x>>5, request.z>>5
-			if provider.IsRegionLoaded(regionX, regionZ) {
-				provider.load(request, regionX, regionZ)
-			} else {
-				var path = provider.path + "r." + strconv.Itoa(int(regionX)) + "." + strconv.Itoa(int(regionZ)) + ".mca"
-				var _, err = os.Stat(path)
-				if err != nil {
-					os.Create(path)
-				}
-				provider.OpenRegion(regionX, regionZ, path)
-				provider.load(request, regionX, regionZ)
-			}
-		}()
 	}
 }
 
+// process processes a single chunk request, loading the chunk asynchronously if needed.
+func (provider *Anvil) process(request ChunkRequest) {
+	if provider.IsChunkLoaded(request.x, request.z) {
+		provider.completeRequest(request)
+		return
+	}
+	provider.pending.Add(1)
+	go func() {
+		defer provider.pending.Done()
+		var regionX, regionZ = request.x>>5, request.z>>5
+		if !provider.IsRegionLoaded(regionX, regionZ) {
+			if err := provider.openRegion(regionX, regionZ, provider.GetRegionPath(regionX, regionZ)); err != nil {
+				provider.rejectRequest(request, err)
+				return
+			}
+		}
+		provider.load(request, regionX, regionZ)
+	}()
+}
+
 // load loads a chunk at the given region X and Z for the given request.
 func (provider *Anvil) load(request ChunkRequest, regionX, regionZ int32) {
 	var region, _ = provider.GetRegion(regionX, regionZ)
@@ -65,21 +102,143 @@ func (provider *Anvil) load(request ChunkRequest, regionX, regionZ int32) {
 		return
 	}
 
-	var compression, data = region.GetChunkData(request.x, request.z)
-
-	var reader = gonbt.NewReader(data, false, binutils.BigEndian)
-	var c = reader.ReadIntoCompound(int(compression))
+	var start = time.Now()
+	var compression, raw = region.GetRawChunkData(request.x, request.z)
+	var data, err = io.Decompress(compression, raw)
+	var c *gonbt.Compound
+	if err == nil {
+		c, err = readChunkCompound(data)
+	}
+	if err == nil {
+		err = io.ValidateAnvilChunk(c, request.x, request.z)
+	}
 
-	if c == nil {
+	if err != nil {
+		provider.handleCorruption(request.x, request.z, compression, raw, err)
 		provider.GenerateChunk(request.x, request.z)
 		provider.completeRequest(request)
 		return
 	}
 
-	provider.SetChunk(request.x, request.z, io.GetAnvilChunkFromNBT(c))
+	provider.SetChunk(request.x, request.z, io.GetAnvilChunkFromNBTWithRange(c, provider.GetSubChunkRange()))
+	provider.reportSlow("load", provider.SlowThresholds.Load, start, request.x, request.z)
 	provider.completeRequest(request)
 }
 
+// readChunkCompound reads the given decompressed chunk data into a compound.
+// Returns UnreadableChunk if the data could not be read.
+func readChunkCompound(data []byte) (compound *gonbt.Compound, err error) {
+	defer func() {
+		if recover() != nil {
+			compound, err = nil, UnreadableChunk
+		}
+	}()
+	var reader = gonbt.NewReader(data, false, binutils.BigEndian)
+	// The data is already decompressed by the region, so no compression is passed.
+	compound = reader.ReadIntoCompound(0)
+	if compound == nil {
+		return nil, UnreadableChunk
+	}
+	return compound, nil
+}
+
+// handleCorruption logs a corrupted chunk at the given chunk X and Z,
+// copies its data to the recovery path and calls the CorruptionFunction.
+func (provider *Anvil) handleCorruption(x, z int32, compression io.CompressionType, data []byte, err error) {
+	text.DefaultLogger.Error("Corrupted chunk at", x, z, "in", provider.path+":", err)
+
+	var path = provider.GetRecoveryPath()
+	os.MkdirAll(path, 0700)
+	// Every copy gets a unique name, so that chunks corrupted more than once never overwrite earlier copies.
+	var file, createErr = ioutil.TempFile(path, "c."+strconv.Itoa(int(x))+"."+strconv.Itoa(int(z))+".*.bin")
+	if createErr != nil {
+		text.DefaultLogger.Error("Could not create a recovery file in", path+":", createErr)
+	} else {
+		if _, writeErr := file.Write(append([]byte{byte(compression)}, data...)); writeErr != nil {
+			text.DefaultLogger.Error("Could not write corrupted chunk data to", file.Name()+":", writeErr)
+		}
+		file.Close()
+	}
+
+	provider.CorruptionFunction(x, z, err)
+}
+
+// GetRecoveryPath returns the path corrupted chunk data gets copied to.
+// Every file in it starts with the compression type byte, followed by the raw chunk data.
+func (provider *Anvil) GetRecoveryPath() string {
+	return provider.path + "corrupted/"
+}
+
+// GetRegionPath returns the path of the region file with the given region X and Z.
+func (provider *Anvil) GetRegionPath(regionX, regionZ int32) string {
+	return provider.path + "r." + strconv.Itoa(int(regionX)) + "." + strconv.Itoa(int(regionZ)) + ".mca"
+}
+
+// UnloadChunk writes the chunk with the given chunk X and Z to its region if it is dirty, and unloads it.
+// The chunk is written before it gets unloaded, as its sub chunks are released when unloading.
+func (provider *Anvil) UnloadChunk(x, z int32) {
+	if chunk, ok := provider.GetChunk(x, z); ok {
+		if err := provider.saveChunk(chunk); err != nil {
+			text.DefaultLogger.Error("Could not save chunk at", x, z, "in", provider.path+":", err)
+		}
+	}
+	provider.ChunkProvider.UnloadChunk(x, z)
+}
+
+// saveChunk writes the chunk to its region if it is dirty, opening the region if needed.
+// The chunk is marked clean before it gets encoded, so that changes made while writing it mark it dirty again.
+func (provider *Anvil) saveChunk(chunk *chunks.Chunk) error {
+	if !chunk.IsDirty() {
+		return nil
+	}
+	var regionX, regionZ = chunk.X >> 5, chunk.Z >> 5
+	if err := provider.openRegion(regionX, regionZ, provider.GetRegionPath(regionX, regionZ)); err != nil {
+		return err
+	}
+	var region, ok = provider.GetRegion(regionX, regionZ)
+	if !ok {
+		return ClosedProvider
+	}
+	chunk.SetDirty(false)
+	if err := region.WriteCompressedChunkData(chunk.X, chunk.Z, io.EncodeAnvilChunk(chunk, provider.GetCurrentTick()), provider.Compression); err != nil {
+		chunk.SetDirty(true)
+		return err
+	}
+	return nil
+}
+
+// saveChunks writes all dirty loaded chunks to their regions.
+func (provider *Anvil) saveChunks() {
+	for _, chunk := range provider.GetLoadedChunks() {
+		if err := provider.saveChunk(chunk); err != nil {
+			text.DefaultLogger.Error("Could not save chunk at", chunk.X, chunk.Z, "in", provider.path+":", err)
+		}
+	}
+}
+
+// DeleteChunk unloads the chunk at the given chunk X and Z without writing it, and deletes it from its region.
+// The chunk gets regenerated the next time it is loaded.
+// Returns the error opening the region if it could not be opened, such as io.WorldInUse if another process has it locked.
+func (provider *Anvil) DeleteChunk(x, z int32) error {
+	provider.ChunkProvider.UnloadChunk(x, z)
+	var regionX, regionZ = x>>5, z>>5
+	if !provider.IsRegionLoaded(regionX, regionZ) {
+		var path = provider.GetRegionPath(regionX, regionZ)
+		if _, err := os.Stat(path); err != nil {
+			return nil
+		}
+		if err := provider.openRegion(regionX, regionZ, path); err != nil {
+			return err
+		}
+	}
+	var region, ok = provider.GetRegion(regionX, regionZ)
+	if !ok {
+		return ClosedProvider
+	}
+	region.DeleteChunk(x, z)
+	return nil
+}
+
 // IsRegionLoaded checks if a region with the given region X and Z is loaded.
 func (provider *Anvil) IsRegionLoaded(regionX, regionZ int32) bool {
 	provider.mutex.RLock()
@@ -97,34 +256,142 @@ func (provider *Anvil) GetRegion(regionX, regionZ int32) (*io.Region, bool) {
 }
 
 // OpenRegion opens a region file at the given region X and Z in the given path.
-// OpenRegion creates a region file if it did not yet exist.
+// OpenRegion creates a region file if it did not yet exist. Errors opening the region get logged.
 func (provider *Anvil) OpenRegion(regionX, regionZ int32, path string) {
-	var region, _ = io.OpenRegion(path)
+	if err := provider.openRegion(regionX, regionZ, path); err != nil {
+		text.DefaultLogger.Error("Could not open region", path+":", err)
+	}
+}
+
+// openRegion opens a region file at the given region X and Z in the given path, unless the region is already loaded.
+// The region file is created if it did not yet exist.
+// The region file gets locked if LockRegions is set, in which case io.WorldInUse is returned if another process has it locked.
+// ClosedProvider is returned if the regions of the provider were already closed.
+func (provider *Anvil) openRegion(regionX, regionZ int32, path string) error {
 	provider.mutex.Lock()
-	provider.regions[provider.GetChunkIndex(regionX, regionZ)] = region
-	provider.mutex.Unlock()
+	defer provider.mutex.Unlock()
+	if provider.closed {
+		return ClosedProvider
+	}
+	var index = provider.GetChunkIndex(regionX, regionZ)
+	if _, ok := provider.regions[index]; ok {
+		return nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		var file, err = os.Create(path)
+		if err != nil {
+			return err
+		}
+		file.Close()
+	}
+	var open = io.OpenRegion
+	if provider.LockRegions {
+		open = io.OpenLockedRegion
+	}
+	var region, err = open(path)
+	if err != nil {
+		return err
+	}
+	provider.regions[index] = region
+	return nil
 }
 
 // Close closes the provider and saves all chunks.
+// Pending chunk requests get completed before the regions are closed.
 func (provider *Anvil) Close(async bool) {
-	var c = func() {
-		for index, region := range provider.regions {
-			region.Close(true)
-			delete(provider.regions, index)
-		}
-	}
 	if async {
-		go c()
+		go provider.CloseContext(context.Background())
 	} else {
-		c()
+		provider.CloseContext(context.Background())
 	}
 }
 
-// Save saves all regions in the provider.
+// CloseContext stops processing chunk requests, completes pending requests, writes all dirty chunks and closes all regions.
+// Requests still pending when the context is done get passed to the RejectionFunction, and the error of the context is returned.
+// Returns ClosedProvider if the provider was already closed.
+func (provider *Anvil) CloseContext(ctx context.Context) error {
+	if !provider.stopProcessing() {
+		return ClosedProvider
+	}
+	<-provider.stopped
+
+	var err error
+	for draining := true; draining; {
+		select {
+		case request := <-provider.requests:
+			if ctx.Err() != nil {
+				err = ctx.Err()
+				provider.rejectRequest(request, err)
+				continue
+			}
+			provider.process(request)
+		default:
+			draining = false
+		}
+	}
+	if waitErr := provider.waitPending(ctx); waitErr != nil {
+		err = waitErr
+	}
+
+	// Saves still in progress are waited for, so that no region gets closed while it is being saved.
+	provider.saveMutex.Lock()
+	defer provider.saveMutex.Unlock()
+	provider.saveChunks()
+
+	provider.mutex.Lock()
+	for index, region := range provider.regions {
+		if closeErr := region.Close(true); closeErr != nil {
+			text.DefaultLogger.Error("Could not save region", region.GetPath()+":", closeErr)
+		}
+		delete(provider.regions, index)
+	}
+	provider.closed = true
+	provider.mutex.Unlock()
+	return err
+}
+
+// Defragment defragments all loaded regions of the provider, returning the combined statistics.
+// Defragmenting is expensive, and should only be done when the server is not busy.
+func (provider *Anvil) Defragment() (io.DefragmentStats, error) {
+	var stats io.DefragmentStats
+	provider.mutex.RLock()
+	defer provider.mutex.RUnlock()
+	for _, region := range provider.regions {
+		var regionStats, err = region.Defragment()
+		stats.BytesReclaimed += regionStats.BytesReclaimed
+		stats.ChunksMoved += regionStats.ChunksMoved
+		if err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+// getRegions returns a copy of the loaded regions of the provider, keyed by their region index.
+func (provider *Anvil) getRegions() map[int]*io.Region {
+	provider.mutex.RLock()
+	defer provider.mutex.RUnlock()
+	var regions = make(map[int]*io.Region, len(provider.regions))
+	for index, region := range provider.regions {
+		regions[index] = region
+	}
+	return regions
+}
+
+// Save writes all dirty loaded chunks to their regions, and saves all regions in the provider.
+// Slow region saves get reported with the chunk X and Z of the first chunk in the region. Closing the provider waits for saves in progress.
 func (provider *Anvil) Save() {
 	go func() {
-		for _, region := range provider.regions {
-			region.Save()
+		provider.saveMutex.Lock()
+		defer provider.saveMutex.Unlock()
+		provider.saveChunks()
+		for index, region := range provider.getRegions() {
+			var start = time.Now()
+			if err := region.Save(); err != nil {
+				text.DefaultLogger.Error("Could not save region", region.GetPath()+":", err)
+			}
+			var x, z = provider.GetChunkXZ(index)
+			provider.reportSlow("region save", provider.SlowThresholds.Save, start, int32(x)<<5, int32(z)<<5)
 		}
 	}()
 }
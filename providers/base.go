@@ -1,31 +1,61 @@
 package providers
 
 import (
+	"context"
+	"errors"
 	"github.com/irmine/worlds/chunks"
 	"github.com/irmine/worlds/generation"
 	"sync"
+	"time"
 )
 
 // Provider is the interface used to manage chunks and generators.
 type Provider interface {
 	Save()
 	Close(bool)
+	CloseContext(context.Context) error
 	LoadChunk(int32, int32, func(*chunks.Chunk))
 	IsChunkLoaded(int32, int32) bool
 	UnloadChunk(int32, int32)
+	DeleteChunk(int32, int32) error
 	SetChunk(int32, int32, *chunks.Chunk)
 	GetChunk(int32, int32) (*chunks.Chunk, bool)
+	GetLoadedChunks() []*chunks.Chunk
+	GetLoadedChunkCount() int
+	GetPendingRequestCount() int
+	SetSubChunkRange(chunks.SubChunkRange)
+	GetSubChunkRange() chunks.SubChunkRange
 	SetGenerator(generation.Generator)
 	GetGenerator() generation.Generator
 	GenerateChunk(int32, int32)
 	GetChunkIndex(x, z int32) int
 	GetChunkXZ(hash int) (int, int)
+	SetTickFunction(func() int64)
 }
 
 // ChunkProvider implements the Provider interface, implementing basic functionality of a chunk provider.
 type ChunkProvider struct {
-	generator generation.Generator
-	requests  chan ChunkRequest
+	// SlowThresholds are the thresholds used to report slow chunk operations.
+	SlowThresholds SlowThresholds
+	// SlowFunction gets called for every chunk operation that took longer than its slow threshold.
+	// It defaults to LogSlow, which logs the operation with the default logger.
+	SlowFunction func(operation string, x, z int32, duration time.Duration)
+	// RejectionFunction gets called for every chunk request that gets rejected instead of completed.
+	// The error is ClosingProvider for requests made while the provider is closing,
+	// or the error of the close context for requests still pending when it was done.
+	RejectionFunction func(x, z int32, err error)
+
+	generator     generation.Generator
+	subChunkRange chunks.SubChunkRange
+	requests      chan ChunkRequest
+	tickFunction  func() int64
+
+	// requestMutex makes sure no request gets enqueued once the provider started closing.
+	requestMutex sync.RWMutex
+
+	closing chan struct{}
+	stopped chan struct{}
+	pending sync.WaitGroup
 
 	mutex  sync.RWMutex
 	chunks map[int]*chunks.Chunk
@@ -38,20 +68,87 @@ type ChunkRequest struct {
 	z        int32
 }
 
+// ClosedProvider gets returned when trying to close a provider that is already closed.
+var ClosedProvider = errors.New("chunk provider is already closed")
+
+// ClosingProvider gets passed to the RejectionFunction for chunk requests made while the provider is closing.
+var ClosingProvider = errors.New("chunk provider is closing")
+
 // New returns a NewChunkProvider chunk provider.
 func NewChunkProvider() *ChunkProvider {
-	return &ChunkProvider{requests: make(chan ChunkRequest, 4096), chunks: make(map[int]*chunks.Chunk)}
+	return &ChunkProvider{
+		SlowThresholds:    DefaultSlowThresholds,
+		SlowFunction:      LogSlow,
+		RejectionFunction: func(x, z int32, err error) {},
+		subChunkRange:     chunks.DefaultSubChunkRange,
+		requests:          make(chan ChunkRequest, 4096),
+		tickFunction:      func() int64 { return 0 },
+		closing:           make(chan struct{}),
+		stopped:           make(chan struct{}),
+		chunks:            make(map[int]*chunks.Chunk),
+	}
 }
 
 // LoadChunk loads the chunk at the given chunk X and Z.
 // The function provided will run with the loaded chunk once done.
 // The function gets ran immediately if the chunk is already loaded.
+// Requests made after the provider started closing are passed to the RejectionFunction, and their function never runs.
 func (provider *ChunkProvider) LoadChunk(x, z int32, function func(*chunks.Chunk)) {
 	if chunk, ok := provider.GetChunk(x, z); ok {
 		function(chunk)
 		return
 	}
+	provider.requestMutex.RLock()
+	if provider.IsClosing() {
+		provider.requestMutex.RUnlock()
+		provider.RejectionFunction(x, z, ClosingProvider)
+		return
+	}
 	provider.requests <- ChunkRequest{function, x, z}
+	provider.requestMutex.RUnlock()
+}
+
+// IsClosing checks if the provider has started closing.
+// A closing provider no longer accepts new chunk requests.
+func (provider *ChunkProvider) IsClosing() bool {
+	select {
+	case <-provider.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+// stopProcessing signals the request processor to stop.
+// Returns false if the provider was already closing.
+func (provider *ChunkProvider) stopProcessing() bool {
+	provider.requestMutex.Lock()
+	defer provider.requestMutex.Unlock()
+	if provider.IsClosing() {
+		return false
+	}
+	close(provider.closing)
+	return true
+}
+
+// rejectRequest rejects the given request with the given error, passing it to the RejectionFunction.
+func (provider *ChunkProvider) rejectRequest(request ChunkRequest, err error) {
+	provider.RejectionFunction(request.x, request.z, err)
+}
+
+// waitPending waits for all requests currently being processed to finish, or the context to be done.
+func (provider *ChunkProvider) waitPending(ctx context.Context) error {
+	var done = make(chan struct{})
+	go func() {
+		provider.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // IsChunkLoaded checks if a chunk is loaded at the given chunk X and Z.
@@ -63,10 +160,15 @@ func (provider *ChunkProvider) IsChunkLoaded(x, z int32) bool {
 }
 
 // UnloadChunk unloads a chunk with the given chunk X and Z if loaded.
+// The sub chunks of the unloaded chunk are released to be reused, so the chunk should no longer be used.
 func (provider *ChunkProvider) UnloadChunk(x, z int32) {
 	provider.mutex.Lock()
+	var chunk, ok = provider.chunks[provider.GetChunkIndex(x, z)]
 	delete(provider.chunks, provider.GetChunkIndex(x, z))
 	provider.mutex.Unlock()
+	if ok {
+		chunk.Release()
+	}
 }
 
 // SetChunk sets a chunk at the given chunk X and Z.
@@ -85,6 +187,29 @@ func (provider *ChunkProvider) GetChunk(x, z int32) (*chunks.Chunk, bool) {
 	return chunk, ok
 }
 
+// GetLoadedChunks returns all chunks currently loaded in the provider.
+func (provider *ChunkProvider) GetLoadedChunks() []*chunks.Chunk {
+	provider.mutex.RLock()
+	var loaded = make([]*chunks.Chunk, 0, len(provider.chunks))
+	for _, chunk := range provider.chunks {
+		loaded = append(loaded, chunk)
+	}
+	provider.mutex.RUnlock()
+	return loaded
+}
+
+// GetLoadedChunkCount returns the count of chunks currently loaded in the provider.
+func (provider *ChunkProvider) GetLoadedChunkCount() int {
+	provider.mutex.RLock()
+	defer provider.mutex.RUnlock()
+	return len(provider.chunks)
+}
+
+// GetPendingRequestCount returns the count of chunk requests waiting to be processed.
+func (provider *ChunkProvider) GetPendingRequestCount() int {
+	return len(provider.requests)
+}
+
 // SetGenerator sets the generator of the provider.
 func (provider *ChunkProvider) SetGenerator(generator generation.Generator) {
 	provider.generator = generator
@@ -95,6 +220,37 @@ func (provider *ChunkProvider) GetGenerator() generation.Generator {
 	return provider.generator
 }
 
+// SetTickFunction sets the function returning the current tick of the level the provider holds chunks of.
+// Scheduled ticks of chunks are saved relative to the current tick.
+func (provider *ChunkProvider) SetTickFunction(function func() int64) {
+	provider.mutex.Lock()
+	provider.tickFunction = function
+	provider.mutex.Unlock()
+}
+
+// GetCurrentTick returns the current tick of the level the provider holds chunks of, as returned by the tick function.
+func (provider *ChunkProvider) GetCurrentTick() int64 {
+	provider.mutex.RLock()
+	var function = provider.tickFunction
+	provider.mutex.RUnlock()
+	return function()
+}
+
+// SetSubChunkRange sets the range of sub chunks of chunks generated and loaded by the provider.
+// Chunks already loaded keep their range.
+func (provider *ChunkProvider) SetSubChunkRange(subChunkRange chunks.SubChunkRange) {
+	provider.mutex.Lock()
+	provider.subChunkRange = subChunkRange
+	provider.mutex.Unlock()
+}
+
+// GetSubChunkRange returns the range of sub chunks of chunks generated and loaded by the provider.
+func (provider *ChunkProvider) GetSubChunkRange() chunks.SubChunkRange {
+	provider.mutex.RLock()
+	defer provider.mutex.RUnlock()
+	return provider.subChunkRange
+}
+
 // completeRequest completes the given request, executing its function.
 func (provider *ChunkProvider) completeRequest(request ChunkRequest) {
 	var chunk, ok = provider.GetChunk(request.x, request.z)
@@ -105,7 +261,12 @@ func (provider *ChunkProvider) completeRequest(request ChunkRequest) {
 
 // GenerateChunk generates a NewChunkProvider chunk at the given chunk X and Z.
 func (provider *ChunkProvider) GenerateChunk(x, z int32) {
+	var start = time.Now()
 	var chunk = provider.generator.GenerateNewChunk(x, z)
+	if subChunkRange := provider.GetSubChunkRange(); chunk.GetSubChunkRange() != subChunkRange {
+		chunk.SetSubChunkRange(subChunkRange)
+	}
+	provider.reportSlow("generation", provider.SlowThresholds.Generation, start, x, z)
 	provider.SetChunk(x, z, chunk)
 }
 
@@ -119,4 +280,4 @@ func (provider *ChunkProvider) GetChunkXZ(hash int) (int, int) {
 	x := hash >> 32
 	z := (hash & 0xffffffff) << 32 >> 32
 	return x, z
-}
\ No newline at end of file
+}
@@ -0,0 +1,39 @@
+package providers
+
+import (
+	"github.com/irmine/gomine/text"
+	"time"
+)
+
+// SlowThresholds holds the durations after which chunk operations are considered slow.
+// Slow operations get passed to the SlowFunction of the provider with the coordinates of the chunk they happened in.
+// Operations on whole regions get passed with the coordinates of the first chunk of the region, the one with the lowest X and Z.
+// A threshold of zero disables reporting for that operation.
+type SlowThresholds struct {
+	Load       time.Duration
+	Generation time.Duration
+	Save       time.Duration
+}
+
+// DefaultSlowThresholds are the slow thresholds every new chunk provider starts with.
+var DefaultSlowThresholds = SlowThresholds{
+	Load:       50 * time.Millisecond,
+	Generation: 100 * time.Millisecond,
+	Save:       250 * time.Millisecond,
+}
+
+// LogSlow logs a slow chunk operation at the given X and Z with the default logger.
+// LogSlow is the SlowFunction every new chunk provider starts with.
+func LogSlow(operation string, x, z int32, duration time.Duration) {
+	text.DefaultLogger.Warning("Slow chunk", operation, "at", x, z, "took", duration.String())
+}
+
+// reportSlow passes the operation to the SlowFunction if it took longer than the threshold since the start time.
+func (provider *ChunkProvider) reportSlow(operation string, threshold time.Duration, start time.Time, x, z int32) {
+	if threshold == 0 {
+		return
+	}
+	if duration := time.Since(start); duration > threshold {
+		provider.SlowFunction(operation, x, z, duration)
+	}
+}
@@ -0,0 +1,115 @@
+package worlds
+
+import (
+	"container/heap"
+	"sync"
+	"sync/atomic"
+)
+
+// ScheduledTask is a task scheduled to run on the tick of a dimension.
+type ScheduledTask struct {
+	function  func()
+	runAt     int64
+	period    int64
+	order     uint64
+	cancelled int32
+}
+
+// Cancel cancels the task, preventing it from running again.
+func (task *ScheduledTask) Cancel() {
+	atomic.StoreInt32(&task.cancelled, 1)
+}
+
+// IsCancelled checks if the task was cancelled.
+func (task *ScheduledTask) IsCancelled() bool {
+	return atomic.LoadInt32(&task.cancelled) == 1
+}
+
+// IsRepeating checks if the task runs repeatedly.
+func (task *ScheduledTask) IsRepeating() bool {
+	return task.period > 0
+}
+
+// taskHeap is a heap of scheduled tasks, ordered by the tick they run at and the order they were scheduled in.
+type taskHeap []*ScheduledTask
+
+func (tasks taskHeap) Len() int { return len(tasks) }
+
+func (tasks taskHeap) Less(i, j int) bool {
+	if tasks[i].runAt == tasks[j].runAt {
+		return tasks[i].order < tasks[j].order
+	}
+	return tasks[i].runAt < tasks[j].runAt
+}
+
+func (tasks taskHeap) Swap(i, j int) { tasks[i], tasks[j] = tasks[j], tasks[i] }
+
+func (tasks *taskHeap) Push(task interface{}) { *tasks = append(*tasks, task.(*ScheduledTask)) }
+
+func (tasks *taskHeap) Pop() interface{} {
+	var old = *tasks
+	var task = old[len(old)-1]
+	*tasks = old[:len(old)-1]
+	return task
+}
+
+// scheduler runs scheduled tasks of a dimension.
+type scheduler struct {
+	mutex sync.Mutex
+	tick  int64
+	order uint64
+	tasks taskHeap
+}
+
+// schedule schedules the function to run after the delay, and every period ticks afterwards if the period is above 0.
+func (scheduler *scheduler) schedule(delay, period int64, function func()) *ScheduledTask {
+	if delay < 1 {
+		delay = 1
+	}
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	scheduler.order++
+	var task = &ScheduledTask{function, scheduler.tick + delay, period, scheduler.order, 0}
+	heap.Push(&scheduler.tasks, task)
+	return task
+}
+
+// run advances the scheduler by a tick and runs all tasks due.
+// Tasks run without the scheduler locked, so they can schedule new tasks.
+func (scheduler *scheduler) run() {
+	scheduler.mutex.Lock()
+	scheduler.tick++
+	var due []*ScheduledTask
+	for len(scheduler.tasks) > 0 && scheduler.tasks[0].runAt <= scheduler.tick {
+		due = append(due, heap.Pop(&scheduler.tasks).(*ScheduledTask))
+	}
+	scheduler.mutex.Unlock()
+
+	for _, task := range due {
+		if task.IsCancelled() {
+			continue
+		}
+		task.function()
+		if task.IsRepeating() && !task.IsCancelled() {
+			scheduler.mutex.Lock()
+			task.runAt += task.period
+			heap.Push(&scheduler.tasks, task)
+			scheduler.mutex.Unlock()
+		}
+	}
+}
+
+// ScheduleTask schedules the function to run on the tick of the dimension after the given amount of ticks.
+// Scheduled tasks run on the goroutine ticking the dimension, so they can safely modify the dimension.
+func (dimension *Dimension) ScheduleTask(delayTicks int64, function func()) *ScheduledTask {
+	return dimension.scheduler.schedule(delayTicks, 0, function)
+}
+
+// ScheduleRepeatingTask schedules the function to run on the tick of the dimension after the given amount of ticks,
+// and every period ticks afterwards until the task is cancelled.
+func (dimension *Dimension) ScheduleRepeatingTask(delayTicks, periodTicks int64, function func()) *ScheduledTask {
+	if periodTicks < 1 {
+		periodTicks = 1
+	}
+	return dimension.scheduler.schedule(delayTicks, periodTicks, function)
+}
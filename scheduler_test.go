@@ -0,0 +1,85 @@
+package worlds
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchedulerOrdering(t *testing.T) {
+	var tests = []struct {
+		name     string
+		delays   []int64
+		ticks    int
+		expected []int
+	}{
+		{"by delay", []int64{3, 1, 2}, 3, []int{1, 2, 0}},
+		{"same tick in scheduling order", []int64{2, 2, 1, 2}, 2, []int{2, 0, 1, 3}},
+		{"minimum delay of one tick", []int64{0, -5, 1}, 1, []int{0, 1, 2}},
+		{"not yet due", []int64{1, 5}, 4, []int{0}},
+	}
+	for _, test := range tests {
+		var scheduler = &scheduler{}
+		var ran []int
+		for i, delay := range test.delays {
+			var i = i
+			scheduler.schedule(delay, 0, func() {
+				ran = append(ran, i)
+			})
+		}
+		for i := 0; i < test.ticks; i++ {
+			scheduler.run()
+		}
+		if !reflect.DeepEqual(ran, test.expected) {
+			t.Errorf("%v: got order %v, expected %v", test.name, ran, test.expected)
+		}
+	}
+}
+
+func TestSchedulerRepeating(t *testing.T) {
+	var scheduler = &scheduler{}
+	var ticks []int64
+	var task *ScheduledTask
+	task = scheduler.schedule(2, 3, func() {
+		ticks = append(ticks, scheduler.tick)
+		if len(ticks) == 3 {
+			task.Cancel()
+		}
+	})
+	for i := 0; i < 20; i++ {
+		scheduler.run()
+	}
+	if expected := []int64{2, 5, 8}; !reflect.DeepEqual(ticks, expected) {
+		t.Errorf("got ticks %v, expected %v", ticks, expected)
+	}
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	var scheduler = &scheduler{}
+	var ran = false
+	scheduler.schedule(1, 0, func() {
+		ran = true
+	}).Cancel()
+	scheduler.run()
+	if ran {
+		t.Error("cancelled task ran")
+	}
+}
+
+func TestSchedulerScheduleWhileRunning(t *testing.T) {
+	var scheduler = &scheduler{}
+	var ran []int64
+	scheduler.schedule(1, 0, func() {
+		// Tasks scheduled by tasks run on a later tick, even with the minimum delay.
+		scheduler.schedule(1, 0, func() {
+			ran = append(ran, scheduler.tick)
+		})
+	})
+	scheduler.run()
+	if len(ran) != 0 {
+		t.Fatalf("task scheduled while running ran on the same tick")
+	}
+	scheduler.run()
+	if expected := []int64{2}; !reflect.DeepEqual(ran, expected) {
+		t.Errorf("got ticks %v, expected %v", ran, expected)
+	}
+}
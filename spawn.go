@@ -0,0 +1,90 @@
+package worlds
+
+import (
+	"errors"
+	"github.com/golang/geo/r3"
+	"github.com/irmine/gonbt"
+	"github.com/irmine/worlds/chunks"
+	"math"
+)
+
+// SafeSpawnRadius is the radius in blocks around a position searched for a safe spawn.
+const SafeSpawnRadius = 32
+
+// DefaultSpawn is the spawn of dimensions that have no spawn set.
+var DefaultSpawn = r3.Vector{X: 0, Y: 64, Z: 0}
+
+// NoSafeSpawn gets returned when no safe spawn could be found in the loaded chunks around a position.
+var NoSafeSpawn = errors.New("no safe spawn found")
+
+// SetSpawn sets the spawn of the dimension to the block at the vector, storing it in the level data of the level.
+// The spawn of the default dimension of the level is also stored as the spawn of the level itself, like Minecraft does.
+func (dimension *Dimension) SetSpawn(vector r3.Vector) {
+	var x, y, z = int32(math.Floor(vector.X)), int32(math.Floor(vector.Y)), int32(math.Floor(vector.Z))
+	var level = dimension.level
+	var isDefault = level.GetDefaultDimension() == dimension
+	level.mutex.Lock()
+	var compounds = []*gonbt.Compound{level.getDimensionData(dimension.name)}
+	if isDefault {
+		compounds = append(compounds, level.getData())
+	}
+	for _, compound := range compounds {
+		compound.SetTag(gonbt.NewInt("SpawnX", x))
+		compound.SetTag(gonbt.NewInt("SpawnY", y))
+		compound.SetTag(gonbt.NewInt("SpawnZ", z))
+	}
+	level.mutex.Unlock()
+}
+
+// GetSpawn returns the spawn of the dimension, stored in the level data of the level.
+// The spawn of the level is used for the default dimension if it has no spawn of its own, and DefaultSpawn otherwise.
+func (dimension *Dimension) GetSpawn() r3.Vector {
+	var level = dimension.level
+	var isDefault = level.GetDefaultDimension() == dimension
+	level.mutex.RLock()
+	defer level.mutex.RUnlock()
+	var compound = level.lookupDimensionData(dimension.name)
+	if (compound == nil || !compound.HasTag("SpawnX")) && isDefault {
+		compound = level.data.GetCompound("Data")
+	}
+	if compound == nil || !compound.HasTag("SpawnX") {
+		return DefaultSpawn
+	}
+	return r3.Vector{
+		X: float64(compound.GetInt("SpawnX", int32(DefaultSpawn.X))),
+		Y: float64(compound.GetInt("SpawnY", int32(DefaultSpawn.Y))),
+		Z: float64(compound.GetInt("SpawnZ", int32(DefaultSpawn.Z))),
+	}
+}
+
+// FindSafeSpawn searches the loaded chunks around the vector for a safe spawn, closest to the vector first.
+// A column is safe if the block below its height map is solid and not liquid, with two non-solid blocks above it.
+// The position returned is the center of the block above the ground. Returns NoSafeSpawn if there was no safe column within SafeSpawnRadius.
+func (dimension *Dimension) FindSafeSpawn(around r3.Vector) (r3.Vector, error) {
+	var centerX, centerZ = int(math.Floor(around.X)), int(math.Floor(around.Z))
+	for radius := 0; radius <= SafeSpawnRadius; radius++ {
+		for offsetX := -radius; offsetX <= radius; offsetX++ {
+			for offsetZ := -radius; offsetZ <= radius; offsetZ++ {
+				// Only the ring at the current radius is checked, as the inner rings were checked before.
+				if abs32(int32(offsetX)) != int32(radius) && abs32(int32(offsetZ)) != int32(radius) {
+					continue
+				}
+				var x, z = centerX + offsetX, centerZ + offsetZ
+				if y, ok := dimension.getSafeSpawnY(x, z); ok {
+					return r3.Vector{X: float64(x) + 0.5, Y: float64(y), Z: float64(z) + 0.5}, nil
+				}
+			}
+		}
+	}
+	return r3.Vector{}, NoSafeSpawn
+}
+
+// getSafeSpawnY returns the Y above the ground of the column at the given world X and Z, and a bool indicating if the column is safe to spawn in.
+func (dimension *Dimension) getSafeSpawnY(x, z int) (int, bool) {
+	var chunk, ok = dimension.GetChunk(int32(x>>4), int32(z>>4))
+	if !ok {
+		return 0, false
+	}
+	var y = int(chunk.GetHeightMapAt(x&15, z&15))
+	return y, chunk.IsSpawnable(x&15, y, z&15, chunks.DefaultSpawnConstraints())
+}
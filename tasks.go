@@ -0,0 +1,93 @@
+package worlds
+
+import (
+	"sync"
+	"time"
+)
+
+// TaskPriority is the priority of deferred work run by the manager.
+type TaskPriority byte
+
+const (
+	// PriorityHigh tasks queued before a tick run that tick, even if the tick budget was exceeded.
+	PriorityHigh TaskPriority = iota
+	// PriorityNormal tasks run if the tick budget allows, before low priority tasks.
+	PriorityNormal
+	// PriorityLow tasks, such as pregeneration and saving, run last if the tick budget allows.
+	PriorityLow
+)
+
+// taskQueue is a queue of deferred tasks, ordered by priority and then by the order they were added in.
+type taskQueue struct {
+	mutex sync.Mutex
+	tasks [PriorityLow + 1][]func()
+}
+
+// add adds the task with the given priority to the queue.
+func (queue *taskQueue) add(priority TaskPriority, task func()) {
+	if priority > PriorityLow {
+		priority = PriorityLow
+	}
+	queue.mutex.Lock()
+	queue.tasks[priority] = append(queue.tasks[priority], task)
+	queue.mutex.Unlock()
+}
+
+// pop removes and returns the first task with the highest priority and its priority, if any.
+// Only tasks with a priority of at most maxPriority are returned.
+func (queue *taskQueue) pop(maxPriority TaskPriority) (func(), TaskPriority, bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	for priority := PriorityHigh; priority <= maxPriority; priority++ {
+		if len(queue.tasks[priority]) == 0 {
+			continue
+		}
+		var task = queue.tasks[priority][0]
+		queue.tasks[priority] = queue.tasks[priority][1:]
+		return task, priority, true
+	}
+	return nil, 0, false
+}
+
+// count returns the amount of tasks in the queue with the given priority.
+func (queue *taskQueue) count(priority TaskPriority) int {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return len(queue.tasks[priority])
+}
+
+// len returns the amount of tasks in the queue.
+func (queue *taskQueue) len() int {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	var count = 0
+	for _, tasks := range queue.tasks {
+		count += len(tasks)
+	}
+	return count
+}
+
+// run runs tasks from the queue until the deadline is reached.
+// High priority tasks queued before the run always run, even after the deadline.
+// High priority tasks added during the run, such as tasks scheduling themselves again,
+// only run before the deadline, so that they cannot keep the run going forever.
+func (queue *taskQueue) run(deadline time.Time) {
+	var highPriority = queue.count(PriorityHigh)
+	for {
+		var maxPriority = PriorityLow
+		if !time.Now().Before(deadline) {
+			if highPriority <= 0 {
+				return
+			}
+			maxPriority = PriorityHigh
+		}
+		var task, priority, ok = queue.pop(maxPriority)
+		if !ok {
+			return
+		}
+		if priority == PriorityHigh {
+			highPriority--
+		}
+		task()
+	}
+}
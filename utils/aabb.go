@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"github.com/golang/geo/r3"
+	"math"
+)
+
+// AABB is an axis aligned bounding box, spanning from its minimum to its maximum corner.
+type AABB struct {
+	Min, Max r3.Vector
+}
+
+// NewAABB returns a new bounding box between the two given corners, in any order.
+func NewAABB(a, b r3.Vector) AABB {
+	return AABB{
+		r3.Vector{X: math.Min(a.X, b.X), Y: math.Min(a.Y, b.Y), Z: math.Min(a.Z, b.Z)},
+		r3.Vector{X: math.Max(a.X, b.X), Y: math.Max(a.Y, b.Y), Z: math.Max(a.Z, b.Z)},
+	}
+}
+
+// Intersects checks if the bounding box overlaps with the other bounding box.
+// Bounding boxes only touching each other do not intersect.
+func (box AABB) Intersects(other AABB) bool {
+	return box.Min.X < other.Max.X && box.Max.X > other.Min.X &&
+		box.Min.Y < other.Max.Y && box.Max.Y > other.Min.Y &&
+		box.Min.Z < other.Max.Z && box.Max.Z > other.Min.Z
+}
+
+// Contains checks if the vector lies within the bounding box.
+func (box AABB) Contains(vector r3.Vector) bool {
+	return vector.X >= box.Min.X && vector.X <= box.Max.X &&
+		vector.Y >= box.Min.Y && vector.Y <= box.Max.Y &&
+		vector.Z >= box.Min.Z && vector.Z <= box.Max.Z
+}
+
+// Offset returns the bounding box moved by the given vector.
+func (box AABB) Offset(vector r3.Vector) AABB {
+	return AABB{box.Min.Add(vector), box.Max.Add(vector)}
+}
+
+// Grow returns the bounding box grown by the given amount in every direction.
+func (box AABB) Grow(amount float64) AABB {
+	var grow = r3.Vector{X: amount, Y: amount, Z: amount}
+	return AABB{box.Min.Sub(grow), box.Max.Add(grow)}
+}
+
+// GetBlockBounds returns the minimum and maximum block coordinates of all blocks intersecting the bounding box, inclusive.
+// The maximum is below the minimum on an axis if the bounding box is flat on that axis.
+func (box AABB) GetBlockBounds() (minX, minY, minZ, maxX, maxY, maxZ int) {
+	return int(math.Floor(box.Min.X)), int(math.Floor(box.Min.Y)), int(math.Floor(box.Min.Z)),
+		int(math.Ceil(box.Max.X)) - 1, int(math.Ceil(box.Max.Y)) - 1, int(math.Ceil(box.Max.Z)) - 1
+}
+
+// Extend returns the bounding box extended by the given vector, in the direction of every component of the vector.
+// It covers all space the bounding box passes through when moved by the vector.
+func (box AABB) Extend(vector r3.Vector) AABB {
+	var extended = box
+	if vector.X < 0 {
+		extended.Min.X += vector.X
+	} else {
+		extended.Max.X += vector.X
+	}
+	if vector.Y < 0 {
+		extended.Min.Y += vector.Y
+	} else {
+		extended.Max.Y += vector.Y
+	}
+	if vector.Z < 0 {
+		extended.Min.Z += vector.Z
+	} else {
+		extended.Max.Z += vector.Z
+	}
+	return extended
+}
+
+// ClipX returns the X offset the other bounding box can move by without intersecting the bounding box,
+// limited to the given offset. The offset is returned unchanged if the boxes do not overlap on the other axes.
+func (box AABB) ClipX(other AABB, offset float64) float64 {
+	if other.Max.Y <= box.Min.Y || other.Min.Y >= box.Max.Y || other.Max.Z <= box.Min.Z || other.Min.Z >= box.Max.Z {
+		return offset
+	}
+	if offset > 0 && other.Max.X <= box.Min.X {
+		return math.Min(offset, box.Min.X-other.Max.X)
+	}
+	if offset < 0 && other.Min.X >= box.Max.X {
+		return math.Max(offset, box.Max.X-other.Min.X)
+	}
+	return offset
+}
+
+// ClipY returns the Y offset the other bounding box can move by without intersecting the bounding box,
+// limited to the given offset. The offset is returned unchanged if the boxes do not overlap on the other axes.
+func (box AABB) ClipY(other AABB, offset float64) float64 {
+	if other.Max.X <= box.Min.X || other.Min.X >= box.Max.X || other.Max.Z <= box.Min.Z || other.Min.Z >= box.Max.Z {
+		return offset
+	}
+	if offset > 0 && other.Max.Y <= box.Min.Y {
+		return math.Min(offset, box.Min.Y-other.Max.Y)
+	}
+	if offset < 0 && other.Min.Y >= box.Max.Y {
+		return math.Max(offset, box.Max.Y-other.Min.Y)
+	}
+	return offset
+}
+
+// ClipZ returns the Z offset the other bounding box can move by without intersecting the bounding box,
+// limited to the given offset. The offset is returned unchanged if the boxes do not overlap on the other axes.
+func (box AABB) ClipZ(other AABB, offset float64) float64 {
+	if other.Max.X <= box.Min.X || other.Min.X >= box.Max.X || other.Max.Y <= box.Min.Y || other.Min.Y >= box.Max.Y {
+		return offset
+	}
+	if offset > 0 && other.Max.Z <= box.Min.Z {
+		return math.Min(offset, box.Min.Z-other.Max.Z)
+	}
+	if offset < 0 && other.Min.Z >= box.Max.Z {
+		return math.Max(offset, box.Max.Z-other.Min.Z)
+	}
+	return offset
+}
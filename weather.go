@@ -0,0 +1,271 @@
+package worlds
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/blocks"
+	"github.com/irmine/worlds/chunks"
+	"math"
+)
+
+// LevelEvent is an event in a dimension played by viewers, such as rain starting.
+type LevelEvent int32
+
+const (
+	EventStartRain    LevelEvent = 3001
+	EventStartThunder LevelEvent = 3002
+	EventStopRain     LevelEvent = 3003
+	EventStopThunder  LevelEvent = 3004
+)
+
+// LevelEventViewer is a viewer that can be sent level events.
+// Viewers not implementing this interface do not see the weather.
+type LevelEventViewer interface {
+	chunks.Viewer
+	SendLevelEvent(event LevelEvent, position r3.Vector, data int32)
+}
+
+// LightningFunction returns a new lightning bolt entity, to be added to the dimension at the given position.
+// Returns nil if no lightning bolt entity should be added.
+type LightningFunction func(position r3.Vector) chunks.ChunkEntity
+
+const (
+	// MinClearTime and MaxClearTime are the bounds of the amount of ticks rain or thunder stays away.
+	MinClearTime = 12000
+	MaxClearTime = 180000
+	// MinRainTime and MaxRainTime are the bounds of the amount of ticks it rains.
+	MinRainTime = 12000
+	MaxRainTime = 24000
+	// MinThunderTime and MaxThunderTime are the bounds of the amount of ticks it thunders.
+	MinThunderTime = 3600
+	MaxThunderTime = 15600
+	// LightningChance is the chance of one in LightningChance every tick for lightning to strike in a loaded chunk while it thunders.
+	LightningChance = 100000
+	// RainIntensity is the intensity of rain sent to viewers when rain starts.
+	RainIntensity = 65535
+)
+
+// fireId is the block ID of fire, placed by lightning.
+const fireId = 51
+
+// weather is the rain and thunder state of a dimension.
+// Thunder only happens while it rains as well.
+type weather struct {
+	raining     bool
+	rainTime    int
+	thundering  bool
+	thunderTime int
+}
+
+// HasWeather checks if the dimension has weather, which only dimensions with sky light have.
+func (dimension *Dimension) HasWeather() bool {
+	return dimension.lightEngine.HasSky()
+}
+
+// IsRaining checks if it rains in the dimension.
+func (dimension *Dimension) IsRaining() bool {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.weather.raining
+}
+
+// IsThundering checks if it thunders in the dimension, which is only the case while it rains as well.
+func (dimension *Dimension) IsThundering() bool {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.weather.raining && dimension.weather.thundering
+}
+
+// IsRainingAt checks if it rains at the given vector, which is the case if it rains and no blocks are above the vector.
+func (dimension *Dimension) IsRainingAt(vector r3.Vector) bool {
+	if !dimension.IsRaining() {
+		return false
+	}
+	var x, z = int(math.Floor(vector.X)), int(math.Floor(vector.Z))
+	var chunk, ok = dimension.GetChunk(int32(x>>4), int32(z>>4))
+	return ok && math.Floor(vector.Y) > float64(chunk.GetHighestBlockY(x&15, z&15))
+}
+
+// GetRainTime returns the amount of ticks until rain starts or stops.
+func (dimension *Dimension) GetRainTime() int {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.weather.rainTime
+}
+
+// GetThunderTime returns the amount of ticks until thunder starts or stops.
+func (dimension *Dimension) GetThunderTime() int {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.weather.thunderTime
+}
+
+// SetRaining starts or stops rain in the dimension for the given amount of ticks, after which the weather cycle continues.
+// A random duration is picked if the duration is 0 or lower. Viewers get notified if the rain started or stopped.
+func (dimension *Dimension) SetRaining(raining bool, duration int) {
+	dimension.mutex.Lock()
+	if duration <= 0 {
+		duration = dimension.randomWeatherTime(raining, MinRainTime, MaxRainTime)
+	}
+	var changed = dimension.weather.raining != raining
+	var thundering = dimension.weather.thundering
+	dimension.weather.raining, dimension.weather.rainTime = raining, duration
+	dimension.mutex.Unlock()
+	if !changed {
+		return
+	}
+	if raining {
+		dimension.BroadcastLevelEvent(EventStartRain, r3.Vector{}, RainIntensity)
+		if thundering {
+			dimension.BroadcastLevelEvent(EventStartThunder, r3.Vector{}, RainIntensity)
+		}
+		return
+	}
+	dimension.BroadcastLevelEvent(EventStopRain, r3.Vector{}, 0)
+	if thundering {
+		dimension.BroadcastLevelEvent(EventStopThunder, r3.Vector{}, 0)
+	}
+}
+
+// SetThundering starts or stops thunder in the dimension for the given amount of ticks, after which the weather cycle continues.
+// A random duration is picked if the duration is 0 or lower. Thunder only happens while it rains as well.
+func (dimension *Dimension) SetThundering(thundering bool, duration int) {
+	dimension.mutex.Lock()
+	if duration <= 0 {
+		duration = dimension.randomWeatherTime(thundering, MinThunderTime, MaxThunderTime)
+	}
+	var changed = dimension.weather.thundering != thundering && dimension.weather.raining
+	dimension.weather.thundering, dimension.weather.thunderTime = thundering, duration
+	dimension.mutex.Unlock()
+	if !changed {
+		return
+	}
+	if thundering {
+		dimension.BroadcastLevelEvent(EventStartThunder, r3.Vector{}, RainIntensity)
+		return
+	}
+	dimension.BroadcastLevelEvent(EventStopThunder, r3.Vector{}, 0)
+}
+
+// randomWeatherTime returns a random amount of ticks for weather, between the given bounds if active, or between the clear bounds otherwise.
+// The dimension must be locked while calling it.
+func (dimension *Dimension) randomWeatherTime(active bool, min, max int) int {
+	if !active {
+		min, max = MinClearTime, MaxClearTime
+	}
+	return min + dimension.random.Intn(max-min+1)
+}
+
+// countWeatherTime counts down the weather time, and returns true if the weather time is up and the weather should toggle.
+// Weather without time left, such as that of new dimensions, gets a random time for its current state first.
+// The dimension must be locked while calling it.
+func (dimension *Dimension) countWeatherTime(time *int, active bool, min, max int) bool {
+	if *time <= 0 {
+		*time = dimension.randomWeatherTime(active, min, max)
+		return false
+	}
+	*time--
+	return *time == 0
+}
+
+// tickWeather counts down the rain and thunder time, toggling rain and thunder once their time is up, and strikes lightning while it thunders.
+// Rain and thunder only change by themselves if the doWeatherCycle game rule is enabled.
+func (dimension *Dimension) tickWeather() {
+	if !dimension.HasWeather() {
+		return
+	}
+	if dimension.level.GetBoolGameRule(GameRuleDoWeatherCycle, true) {
+		dimension.mutex.Lock()
+		var raining, thundering = dimension.weather.raining, dimension.weather.thundering
+		var toggleRain = dimension.countWeatherTime(&dimension.weather.rainTime, raining, MinRainTime, MaxRainTime)
+		var toggleThunder = dimension.countWeatherTime(&dimension.weather.thunderTime, thundering, MinThunderTime, MaxThunderTime)
+		dimension.mutex.Unlock()
+		if toggleRain {
+			dimension.SetRaining(!raining, 0)
+		}
+		if toggleThunder {
+			dimension.SetThundering(!thundering, 0)
+		}
+	}
+	if dimension.IsThundering() {
+		dimension.strikeRandomLightning()
+	}
+}
+
+// strikeRandomLightning strikes lightning at the highest block of a random column in loaded chunks with a chance of one in LightningChance per chunk.
+func (dimension *Dimension) strikeRandomLightning() {
+	for _, chunk := range dimension.chunkProvider.GetLoadedChunks() {
+		dimension.mutex.Lock()
+		var strike = dimension.random.Intn(LightningChance) == 0
+		var x, z = dimension.random.Intn(16), dimension.random.Intn(16)
+		dimension.mutex.Unlock()
+		if !strike {
+			continue
+		}
+		var y = chunk.GetHighestBlockY(x, z) + 1
+		dimension.StrikeLightning(r3.Vector{X: float64(int(chunk.X)<<4|x) + 0.5, Y: float64(y), Z: float64(int(chunk.Z)<<4|z) + 0.5})
+	}
+}
+
+// SetLightningFunction sets the function creating lightning bolt entities for lightning striking in the dimension.
+// Lightning strikes without entity if nil.
+func (dimension *Dimension) SetLightningFunction(function LightningFunction) {
+	dimension.mutex.Lock()
+	dimension.lightningFunction = function
+	dimension.mutex.Unlock()
+}
+
+// GetLightningFunction returns the function creating lightning bolt entities for lightning striking in the dimension.
+func (dimension *Dimension) GetLightningFunction() LightningFunction {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.lightningFunction
+}
+
+// StrikeLightning strikes lightning at the given vector, adding a lightning bolt entity using the lightning function of the dimension.
+// Fire is placed at the vector if the doFireTick game rule is enabled, the difficulty is at least normal,
+// and the vector is air on top of a solid block.
+func (dimension *Dimension) StrikeLightning(vector r3.Vector) {
+	if function := dimension.GetLightningFunction(); function != nil {
+		if bolt := function(vector); bolt != nil {
+			dimension.AddEntity(bolt, vector)
+		}
+	}
+	if !dimension.level.GetBoolGameRule(GameRuleDoFireTick, true) || dimension.level.GetDifficulty() < DifficultyNormal {
+		return
+	}
+	var x, y, z = int(math.Floor(vector.X)), int(math.Floor(vector.Y)), int(math.Floor(vector.Z))
+	if id, _, ok := dimension.getLoadedBlock(x, y, z); !ok || id != 0 {
+		return
+	}
+	if below, _, ok := dimension.getLoadedBlock(x, y-1, z); !ok || !chunks.IsSolidBlock(below) {
+		return
+	}
+	dimension.SetBlockAt(r3.Vector{X: float64(x), Y: float64(y), Z: float64(z)}, blocks.NewLegacyBlock(fireId, 0, nil))
+}
+
+// BroadcastLevelEvent sends the level event to all viewers of the dimension supporting level events.
+func (dimension *Dimension) BroadcastLevelEvent(event LevelEvent, position r3.Vector, data int32) {
+	dimension.mutex.RLock()
+	var viewers = make([]chunks.Viewer, 0, len(dimension.viewers))
+	for _, viewer := range dimension.viewers {
+		viewers = append(viewers, viewer)
+	}
+	dimension.mutex.RUnlock()
+	for _, viewer := range viewers {
+		if eventViewer, ok := viewer.(LevelEventViewer); ok {
+			eventViewer.SendLevelEvent(event, position, data)
+		}
+	}
+}
+
+// sendWeather sends the current weather of the dimension to the viewer, if it supports level events.
+func (dimension *Dimension) sendWeather(viewer chunks.Viewer) {
+	var eventViewer, ok = viewer.(LevelEventViewer)
+	if !ok || !dimension.IsRaining() {
+		return
+	}
+	eventViewer.SendLevelEvent(EventStartRain, r3.Vector{}, RainIntensity)
+	if dimension.IsThundering() {
+		eventViewer.SendLevelEvent(EventStartThunder, r3.Vector{}, RainIntensity)
+	}
+}
@@ -0,0 +1,172 @@
+package worlds
+
+import (
+	"errors"
+	"github.com/golang/geo/r3"
+	"github.com/irmine/worlds/chunks"
+	"math"
+	"sync"
+)
+
+// OutsideBorder gets returned when blocks are changed outside of the world border of a dimension.
+var OutsideBorder = errors.New("position is outside of the world border")
+
+// BorderCrossFunction gets called when an entity tries to move from the from position to the to position outside of the world border.
+// It returns the position the entity moves to instead, such as a position bounced back inside, or false if the movement is cancelled.
+// Entities can be damaged by the function as well.
+type BorderCrossFunction func(entity chunks.ChunkEntity, from, to r3.Vector) (r3.Vector, bool)
+
+// WorldBorder is a square border around a center, which entities cannot move out of and blocks cannot be set outside of.
+// The border can shrink or grow over time.
+type WorldBorder struct {
+	mutex            sync.RWMutex
+	centerX, centerZ float64
+	radius           float64
+
+	targetRadius float64
+	resizeTicks  int64
+
+	crossFunction BorderCrossFunction
+}
+
+// NewWorldBorder returns a new world border around the center at the given X and Z.
+// The radius is half the width of the square inside of the border.
+func NewWorldBorder(centerX, centerZ, radius float64) *WorldBorder {
+	return &WorldBorder{sync.RWMutex{}, centerX, centerZ, radius, radius, 0, nil}
+}
+
+// GetCenter returns the X and Z of the center of the world border.
+func (border *WorldBorder) GetCenter() (float64, float64) {
+	border.mutex.RLock()
+	defer border.mutex.RUnlock()
+	return border.centerX, border.centerZ
+}
+
+// SetCenter sets the X and Z of the center of the world border.
+func (border *WorldBorder) SetCenter(centerX, centerZ float64) {
+	border.mutex.Lock()
+	border.centerX, border.centerZ = centerX, centerZ
+	border.mutex.Unlock()
+}
+
+// GetRadius returns the current radius of the world border.
+func (border *WorldBorder) GetRadius() float64 {
+	border.mutex.RLock()
+	defer border.mutex.RUnlock()
+	return border.radius
+}
+
+// SetRadius sets the radius of the world border, stopping it from resizing over time.
+func (border *WorldBorder) SetRadius(radius float64) {
+	border.mutex.Lock()
+	border.radius, border.targetRadius, border.resizeTicks = radius, radius, 0
+	border.mutex.Unlock()
+}
+
+// ResizeTo shrinks or grows the world border to the target radius over the given amount of ticks.
+// The world border is resized at once if ticks is 0 or lower.
+func (border *WorldBorder) ResizeTo(radius float64, ticks int64) {
+	if ticks <= 0 {
+		border.SetRadius(radius)
+		return
+	}
+	border.mutex.Lock()
+	border.targetRadius, border.resizeTicks = radius, ticks
+	border.mutex.Unlock()
+}
+
+// GetTargetRadius returns the radius the world border is resizing to, and the amount of ticks left until it reaches it.
+func (border *WorldBorder) GetTargetRadius() (float64, int64) {
+	border.mutex.RLock()
+	defer border.mutex.RUnlock()
+	return border.targetRadius, border.resizeTicks
+}
+
+// SetCrossFunction sets the function called when an entity tries to move outside of the world border.
+// Entities are not able to move outside of the world border if nil.
+func (border *WorldBorder) SetCrossFunction(function BorderCrossFunction) {
+	border.mutex.Lock()
+	border.crossFunction = function
+	border.mutex.Unlock()
+}
+
+// GetCrossFunction returns the function called when an entity tries to move outside of the world border.
+func (border *WorldBorder) GetCrossFunction() BorderCrossFunction {
+	border.mutex.RLock()
+	defer border.mutex.RUnlock()
+	return border.crossFunction
+}
+
+// GetDistanceOutside returns how far the vector is outside of the world border horizontally, which is 0 or lower for vectors inside of it.
+func (border *WorldBorder) GetDistanceOutside(vector r3.Vector) float64 {
+	border.mutex.RLock()
+	defer border.mutex.RUnlock()
+	return math.Max(math.Abs(vector.X-border.centerX), math.Abs(vector.Z-border.centerZ)) - border.radius
+}
+
+// IsWithin checks if the vector is inside of the world border.
+func (border *WorldBorder) IsWithin(vector r3.Vector) bool {
+	return border.GetDistanceOutside(vector) <= 0
+}
+
+// tick moves the radius of the world border a tick closer to its target radius, if it is resizing.
+func (border *WorldBorder) tick() {
+	border.mutex.Lock()
+	if border.resizeTicks > 0 {
+		border.radius += (border.targetRadius - border.radius) / float64(border.resizeTicks)
+		border.resizeTicks--
+	}
+	border.mutex.Unlock()
+}
+
+// SetWorldBorder sets the world border of the dimension. The dimension has no world border if nil.
+func (dimension *Dimension) SetWorldBorder(border *WorldBorder) {
+	dimension.mutex.Lock()
+	dimension.worldBorder = border
+	dimension.mutex.Unlock()
+}
+
+// GetWorldBorder returns the world border of the dimension, or nil if the dimension has none.
+func (dimension *Dimension) GetWorldBorder() *WorldBorder {
+	dimension.mutex.RLock()
+	defer dimension.mutex.RUnlock()
+	return dimension.worldBorder
+}
+
+// IsWithinBorder checks if the vector is inside of the world border of the dimension.
+// Every vector is inside of the world border if the dimension has none.
+func (dimension *Dimension) IsWithinBorder(vector r3.Vector) bool {
+	var border = dimension.GetWorldBorder()
+	return border == nil || border.IsWithin(vector)
+}
+
+// isBlockWithinBorder checks if the block at the given world position is inside of the world border of the dimension.
+func (dimension *Dimension) isBlockWithinBorder(x, y, z int) bool {
+	return dimension.IsWithinBorder(r3.Vector{X: float64(x), Y: float64(y), Z: float64(z)})
+}
+
+// CrossBorder checks if the entity may move from the from position to the to position, and returns the position it moves to.
+// Movement ending outside of the world border is passed to the cross function of the border, or cancelled if it has none.
+// Entities already outside of the world border, such as after it shrunk, may always move towards it.
+// Returns false if the movement was cancelled.
+func (dimension *Dimension) CrossBorder(entity chunks.ChunkEntity, from, to r3.Vector) (r3.Vector, bool) {
+	var border = dimension.GetWorldBorder()
+	if border == nil {
+		return to, true
+	}
+	var outside = border.GetDistanceOutside(to)
+	if outside <= 0 || outside < border.GetDistanceOutside(from) {
+		return to, true
+	}
+	if function := border.GetCrossFunction(); function != nil {
+		return function(entity, from, to)
+	}
+	return from, false
+}
+
+// tickWorldBorder resizes the world border of the dimension over time.
+func (dimension *Dimension) tickWorldBorder() {
+	if border := dimension.GetWorldBorder(); border != nil {
+		border.tick()
+	}
+}